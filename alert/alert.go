@@ -0,0 +1,243 @@
+// Package alert raises alerts from declarative conditions over the events of a bus,
+// for small monitoring built on the bus itself:
+//
+//	engine := alert.New(bus, []alert.Rule{
+//		{Name: "payment-failures", Severity: alert.Critical, Pattern: "payment.failed", Condition: alert.RateAbove, Count: 10, Window: time.Minute},
+//		{Name: "no-heartbeat", Severity: alert.Warning, Pattern: "worker.heartbeat", Condition: alert.Absent, Window: 30 * time.Second},
+//	})
+//	bus.Register("*", engine)
+//	bus.Mount(engine)
+//
+// Alerts are emitted as "alert.raised" and "alert.resolved" events with an Alert payload,
+// once per transition, so a firing condition doesn't flood the bus.
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Event types of the alerts.
+const (
+	Raised   = "alert.raised"
+	Resolved = "alert.resolved"
+)
+
+// Severity is the severity of an alert.
+type Severity string
+
+// Severities of the alerts.
+const (
+	Info     Severity = "info"
+	Warning  Severity = "warning"
+	Critical Severity = "critical"
+)
+
+// Condition is the kind of condition of a rule.
+type Condition int
+
+const (
+	// RateAbove raises the alert while at least Count matching events were emitted within the Window.
+	// With a Count of 1 and a Where predicate, it alerts on single events with a suspicious payload.
+	RateAbove Condition = iota
+	// Absent raises the alert when no matching event was emitted within the Window,
+	// and resolves it at the next one.
+	Absent
+)
+
+// Rule declares an alert.
+type Rule struct {
+	// Name identifies the alert.
+	Name string
+	// Severity is the severity of the alert, Warning by default.
+	Severity Severity
+	// Pattern selects the events, see eventify.NewMatcher. Alert events are never matched.
+	Pattern string
+	// Where, if set, further selects the events, e.g. by their payload.
+	Where     func(event eventify.Event) bool
+	Condition Condition
+	// Count is the number of events of a RateAbove condition, 1 by default.
+	Count int
+	// Window is the duration the condition is evaluated over.
+	Window time.Duration
+	// Message describes the alert.
+	Message string
+}
+
+// Alert is the payload of the alert events.
+type Alert struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message,omitempty"`
+	// Since is when the alert was raised.
+	Since time.Time `json:"since"`
+	// At is when the alert was raised or resolved.
+	At time.Time `json:"at"`
+}
+
+// Engine is a listener evaluating the rules on the events, and a module evaluating them over time.
+type Engine struct {
+	bus      *eventify.Eventify
+	now      func() time.Time
+	interval time.Duration
+	mutex    sync.Mutex
+	rules    []*rule
+}
+
+type rule struct {
+	Rule
+	matcher *eventify.Matcher
+	// seen are the times of the last Count matching events, oldest first.
+	seen []time.Time
+	// last is the time of the last matching event, or of the start of the Engine.
+	last  time.Time
+	since time.Time
+}
+
+// OptionFunc is a function that configures an Engine.
+type OptionFunc func(*Engine)
+
+// WithClock sets the clock of the Engine, time.Now by default.
+func WithClock(now func() time.Time) OptionFunc {
+	return func(e *Engine) {
+		e.now = now
+	}
+}
+
+// WithInterval sets how often Run evaluates the rules, every second by default.
+func WithInterval(interval time.Duration) OptionFunc {
+	return func(e *Engine) {
+		e.interval = interval
+	}
+}
+
+// New creates a new Engine emitting the alerts of the rules on the bus.
+// Absent conditions start counting from now.
+func New(bus *eventify.Eventify, rules []Rule, opts ...OptionFunc) *Engine {
+	e := &Engine{bus: bus, now: time.Now, interval: time.Second}
+	for _, opt := range opts {
+		opt(e)
+	}
+	start := e.now()
+	for _, r := range rules {
+		if r.Severity == "" {
+			r.Severity = Warning
+		}
+		if r.Count <= 0 {
+			r.Count = 1
+		}
+		e.rules = append(e.rules, &rule{Rule: r, matcher: eventify.NewMatcher(r.Pattern), last: start})
+	}
+	return e
+}
+
+// Name implements eventify.Namable.
+func (e *Engine) Name() string {
+	return "alert.engine"
+}
+
+// Handle records the event for the rules matching it and emits the resulting transitions.
+func (e *Engine) Handle(event eventify.Event) error {
+	if strings.HasPrefix(event.Type(), "alert.") {
+		return nil
+	}
+	e.mutex.Lock()
+	now := e.now()
+	var transitions []eventify.Event
+	for _, r := range e.rules {
+		if !r.matcher.Match(event.Type()) || (r.Where != nil && !r.Where(event)) {
+			continue
+		}
+		r.last = now
+		r.seen = append(r.seen, now)
+		if len(r.seen) > r.Count {
+			r.seen = r.seen[len(r.seen)-r.Count:]
+		}
+		transitions = append(transitions, r.evaluate(now)...)
+	}
+	e.mutex.Unlock()
+	e.emit(transitions)
+	return nil
+}
+
+// Check evaluates the rules at the current time and emits the resulting transitions,
+// raising Absent conditions and resolving RateAbove ones once their window has passed.
+func (e *Engine) Check() {
+	e.mutex.Lock()
+	now := e.now()
+	var transitions []eventify.Event
+	for _, r := range e.rules {
+		transitions = append(transitions, r.evaluate(now)...)
+	}
+	e.mutex.Unlock()
+	e.emit(transitions)
+}
+
+// Active returns the alerts currently raised.
+func (e *Engine) Active() []Alert {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	alerts := []Alert{}
+	for _, r := range e.rules {
+		if !r.since.IsZero() {
+			alerts = append(alerts, r.alert(r.since))
+		}
+	}
+	return alerts
+}
+
+// Run evaluates the rules every interval until the context is done.
+func (e *Engine) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			e.Check()
+		}
+	}
+}
+
+func (e *Engine) emit(transitions []eventify.Event) {
+	for _, event := range transitions {
+		e.bus.Emit(event)
+	}
+}
+
+// evaluate updates the state of the rule at the time and returns the alert event of its transition, if any.
+func (r *rule) evaluate(now time.Time) []eventify.Event {
+	var firing bool
+	switch r.Condition {
+	case Absent:
+		firing = now.Sub(r.last) >= r.Window
+	default:
+		firing = len(r.seen) == r.Count && now.Sub(r.seen[0]) < r.Window
+	}
+	raised := !r.since.IsZero()
+	switch {
+	case firing && !raised:
+		r.since = now
+		return []eventify.Event{newAlertEvent(Raised, r.alert(now))}
+	case !firing && raised:
+		alert := r.alert(now)
+		r.since = time.Time{}
+		return []eventify.Event{newAlertEvent(Resolved, alert)}
+	}
+	return nil
+}
+
+func newAlertEvent(eventType string, alert Alert) eventify.Event {
+	payload, _ := json.Marshal(alert)
+	return eventify.NewEvent(eventType, payload)
+}
+
+func (r *rule) alert(at time.Time) Alert {
+	return Alert{Rule: r.Name, Severity: r.Severity, Message: r.Message, Since: r.since, At: at}
+}
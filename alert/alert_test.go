@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time               { return c.now }
+func (c *clock) Advance(d time.Duration)      { c.now = c.now.Add(d) }
+func transition(a Alert, state string) string { return state + ":" + a.Rule }
+
+func setup(t *testing.T, rules ...Rule) (*eventify.Eventify, *Engine, *clock, *[]string) {
+	t.Helper()
+	c := &clock{now: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	bus := eventify.NewEventify()
+	engine := New(bus, rules, WithClock(c.Now))
+	bus.Register("*", engine)
+	transitions := &[]string{}
+	bus.Register("alert.*", eventify.NewListener(func(event eventify.Event) error {
+		var a Alert
+		if err := json.Unmarshal(event.Payload(), &a); err != nil {
+			return err
+		}
+		*transitions = append(*transitions, transition(a, event.Type()))
+		return nil
+	}))
+	return bus, engine, c, transitions
+}
+
+func TestEngine_RateAbove(t *testing.T) {
+	bus, engine, c, transitions := setup(t, Rule{Name: "failures", Pattern: "payment.failed", Condition: RateAbove, Count: 3, Window: time.Minute})
+
+	for range 2 {
+		bus.EmitBy("payment.failed", nil)
+		c.Advance(10 * time.Second)
+	}
+	bus.EmitBy("payment.succeeded", nil)
+	assert.Empty(t, *transitions)
+
+	bus.EmitBy("payment.failed", nil)
+	bus.EmitBy("payment.failed", nil)
+	assert.Equal(t, []string{"alert.raised:failures"}, *transitions)
+	assert.Equal(t, []Alert{{Rule: "failures", Severity: Warning, Since: c.now, At: c.now}}, engine.Active())
+
+	c.Advance(49 * time.Second)
+	engine.Check()
+	assert.Len(t, *transitions, 1)
+
+	c.Advance(time.Second)
+	engine.Check()
+	assert.Equal(t, []string{"alert.raised:failures", "alert.resolved:failures"}, *transitions)
+	assert.Empty(t, engine.Active())
+}
+
+func TestEngine_Where(t *testing.T) {
+	bus, _, _, transitions := setup(t, Rule{
+		Name:      "large-order",
+		Severity:  Critical,
+		Pattern:   "order.*",
+		Where:     func(event eventify.Event) bool { return string(event.Payload()) == "large" },
+		Condition: RateAbove,
+		Window:    time.Minute,
+	})
+
+	bus.EmitBy("order.created", "small")
+	assert.Empty(t, *transitions)
+	bus.EmitBy("order.created", "large")
+	bus.EmitBy("order.created", "large")
+	assert.Equal(t, []string{"alert.raised:large-order"}, *transitions)
+}
+
+func TestEngine_Absent(t *testing.T) {
+	bus, engine, c, transitions := setup(t, Rule{Name: "heartbeat", Pattern: "worker.heartbeat", Condition: Absent, Window: 30 * time.Second})
+
+	c.Advance(20 * time.Second)
+	engine.Check()
+	bus.EmitBy("worker.heartbeat", nil)
+	c.Advance(29 * time.Second)
+	engine.Check()
+	assert.Empty(t, *transitions)
+
+	c.Advance(time.Second)
+	engine.Check()
+	engine.Check()
+	assert.Equal(t, []string{"alert.raised:heartbeat"}, *transitions)
+
+	bus.EmitBy("worker.heartbeat", nil)
+	assert.Equal(t, []string{"alert.raised:heartbeat", "alert.resolved:heartbeat"}, *transitions)
+}
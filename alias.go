@@ -0,0 +1,77 @@
+package eventify
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// aliases maps deprecated event types to their new names and counts their use.
+type aliases struct {
+	mutex    sync.RWMutex
+	newNames map[string]string
+	oldNames map[string][]string
+	counts   map[string]*atomic.Uint64
+}
+
+func newAliases() *aliases {
+	return &aliases{
+		newNames: map[string]string{},
+		oldNames: map[string][]string{},
+		counts:   map[string]*atomic.Uint64{},
+	}
+}
+
+// Alias declares that the event type oldType was renamed to newType.
+// Events emitted with either name are delivered to the listeners of both, so producers and
+// consumers can move to the new name independently. Emits of the old name are counted in
+// DeprecatedEmits and logged.
+func (e *Eventify) Alias(oldType, newType string) {
+	oldType, newType = e._Normalize(oldType), e._Normalize(newType)
+	e.aliases.mutex.Lock()
+	defer e.aliases.mutex.Unlock()
+	if previous, ok := e.aliases.newNames[oldType]; ok {
+		olds := e.aliases.oldNames[previous]
+		for i, old := range olds {
+			if old == oldType {
+				e.aliases.oldNames[previous] = append(olds[:i:i], olds[i+1:]...)
+				break
+			}
+		}
+	}
+	e.aliases.newNames[oldType] = newType
+	e.aliases.oldNames[newType] = append(e.aliases.oldNames[newType], oldType)
+	if _, ok := e.aliases.counts[oldType]; !ok {
+		e.aliases.counts[oldType] = &atomic.Uint64{}
+	}
+	e.log.Debug("eventify alias", "old_type", oldType, "new_type", newType)
+}
+
+// DeprecatedEmits returns the number of emits of every aliased old event type.
+func (e *Eventify) DeprecatedEmits() map[string]uint64 {
+	e.aliases.mutex.RLock()
+	defer e.aliases.mutex.RUnlock()
+	counts := map[string]uint64{}
+	for oldType, count := range e.aliases.counts {
+		counts[oldType] = count.Load()
+	}
+	return counts
+}
+
+// _Aliases returns the event type and all the types it is aliased with.
+func (e *Eventify) _Aliases(eventType string) []string {
+	e.aliases.mutex.RLock()
+	defer e.aliases.mutex.RUnlock()
+	types := []string{eventType}
+	if newType, ok := e.aliases.newNames[eventType]; ok {
+		e.aliases.counts[eventType].Add(1)
+		e.log.Debug("eventify deprecated event type emitted", "event", eventType, "new_type", newType)
+		types = append(types, newType)
+		eventType = newType
+	}
+	for _, oldType := range e.aliases.oldNames[eventType] {
+		if oldType != types[0] {
+			types = append(types, oldType)
+		}
+	}
+	return types
+}
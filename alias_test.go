@@ -0,0 +1,55 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_Alias(t *testing.T) {
+	e := New()
+	var oldListener, newListener, wildcard int
+	e.Register("user.signup", NewListener(func(event Event) error {
+		oldListener++
+		return nil
+	}))
+	e.Register("user.registered", NewListener(func(event Event) error {
+		newListener++
+		return nil
+	}))
+	e.Register("user.*", NewListener(func(event Event) error {
+		wildcard++
+		return nil
+	}))
+	e.Alias("user.signup", "user.registered")
+
+	e.EmitBy("user.signup", nil)
+	e.EmitBy("user.registered", nil)
+
+	assert.Equal(t, 2, oldListener)
+	assert.Equal(t, 2, newListener)
+	assert.Equal(t, 2, wildcard, "listeners matching both names should receive each event once")
+	assert.Equal(t, map[string]uint64{"user.signup": 1}, e.DeprecatedEmits())
+}
+
+func TestEventify_AliasChange(t *testing.T) {
+	e := New()
+	var received []string
+	e.Register("*", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+	var legacy int
+	e.Register("a.old", NewListener(func(event Event) error {
+		legacy++
+		return nil
+	}))
+
+	e.Alias("a.old", "a.new")
+	e.Alias("a.old", "a.newer")
+	e.EmitBy("a.new", nil)
+	e.EmitBy("a.newer", nil)
+
+	assert.Equal(t, 1, legacy, "only the current alias should be delivered to the old listeners")
+	assert.Equal(t, []string{"a.new", "a.newer"}, received)
+}
@@ -0,0 +1,10 @@
+//go:build !eventify_debug
+
+package eventify
+
+// allocTracking reports whether the allocations of guarded invocations are tracked, see WithAllocLimit.
+const allocTracking = false
+
+func allocatedBytes() uint64 {
+	return 0
+}
@@ -0,0 +1,15 @@
+//go:build eventify_debug
+
+package eventify
+
+import "runtime/metrics"
+
+// allocTracking reports whether the allocations of guarded invocations are tracked, see WithAllocLimit.
+const allocTracking = true
+
+// allocatedBytes returns the cumulative number of bytes allocated on the heap by the process.
+func allocatedBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,60 @@
+package eventify
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// AnyEvent is an event carrying the original Go value of its payload, for in-process dispatch
+// between producers and consumers sharing types. Listeners read it with Value or DecodedPayload
+// without a marshal/unmarshal round-trip; the JSON payload is only marshaled if Payload is called.
+// Like the payload, the value is shared by every listener and must be treated as read-only.
+type AnyEvent interface {
+	Event
+	Value() any
+}
+
+// NewAnyEvent creates a new event with the specified type carrying the value.
+func NewAnyEvent(eventType string, value any) AnyEvent {
+	return &anyEvent{
+		eventType: eventType,
+		value:     value,
+	}
+}
+
+// EmitAny creates and emits a new AnyEvent with the specified type carrying the payload as is.
+func (e *Eventify) EmitAny(eventType string, payload any) {
+	e._Emit(NewAnyEvent(e._Normalize(eventType), payload))
+}
+
+type anyEvent struct {
+	DecodeCache
+	eventType string
+	value     any
+	once      sync.Once
+	payload   []byte
+}
+
+func (e *anyEvent) Type() string {
+	return e.eventType
+}
+
+func (e *anyEvent) Value() any {
+	return e.value
+}
+
+// Payload returns the value marshaled to JSON, or nil if it can't be marshaled.
+func (e *anyEvent) Payload() []byte {
+	e.once.Do(func() {
+		switch v := e.value.(type) {
+		case nil:
+		case []byte:
+			e.payload = v
+		case string:
+			e.payload = []byte(v)
+		default:
+			e.payload, _ = json.Marshal(v)
+		}
+	})
+	return e.payload
+}
@@ -0,0 +1,41 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_EmitAny(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	e := New()
+	original := &user{Name: "alice"}
+	var received *user
+	var payload string
+	e.Register("user.created", NewListener(func(event Event) error {
+		var err error
+		received, err = DecodedPayload[*user](event)
+		return err
+	}))
+	e.Register("user.created", NewListener(func(event Event) error {
+		payload = string(event.Payload())
+		return nil
+	}))
+
+	e.EmitAny("user.created", original)
+
+	assert.Same(t, original, received, "the value should be passed through without a round-trip")
+	assert.JSONEq(t, `{"name":"alice"}`, payload)
+}
+
+func TestAnyEvent_DecodedPayloadOtherType(t *testing.T) {
+	event := NewAnyEvent("user.created", map[string]string{"name": "alice"})
+
+	decoded, err := DecodedPayload[struct{ Name string }](event)
+
+	require.NoError(t, err)
+	assert.Equal(t, "alice", decoded.Name)
+}
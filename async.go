@@ -10,3 +10,13 @@ type IAmAsync struct {
 }
 
 func (IAmAsync) isAsync() {}
+
+// _AsyncType reports whether the event type matches a pattern configured with WithAsyncTypes.
+func (e *Eventify) _AsyncType(eventType string) bool {
+	for _, matcher := range e.asyncTypes {
+		if matcher.Match(eventType) {
+			return true
+		}
+	}
+	return false
+}
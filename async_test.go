@@ -0,0 +1,67 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_WithAsyncTypes(t *testing.T) {
+	tests := []struct {
+		eventType string
+		wantAsync bool
+	}{
+		{"analytics.page_viewed", true},
+		{"Audit.Login", true},
+		{"orders.created", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			sim := NewSimulation(1)
+			e := NewEventify(WithScheduler(sim), WithTypeNormalization(nil), WithAsyncTypes("analytics.*", "audit.*"))
+			called := false
+			e.Register(tt.eventType, NewListener(func(Event) error {
+				called = true
+				return nil
+			}))
+
+			e.EmitBy(tt.eventType, nil)
+			assert.Equal(t, !tt.wantAsync, called)
+
+			sim.Run()
+			assert.True(t, called)
+		})
+	}
+}
+
+func TestEventify_EmitOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		listener  func(handle func(Event) error) Listener
+		opts      []EmitOption
+		wantAsync bool
+	}{
+		{"sync", func(h func(Event) error) Listener { return NewListener(h) }, nil, false},
+		{"async", func(h func(Event) error) Listener { return &asyncListener{handle: h} }, nil, true},
+		{"forced async", func(h func(Event) error) Listener { return NewListener(h) }, []EmitOption{WithAsync()}, true},
+		{"forced sync", func(h func(Event) error) Listener { return &asyncListener{handle: h} }, []EmitOption{WithSync()}, false},
+		{"last option wins", func(h func(Event) error) Listener { return NewListener(h) }, []EmitOption{WithSync(), WithAsync()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulation(1)
+			e := NewEventify(WithScheduler(sim))
+			called := false
+			e.Register("test.event", tt.listener(func(Event) error {
+				called = true
+				return nil
+			}))
+
+			e.Emit(NewEvent("test.event", nil), tt.opts...)
+			assert.Equal(t, !tt.wantAsync, called)
+
+			sim.Run()
+			assert.True(t, called)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package backoff provides the delays between retries used by eventify's redeliveries,
+// and by anything else that retries, such as transport reconnects and supervisors.
+package backoff
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Backoff produces the delays of a sequence of retries.
+// A Backoff is stateful: use one per sequence, and Reset it once the operation succeeded.
+type Backoff interface {
+	// Next returns the delay before the next retry.
+	Next() time.Duration
+	// Reset starts the sequence over.
+	Reset()
+}
+
+// Constant returns a Backoff waiting the same delay before every retry.
+func Constant(delay time.Duration) Backoff {
+	return constant(delay)
+}
+
+type constant time.Duration
+
+func (c constant) Next() time.Duration { return time.Duration(c) }
+
+func (c constant) Reset() {}
+
+// Exponential returns a Backoff doubling the delay before every retry, starting at initial.
+// Combine it with Capped to bound the delay.
+func Exponential(initial time.Duration) Backoff {
+	return &exponential{initial: initial}
+}
+
+type exponential struct {
+	mutex   sync.Mutex
+	initial time.Duration
+	next    time.Duration
+}
+
+func (e *exponential) Next() time.Duration {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.next == 0 {
+		e.next = e.initial
+	}
+	delay := e.next
+	if e.next <= math.MaxInt64/2 {
+		e.next *= 2
+	}
+	return delay
+}
+
+func (e *exponential) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.next = 0
+}
+
+// DecorrelatedJitter returns a Backoff picking every delay at random between base and three times
+// the previous delay, capped at maxDelay, which spreads out clients retrying at the same time.
+func DecorrelatedJitter(base, maxDelay time.Duration) Backoff {
+	return &decorrelated{base: base, max: maxDelay}
+}
+
+type decorrelated struct {
+	mutex    sync.Mutex
+	base     time.Duration
+	max      time.Duration
+	previous time.Duration
+}
+
+func (d *decorrelated) Next() time.Duration {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	previous := max(d.previous, d.base)
+	upper := min(previous*3, d.max)
+	delay := upper
+	if upper > d.base {
+		delay = d.base + rand.N(upper-d.base)
+	}
+	d.previous = delay
+	return delay
+}
+
+func (d *decorrelated) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.previous = 0
+}
+
+// Capped returns a Backoff limiting the delays of b to maxDelay.
+func Capped(b Backoff, maxDelay time.Duration) Backoff {
+	return &capped{Backoff: b, max: maxDelay}
+}
+
+type capped struct {
+	Backoff
+	max time.Duration
+}
+
+func (c *capped) Next() time.Duration {
+	return min(c.Backoff.Next(), c.max)
+}
+
+// Jitter returns a Backoff randomizing the delays of b by up to factor of their value in either
+// direction, e.g. 0.2 for ±20%.
+func Jitter(b Backoff, factor float64) Backoff {
+	return &jitter{Backoff: b, factor: factor}
+}
+
+type jitter struct {
+	Backoff
+	factor float64
+}
+
+func (j *jitter) Next() time.Duration {
+	delay := float64(j.Backoff.Next())
+	return time.Duration(delay * (1 + j.factor*(2*rand.Float64()-1)))
+}
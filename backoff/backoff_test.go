@@ -0,0 +1,54 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func next(b Backoff, n int) []time.Duration {
+	delays := make([]time.Duration, n)
+	for i := range delays {
+		delays[i] = b.Next()
+	}
+	return delays
+}
+
+func TestConstant(t *testing.T) {
+	assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second}, next(Constant(time.Second), 3))
+}
+
+func TestExponential(t *testing.T) {
+	b := Capped(Exponential(10*time.Millisecond), 50*time.Millisecond)
+
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}, next(b, 4))
+
+	b.Reset()
+	assert.Equal(t, 10*time.Millisecond, b.Next())
+}
+
+func TestExponential_NoOverflow(t *testing.T) {
+	b := Exponential(time.Hour)
+	delays := next(b, 100)
+	for _, delay := range delays {
+		assert.Positive(t, delay)
+	}
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	b := DecorrelatedJitter(10*time.Millisecond, 100*time.Millisecond)
+	previous := 10 * time.Millisecond
+	for _, delay := range next(b, 100) {
+		assert.GreaterOrEqual(t, delay, 10*time.Millisecond)
+		assert.LessOrEqual(t, delay, min(previous*3, 100*time.Millisecond))
+		previous = delay
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for _, delay := range next(Jitter(Constant(100*time.Millisecond), 0.2), 100) {
+		assert.GreaterOrEqual(t, delay, 80*time.Millisecond)
+		assert.LessOrEqual(t, delay, 120*time.Millisecond)
+	}
+}
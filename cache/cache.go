@@ -0,0 +1,136 @@
+// Package cache invalidates cache entries when the events they depend on are emitted,
+// the most common listener there is:
+//
+//	inv := cache.New(bus, cache.Ristretto[string](users))
+//	inv.InvalidateOn("user.*.updated", cache.Key(template.Must(template.New("key").Parse("user:{{.JSON.id}}"))))
+//
+// Caches are adapted through the small Cache interface; Ristretto and Groupcache adapt
+// the usual libraries without this package depending on them.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Cache is a cache whose entries can be invalidated by key.
+type Cache interface {
+	Invalidate(ctx context.Context, key string) error
+}
+
+// Func adapts a function to Cache.
+type Func func(ctx context.Context, key string) error
+
+// Invalidate calls f.
+func (f Func) Invalidate(ctx context.Context, key string) error {
+	return f(ctx, key)
+}
+
+// Ristretto adapts a cache with a Del method, such as a ristretto.Cache[string, V].
+func Ristretto[K ~string](c interface{ Del(key K) }) Cache {
+	return Func(func(_ context.Context, key string) error {
+		c.Del(K(key))
+		return nil
+	})
+}
+
+// Groupcache adapts a cache with a Remove method, such as a groupcache.Group of the mailgun fork,
+// which also removes the key from the peers.
+func Groupcache(c interface {
+	Remove(ctx context.Context, key string) error
+}) Cache {
+	return Func(c.Remove)
+}
+
+// KeyFunc returns the cache keys invalidated by the event.
+type KeyFunc func(event eventify.Event) ([]string, error)
+
+// Key returns a KeyFunc rendering the single key with the template,
+// which is executed with an eventify.TemplateData.
+func Key(tmpl eventify.Template) KeyFunc {
+	return func(event eventify.Event) ([]string, error) {
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, eventify.NewTemplateData(event)); err != nil {
+			return nil, err
+		}
+		return []string{buf.String()}, nil
+	}
+}
+
+// ListenerName is the name of the listeners created by NewListener.
+const ListenerName = "cache.invalidate"
+
+// NewListener creates a listener invalidating the keys of every event in the cache.
+// Empty keys are skipped.
+func NewListener(c Cache, keys KeyFunc) eventify.Listener {
+	return &listener{cache: c, keys: keys}
+}
+
+type listener struct {
+	cache Cache
+	keys  KeyFunc
+}
+
+func (l *listener) Name() string {
+	return ListenerName
+}
+
+func (l *listener) Handle(event eventify.Event) error {
+	return l.HandleCtx(context.Background(), event)
+}
+
+func (l *listener) HandleCtx(ctx context.Context, event eventify.Event) error {
+	keys, err := l.keys(event)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, key := range keys {
+		if key != "" {
+			errs = append(errs, l.cache.Invalidate(ctx, key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Invalidator binds the entries of a cache to the event patterns of a bus.
+type Invalidator struct {
+	bus       *eventify.Eventify
+	cache     Cache
+	mutex     sync.Mutex
+	listeners map[string][]eventify.Listener
+}
+
+// New creates a new Invalidator of the cache for the bus.
+func New(bus *eventify.Eventify, c Cache) *Invalidator {
+	return &Invalidator{bus: bus, cache: c, listeners: map[string][]eventify.Listener{}}
+}
+
+// InvalidateOn invalidates the keys of the events whose type matches the pattern.
+// This method is thread-safe.
+func (i *Invalidator) InvalidateOn(pattern string, keys KeyFunc) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	listener := NewListener(i.cache, keys)
+	i.listeners[pattern] = append(i.listeners[pattern], listener)
+	i.bus.Register(pattern, listener)
+}
+
+// Close unregisters the listeners of the Invalidator and drains them.
+// This method is thread-safe.
+func (i *Invalidator) Close(ctx context.Context) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	var errs []error
+	for pattern, listeners := range i.listeners {
+		for _, listener := range listeners {
+			errs = append(errs, i.bus.UnregisterAndDrain(ctx, pattern, listener))
+		}
+	}
+	i.listeners = map[string][]eventify.Listener{}
+	return errors.Join(errs...)
+}
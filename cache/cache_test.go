@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"text/template"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryCache struct {
+	mutex   sync.Mutex
+	entries map[string]string
+}
+
+func (c *memoryCache) Del(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+}
+
+func (c *memoryCache) Keys() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	keys := []string{}
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+type group struct {
+	removed []string
+}
+
+func (g *group) Remove(_ context.Context, key string) error {
+	g.removed = append(g.removed, key)
+	return nil
+}
+
+func TestInvalidator(t *testing.T) {
+	bus := eventify.NewEventify()
+	users := &memoryCache{entries: map[string]string{"user:1": "alice", "user:2": "bob", "team:1": "core"}}
+	inv := New(bus, Ristretto[string](users))
+	inv.InvalidateOn("user.*.updated", Key(template.Must(template.New("key").Parse("user:{{.JSON.id}}"))))
+	inv.InvalidateOn("team.deleted", func(event eventify.Event) ([]string, error) {
+		return []string{"team:1", ""}, nil
+	})
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   any
+		want      []string
+	}{
+		{"unrelated", "user.created", map[string]int{"id": 1}, []string{"team:1", "user:1", "user:2"}},
+		{"template key", "user.profile.updated", map[string]int{"id": 1}, []string{"team:1", "user:2"}},
+		{"key function", "team.deleted", nil, []string{"user:2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus.EmitBy(tt.eventType, tt.payload)
+			assert.ElementsMatch(t, tt.want, users.Keys())
+		})
+	}
+
+	assert.NoError(t, inv.Close(context.Background()))
+	users.entries["user:2"] = "bob"
+	bus.EmitBy("user.profile.updated", map[string]int{"id": 2})
+	assert.ElementsMatch(t, []string{"user:2"}, users.Keys())
+}
+
+func TestGroupcache(t *testing.T) {
+	g := &group{}
+	listener := NewListener(Groupcache(g), func(event eventify.Event) ([]string, error) {
+		return []string{"a", "b"}, nil
+	})
+
+	assert.NoError(t, listener.Handle(eventify.NewEvent("x", nil)))
+	assert.Equal(t, []string{"a", "b"}, g.removed)
+}
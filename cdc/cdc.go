@@ -0,0 +1,157 @@
+// Package cdc turns change-data-capture envelopes into eventify events.
+//
+// Debezium JSON envelopes, with or without the schema wrapper, are decoded and
+// emitted as "db.<table>.inserted", "db.<table>.updated", "db.<table>.deleted"
+// or "db.<table>.truncated" events whose payload is a JSON encoded Change.
+package cdc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// ErrUnknownOperation is returned when an envelope carries an operation that is not supported.
+var ErrUnknownOperation = errors.New("cdc: unknown operation")
+
+// maxLineSize is the maximum size of a single envelope read by Consume.
+const maxLineSize = 16 * 1024 * 1024
+
+// Change is the payload of the events emitted by a Source.
+type Change struct {
+	Database  string          `json:"database,omitempty"`
+	Table     string          `json:"table"`
+	Operation string          `json:"operation"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	TsMs      int64           `json:"ts_ms,omitempty"`
+}
+
+// Source decodes Debezium envelopes and emits them on an Eventify instance.
+type Source struct {
+	bus    *eventify.Eventify
+	prefix string
+}
+
+// OptionFunc is a function that configures a Source.
+type OptionFunc func(*Source)
+
+// WithPrefix sets the prefix of the emitted event types, "db" by default.
+func WithPrefix(prefix string) OptionFunc {
+	return func(s *Source) {
+		s.prefix = prefix
+	}
+}
+
+// NewSource creates a new Source emitting on the bus.
+func NewSource(bus *eventify.Eventify, opts ...OptionFunc) *Source {
+	s := &Source{
+		bus:    bus,
+		prefix: "db",
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type envelope struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+	Source struct {
+		DB    string `json:"db"`
+		Table string `json:"table"`
+	} `json:"source"`
+	Op   string `json:"op"`
+	TsMs int64  `json:"ts_ms"`
+}
+
+// Emit decodes a single envelope, such as the value of a Kafka message, and emits it.
+// Tombstones (empty or null messages, or a schema wrapper with a null payload) and logical
+// decoding messages, which don't belong to a table, are ignored.
+func (s *Source) Emit(message []byte) error {
+	message = bytes.TrimSpace(message)
+	if len(message) == 0 || bytes.Equal(message, []byte("null")) {
+		return nil
+	}
+	env, err := decode(message)
+	if err != nil || env == nil {
+		return err
+	}
+	var operation string
+	switch env.Op {
+	case "c", "r":
+		operation = "inserted"
+	case "u":
+		operation = "updated"
+	case "d":
+		operation = "deleted"
+	case "t":
+		operation = "truncated"
+	case "m":
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOperation, env.Op)
+	}
+	change := Change{
+		Database:  env.Source.DB,
+		Table:     env.Source.Table,
+		Operation: operation,
+		Before:    nullToEmpty(env.Before),
+		After:     nullToEmpty(env.After),
+		TsMs:      env.TsMs,
+	}
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	s.bus.Emit(eventify.NewEvent(s.prefix+"."+change.Table+"."+operation, payload))
+	return nil
+}
+
+// Consume reads newline-delimited envelopes from the reader, such as a file, and emits them
+// until the reader is exhausted or an envelope fails to decode or carries an unknown operation.
+func (s *Source) Consume(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if err := s.Emit(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// decode decodes the envelope, unwrapping it from its schema wrapper if any.
+// It returns a nil envelope for a tombstone.
+func decode(message []byte) (*envelope, error) {
+	var wrapped struct {
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(message, &wrapped); err != nil {
+		return nil, err
+	}
+	if wrapped.Payload != nil {
+		message = wrapped.Payload
+		if bytes.Equal(message, []byte("null")) {
+			return nil, nil
+		}
+	}
+	env := &envelope{}
+	if err := json.Unmarshal(message, env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func nullToEmpty(raw json.RawMessage) json.RawMessage {
+	if bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	return raw
+}
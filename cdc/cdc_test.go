@@ -0,0 +1,65 @@
+package cdc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_Consume(t *testing.T) {
+	input := strings.Join([]string{
+		`{"schema":{},"payload":{"before":null,"after":{"id":1},"source":{"db":"shop","table":"users"},"op":"c","ts_ms":1}}`,
+		`{"before":{"id":1},"after":{"id":1,"name":"bob"},"source":{"db":"shop","table":"users"},"op":"u","ts_ms":2}`,
+		``,
+		`{"before":{"id":1},"after":null,"source":{"db":"shop","table":"users"},"op":"d","ts_ms":3}`,
+		`{"schema":{},"payload":null}`,
+		`{"source":{"db":"shop"},"op":"m","ts_ms":4,"message":{"prefix":"audit","content":"aGk="}}`,
+		`{"before":null,"after":null,"source":{"db":"shop","table":"users"},"op":"t","ts_ms":5}`,
+	}, "\n")
+
+	bus := eventify.New()
+	types := []string{}
+	changes := []Change{}
+	bus.Register("db.users.*", eventify.NewListener(func(event eventify.Event) error {
+		var change Change
+		require.NoError(t, json.Unmarshal(event.Payload(), &change))
+		types = append(types, event.Type())
+		changes = append(changes, change)
+		return nil
+	}))
+
+	require.NoError(t, NewSource(bus).Consume(strings.NewReader(input)))
+
+	assert.Equal(t, []string{"db.users.inserted", "db.users.updated", "db.users.deleted", "db.users.truncated"}, types)
+	assert.Nil(t, changes[0].Before)
+	assert.JSONEq(t, `{"id":1}`, string(changes[0].After))
+	assert.JSONEq(t, `{"id":1,"name":"bob"}`, string(changes[1].After))
+	assert.Nil(t, changes[2].After)
+	assert.Equal(t, "shop", changes[2].Database)
+	assert.Nil(t, changes[3].Before)
+	assert.Nil(t, changes[3].After)
+}
+
+func TestSource_Emit(t *testing.T) {
+	t.Run("unknown operation", func(t *testing.T) {
+		err := NewSource(eventify.New()).Emit([]byte(`{"source":{"table":"users"},"op":"x"}`))
+		assert.ErrorIs(t, err, ErrUnknownOperation)
+	})
+
+	t.Run("custom prefix", func(t *testing.T) {
+		bus := eventify.New()
+		var got string
+		bus.Register("*", eventify.NewListener(func(event eventify.Event) error {
+			got = event.Type()
+			return nil
+		}))
+
+		require.NoError(t, NewSource(bus, WithPrefix("cdc")).Emit([]byte(`{"source":{"table":"orders"},"op":"r"}`)))
+
+		assert.Equal(t, "cdc.orders.inserted", got)
+	})
+}
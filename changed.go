@@ -0,0 +1,32 @@
+package eventify
+
+// EmitIfChanged is like EmitBy but skips the emit when the payload is identical to the one of the last event
+// emitted with EmitIfChanged for the type, so config-watch sources don't spam listeners with no-op updates.
+// Payloads are compared by hash. It reports whether the event was emitted; rejected events are not
+// remembered, so the next identical payload is emitted again.
+// This method is thread-safe.
+func (e *Eventify) EmitIfChanged(eventType string, payload any) bool {
+	eventType = e._Normalize(eventType)
+	bz, err := e._AnyToBytes(payload)
+	if err != nil {
+		e.log.Debug("eventify marshal payload failed", "event", eventType, "error", err)
+		if !e.marshalPolicy(eventType, err) {
+			return false
+		}
+	}
+	fingerprint := payloadFingerprint(bz)
+	previous, loaded := e.last.Swap(eventType, fingerprint)
+	if loaded && previous.(uint64) == fingerprint {
+		e.log.Debug("eventify unchanged payload skipped", "event", eventType)
+		return false
+	}
+	if err := e._Emit(NewEvent(eventType, bz)); err != nil {
+		if loaded {
+			e.last.CompareAndSwap(eventType, fingerprint, previous)
+		} else {
+			e.last.CompareAndDelete(eventType, fingerprint)
+		}
+		return false
+	}
+	return true
+}
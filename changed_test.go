@@ -0,0 +1,42 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_EmitIfChanged(t *testing.T) {
+	e := NewEventify(WithMaxPayloadSize(16))
+	var received []string
+	e.Register("config.*", NewListener(func(event Event) error {
+		received = append(received, event.Type()+"="+string(event.Payload()))
+		return nil
+	}))
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   any
+		want      bool
+	}{
+		{"first", "config.flags", map[string]bool{"beta": true}, true},
+		{"unchanged", "config.flags", map[string]bool{"beta": true}, false},
+		{"other type", "config.limits", map[string]bool{"beta": true}, true},
+		{"changed", "config.flags", map[string]bool{"beta": false}, true},
+		{"rejected", "config.flags", "a payload that is too large", false},
+		{"back after rejection", "config.flags", map[string]bool{"beta": false}, false},
+		{"changed back", "config.flags", map[string]bool{"beta": true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, e.EmitIfChanged(tt.eventType, tt.payload))
+		})
+	}
+	assert.Equal(t, []string{
+		`config.flags={"beta":true}`,
+		`config.limits={"beta":true}`,
+		`config.flags={"beta":false}`,
+		`config.flags={"beta":true}`,
+	}, received)
+}
@@ -0,0 +1,134 @@
+package eventify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// ErrBlobNotFound is returned by blob stores when a reference is unknown.
+var ErrBlobNotFound = errors.New("eventify: blob not found")
+
+// BlobStore is an interface that represents a store for payloads kept outside the bus, such as S3.
+type BlobStore interface {
+	Put(ctx context.Context, payload []byte) (ref string, err error)
+	Get(ctx context.Context, ref string) ([]byte, error)
+}
+
+// PayloadResolver is an interface that resolves the full payload of an event.
+type PayloadResolver interface {
+	Resolve(ctx context.Context, event Event) ([]byte, error)
+}
+
+// ClaimCheck keeps large payloads in a blob store and emits small references to them instead,
+// keeping the bus and transports fast. Listeners resolve the full payload lazily with Resolve.
+type ClaimCheck struct {
+	store     BlobStore
+	threshold int
+}
+
+// claimCheckRef is the payload of an event carrying a reference.
+type claimCheckRef struct {
+	Ref string `json:"$claim_check"`
+}
+
+// NewClaimCheck creates a claim check storing payloads larger than threshold bytes in the store.
+func NewClaimCheck(store BlobStore, threshold int) *ClaimCheck {
+	return &ClaimCheck{store: store, threshold: threshold}
+}
+
+// NewEvent creates an event with the payload, replaced by a reference if it is larger than the threshold.
+func (c *ClaimCheck) NewEvent(ctx context.Context, eventType string, payload []byte) (Event, error) {
+	if len(payload) <= c.threshold {
+		return NewEvent(eventType, payload), nil
+	}
+	ref, err := c.store.Put(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := json.Marshal(claimCheckRef{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	return &claimCheckEvent{event: event{eventType: eventType, payload: bz}, ref: ref}, nil
+}
+
+// Resolve returns the full payload of the event, fetching it from the store if the event carries a reference.
+// Events created by NewEvent cache the fetched payload, so several listeners fetch it once.
+// Failed fetches are not cached: the next listener fetches it again with its own context.
+func (c *ClaimCheck) Resolve(ctx context.Context, e Event) ([]byte, error) {
+	if claim, ok := e.(*claimCheckEvent); ok {
+		claim.mutex.Lock()
+		defer claim.mutex.Unlock()
+		if claim.fetched {
+			return claim.body, nil
+		}
+		body, err := c.store.Get(ctx, claim.ref)
+		if err != nil {
+			return nil, err
+		}
+		claim.body, claim.fetched = body, true
+		return body, nil
+	}
+	ref, ok := ClaimCheckRef(e)
+	if !ok {
+		return e.Payload(), nil
+	}
+	return c.store.Get(ctx, ref)
+}
+
+// ClaimCheckRef returns the blob reference carried by the event, if any.
+// It also recognizes references in events received from other processes.
+func ClaimCheckRef(e Event) (string, bool) {
+	if claim, ok := e.(*claimCheckEvent); ok {
+		return claim.ref, true
+	}
+	var ref claimCheckRef
+	if err := json.Unmarshal(e.Payload(), &ref); err != nil || ref.Ref == "" {
+		return "", false
+	}
+	return ref.Ref, true
+}
+
+type claimCheckEvent struct {
+	event
+	ref     string
+	mutex   sync.Mutex
+	fetched bool
+	body    []byte
+}
+
+// NewMemoryBlobStore creates a blob store keeping payloads in memory, useful for tests and development.
+func NewMemoryBlobStore() BlobStore {
+	return &memoryBlobStore{blobs: map[string][]byte{}}
+}
+
+type memoryBlobStore struct {
+	mutex sync.RWMutex
+	blobs map[string][]byte
+}
+
+func (s *memoryBlobStore) Put(_ context.Context, payload []byte) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	ref := hex.EncodeToString(id)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.blobs[ref] = append([]byte{}, payload...)
+	return ref, nil
+}
+
+func (s *memoryBlobStore) Get(_ context.Context, ref string) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	payload, ok := s.blobs[ref]
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+	return payload, nil
+}
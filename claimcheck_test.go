@@ -0,0 +1,87 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingBlobStore struct {
+	BlobStore
+	gets int
+	fail error
+}
+
+func (s *countingBlobStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	s.gets++
+	if s.fail != nil {
+		return nil, s.fail
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.BlobStore.Get(ctx, ref)
+}
+
+func TestClaimCheck(t *testing.T) {
+	ctx := context.Background()
+	store := &countingBlobStore{BlobStore: NewMemoryBlobStore()}
+	claims := NewClaimCheck(store, 8)
+	var resolver PayloadResolver = claims
+
+	small, err := claims.NewEvent(ctx, "doc.uploaded", []byte("tiny"))
+	require.NoError(t, err)
+	_, isRef := ClaimCheckRef(small)
+	assert.False(t, isRef)
+
+	large, err := claims.NewEvent(ctx, "doc.uploaded", []byte("a rather large document"))
+	require.NoError(t, err)
+	ref, isRef := ClaimCheckRef(large)
+	require.True(t, isRef)
+	assert.Contains(t, string(large.Payload()), ref)
+
+	e := New()
+	for i := 0; i < 3; i++ {
+		e.Register("doc.uploaded", NewListener(func(event Event) error {
+			body, err := resolver.Resolve(ctx, event)
+			require.NoError(t, err)
+			assert.Equal(t, "a rather large document", string(body))
+			return nil
+		}))
+	}
+	e.Emit(large)
+	assert.Equal(t, 1, store.gets, "the payload should be fetched once per event")
+
+	remote := NewEvent("doc.uploaded", large.Payload())
+	body, err := claims.Resolve(ctx, remote)
+	require.NoError(t, err)
+	assert.Equal(t, "a rather large document", string(body))
+
+	_, err = store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestClaimCheck_RetriesFailedFetches(t *testing.T) {
+	store := &countingBlobStore{BlobStore: NewMemoryBlobStore()}
+	claims := NewClaimCheck(store, 0)
+	large, err := claims.NewEvent(context.Background(), "doc.uploaded", []byte("document"))
+	require.NoError(t, err)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = claims.Resolve(cancelled, large)
+	assert.ErrorIs(t, err, context.Canceled)
+	store.fail = assert.AnError
+	_, err = claims.Resolve(context.Background(), large)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	store.fail = nil
+	body, err := claims.Resolve(context.Background(), large)
+	require.NoError(t, err)
+	assert.Equal(t, "document", string(body))
+	_, err = claims.Resolve(context.Background(), large)
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.gets, "only the successful fetch is cached")
+}
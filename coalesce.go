@@ -0,0 +1,94 @@
+package eventify
+
+import "sync"
+
+// CoalescedEvent is the event a coalescing listener passes to its inner listener.
+type CoalescedEvent interface {
+	Event
+	// Collapsed returns the number of earlier events for the same key that were skipped in favor of this one.
+	Collapsed() int
+	// Unwrap returns the original event.
+	Unwrap() Event
+}
+
+// NewCoalescingListener creates a listener that delivers only the latest event per key to the inner listener:
+// events arriving for a key while the inner listener is still busy with it replace each other, and the one
+// left is delivered next as a CoalescedEvent counting the collapsed events. This suits slow listeners of
+// state events, such as cache refreshes or UI updates, where only the latest state matters.
+// The key is the event's Key if it implements Keyed, otherwise its type.
+// The inner listener runs on the scheduler of the bus, one task per key, and is invoked like a registered
+// listener: it gets the resources of the bus, is initialized and limited by its guards, and its errors are
+// reported to the event's ErrorHandler. A key is in flight until its last pending event was delivered,
+// so Drain and UnregisterAndDrain wait for it.
+// The coalescing listener keeps the name, async and validator markers of the inner listener, and closes it with itself.
+func (e *Eventify) NewCoalescingListener(inner Listener) Listener {
+	c := &coalescer{bus: e, inner: inner, pending: map[string]*coalescedEvent{}}
+	c.self = wrapListener(inner, c.Handle)
+	return c.self
+}
+
+type coalescer struct {
+	bus     *Eventify
+	inner   Listener
+	self    Listener
+	mutex   sync.Mutex
+	pending map[string]*coalescedEvent
+}
+
+func (c *coalescer) Handle(event Event) error {
+	key := event.Type()
+	if keyed, ok := event.(Keyed); ok {
+		key = keyed.Key()
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	previous, running := c.pending[key]
+	next := &coalescedEvent{Event: event}
+	if previous != nil {
+		next.collapsed = previous.collapsed + 1
+	}
+	c.pending[key] = next
+	if !running {
+		// The hold is taken while the delivery of the event is still in flight, so a drain can't miss it.
+		c.bus.inflight.Acquire(c.self)
+		c.bus.scheduler.Go(func() {
+			defer c.bus.inflight.Release(c.self)
+			c.run(key)
+		})
+	}
+	return nil
+}
+
+// run delivers the pending events of the key until there are none left.
+// A key is running while it is in pending, with a nil value once its event was taken.
+func (c *coalescer) run(key string) {
+	for {
+		c.mutex.Lock()
+		event := c.pending[key]
+		if event == nil {
+			delete(c.pending, key)
+			c.mutex.Unlock()
+			return
+		}
+		c.pending[key] = nil
+		c.mutex.Unlock()
+		if err := c.bus._Handle(event, c.inner); err != nil {
+			if errHandler, ok := event.Event.(ErrorHandler); ok {
+				errHandler.ErrorHandler(event.Event, err)
+			}
+		}
+	}
+}
+
+type coalescedEvent struct {
+	Event
+	collapsed int
+}
+
+func (e *coalescedEvent) Collapsed() int {
+	return e.collapsed
+}
+
+func (e *coalescedEvent) Unwrap() Event {
+	return e.Event
+}
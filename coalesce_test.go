@@ -0,0 +1,97 @@
+package eventify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_NewCoalescingListener(t *testing.T) {
+	e := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	var received []string
+	var collapsed []int
+	e.Register("cache.refresh", e.NewCoalescingListener(NewNamedListener("refresh", func(event Event) error {
+		if string(event.Payload()) == "0" {
+			close(started)
+			<-release
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		received = append(received, string(event.Payload()))
+		collapsed = append(collapsed, event.(CoalescedEvent).Collapsed())
+		return nil
+	})))
+
+	e.EmitBy("cache.refresh", "0")
+	<-started
+	for i := 1; i <= 5; i++ {
+		e.EmitBy("cache.refresh", fmt.Sprint(i))
+	}
+	close(release)
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(received) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"0", "5"}, received)
+	assert.Equal(t, []int{0, 4}, collapsed)
+	assert.Equal(t, "refresh", e.Stats()[0].Listener)
+}
+
+func TestEventify_NewCoalescingListener_PerKey(t *testing.T) {
+	e := New()
+	var mutex sync.Mutex
+	received := map[string]string{}
+	e.Register("user.updated", e.NewCoalescingListener(NewListener(func(event Event) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		received[event.(CoalescedEvent).Unwrap().(Keyed).Key()] = string(event.Payload())
+		return nil
+	})))
+
+	e.Emit(&keyedEvent{Event: NewEvent("user.updated", []byte("1")), key: "a"})
+	e.Emit(&keyedEvent{Event: NewEvent("user.updated", []byte("2")), key: "b"})
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(received) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, received)
+}
+
+func TestEventify_NewCoalescingListener_RunsThroughBus(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	e.Provide(&testPool{dsn: "db"})
+	inner := &lifecycleListener{}
+	var dsn string
+	e.Register("cache.refresh", e.NewCoalescingListener(NewContextListener(func(ctx context.Context, event Event) error {
+		pool, _ := Resolve[*testPool](ctx)
+		dsn = pool.dsn
+		return nil
+	})))
+	e.Register("cache.refresh", e.NewCoalescingListener(inner))
+
+	e.EmitBy("cache.refresh", nil)
+	e.EmitBy("cache.refresh", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, e.Drain(ctx), context.DeadlineExceeded)
+	assert.Equal(t, 0, inner.handled)
+
+	sim.Run()
+	assert.NoError(t, e.Drain(context.Background()))
+	assert.Equal(t, "db", dsn)
+	assert.Equal(t, 1, inner.inits)
+	assert.Equal(t, 1, inner.handled)
+}
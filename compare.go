@@ -0,0 +1,197 @@
+package eventify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ShadowMismatchType is the type of the events emitted when a shadow listener registered with WithShadowCompare
+// didn't behave like its primary.
+const ShadowMismatchType = "eventify.shadow.mismatch"
+
+// ShadowMismatch is the payload of a ShadowMismatchType event.
+type ShadowMismatch struct {
+	// Listener is the name shared by the primary and the shadow listeners.
+	Listener string `json:"listener"`
+	// Event is the type of the event both handled.
+	Event string `json:"event"`
+	// EventID is the ID of the event if it implements Identifiable.
+	EventID string       `json:"event_id,omitempty"`
+	Primary ShadowResult `json:"primary"`
+	Shadow  ShadowResult `json:"shadow"`
+	// Diff describes every difference, one per line, such as `effect 1: {"total":10} != {"total":12}`.
+	Diff []string `json:"diff"`
+}
+
+// ShadowResult is what a listener did with an event: the effects it recorded with RecordEffect, as JSON,
+// and its error, if any.
+type ShadowResult struct {
+	Effects []json.RawMessage `json:"effects"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// WithShadowCompare registers the listener in shadow mode, like WithShadow, and compares what it does with
+// every event to what the primary listener with the same name does with it: the effects both record with
+// RecordEffect, in order, and whether they failed. Differences are emitted as ShadowMismatchType events
+// with a ShadowMismatch payload. Events without a primary listener with the name, or that are not comparable,
+// are not compared. The listener must implement Namable.
+func WithShadowCompare() RegisterOption {
+	return func(r *registration) {
+		r.shadow = true
+		r.compare = true
+	}
+}
+
+// RecordEffect records a descriptor of a side effect of the invocation, such as the row a handler writes
+// or the value it returns, for WithShadowCompare. Descriptors are compared as JSON.
+// The context is the one passed to ContextListener.HandleCtx. It does nothing for invocations not compared.
+func RecordEffect(ctx context.Context, effect any) {
+	if r, ok := ctx.Value(recordingKey{}).(*recording); ok {
+		r.Record(effect)
+	}
+}
+
+type recordingKey struct{}
+
+// pendingRecording identifies the delivery of an event to a primary listener that is compared with a shadow.
+type pendingRecording struct {
+	event    Event
+	listener any
+}
+
+// recording collects what an invocation did.
+type recording struct {
+	comparison *comparison
+	mutex      sync.Mutex
+	result     ShadowResult
+}
+
+func (r *recording) Record(effect any) {
+	data, err := json.Marshal(effect)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("unmarshalable %T: %v", effect, err))
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.result.Effects = append(r.result.Effects, data)
+}
+
+// Done records the error of the invocation and compares the results once both invocations are done.
+func (r *recording) Done(err error) {
+	r.mutex.Lock()
+	if err != nil {
+		r.result.Error = err.Error()
+	}
+	r.mutex.Unlock()
+	r.comparison.Done()
+}
+
+// comparison pairs the recordings of the primary and shadow invocations of an event.
+type comparison struct {
+	eventify  *Eventify
+	event     Event
+	name      string
+	primary   recording
+	shadow    recording
+	mutex     sync.Mutex
+	remaining int
+}
+
+func (c *comparison) Done() {
+	c.mutex.Lock()
+	c.remaining--
+	last := c.remaining == 0
+	c.mutex.Unlock()
+	if last {
+		c.eventify._CompareResults(c)
+	}
+}
+
+// _Compare starts the comparisons of the shadows registered with WithShadowCompare with their primaries
+// and returns the recordings of the shadows.
+func (e *Eventify) _Compare(event Event, listeners, shadows []Listener) map[Listener]*recording {
+	if !e.hasComparisons.Load() || !reflect.ValueOf(event).Comparable() {
+		return nil
+	}
+	var recordings map[Listener]*recording
+	for _, shadow := range shadows {
+		compare, _ := e.shadows.Load(statsKey(shadow))
+		namable, ok := shadow.(Namable)
+		if compare != true || !ok {
+			continue
+		}
+		for _, primary := range listeners {
+			if p, ok := primary.(Namable); !ok || p.Name() != namable.Name() || statsKey(primary) == nil {
+				continue
+			}
+			c := &comparison{eventify: e, event: event, name: namable.Name(), remaining: 2}
+			c.primary.comparison, c.shadow.comparison = c, c
+			if _, loaded := e.recordings.LoadOrStore(pendingRecording{event: event, listener: primary}, &c.primary); loaded {
+				break
+			}
+			if recordings == nil {
+				recordings = map[Listener]*recording{}
+			}
+			recordings[shadow] = &c.shadow
+			break
+		}
+	}
+	return recordings
+}
+
+// _Recording returns the recording of the delivery of the event to the listener if it is compared, once.
+func (e *Eventify) _Recording(event Event, listener Listener) *recording {
+	if !e.hasComparisons.Load() || statsKey(listener) == nil || !reflect.ValueOf(event).Comparable() {
+		return nil
+	}
+	if r, ok := e.recordings.LoadAndDelete(pendingRecording{event: event, listener: listener}); ok {
+		return r.(*recording)
+	}
+	return nil
+}
+
+// _CompareResults emits the mismatch of the comparison, if any.
+func (e *Eventify) _CompareResults(c *comparison) {
+	diff := diffResults(c.primary.result, c.shadow.result)
+	if len(diff) == 0 {
+		return
+	}
+	mismatch := ShadowMismatch{
+		Listener: c.name,
+		Event:    c.event.Type(),
+		Primary:  c.primary.result,
+		Shadow:   c.shadow.result,
+		Diff:     diff,
+	}
+	if identifiable, ok := c.event.(Identifiable); ok {
+		mismatch.EventID = identifiable.ID()
+	}
+	withEventFields(e.log, c.event, nil).Debug("eventify shadow mismatch", "event", c.event.Type(), "listener", c.name, "diff", diff)
+	payload, _ := json.Marshal(mismatch)
+	e.Emit(NewEvent(ShadowMismatchType, payload))
+}
+
+// diffResults describes the differences between the results of the primary and the shadow.
+func diffResults(primary, shadow ShadowResult) []string {
+	var diff []string
+	for i := range max(len(primary.Effects), len(shadow.Effects)) {
+		switch {
+		case i >= len(shadow.Effects):
+			diff = append(diff, fmt.Sprintf("effect %d: %s missing in shadow", i, primary.Effects[i]))
+		case i >= len(primary.Effects):
+			diff = append(diff, fmt.Sprintf("effect %d: %s missing in primary", i, shadow.Effects[i]))
+		case string(primary.Effects[i]) != string(shadow.Effects[i]):
+			diff = append(diff, fmt.Sprintf("effect %d: %s != %s", i, primary.Effects[i], shadow.Effects[i]))
+		}
+	}
+	switch {
+	case primary.Error == "" && shadow.Error != "":
+		diff = append(diff, fmt.Sprintf("error: primary succeeded, shadow failed: %s", shadow.Error))
+	case primary.Error != "" && shadow.Error == "":
+		diff = append(diff, fmt.Sprintf("error: primary failed: %s, shadow succeeded", primary.Error))
+	}
+	return diff
+}
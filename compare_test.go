@@ -0,0 +1,143 @@
+package eventify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedContextListener struct {
+	name   string
+	handle func(ctx context.Context, event Event) error
+}
+
+func (l *namedContextListener) Name() string { return l.name }
+func (l *namedContextListener) Handle(event Event) error {
+	return l.HandleCtx(context.Background(), event)
+}
+func (l *namedContextListener) HandleCtx(ctx context.Context, event Event) error {
+	return l.handle(ctx, event)
+}
+
+func TestEventify_WithShadowCompare(t *testing.T) {
+	type total struct {
+		Total int `json:"total"`
+	}
+	tests := []struct {
+		name     string
+		primary  func(ctx context.Context) error
+		shadow   func(ctx context.Context) error
+		wantDiff []string
+	}{
+		{
+			name: "same effects",
+			primary: func(ctx context.Context) error {
+				RecordEffect(ctx, total{Total: 10})
+				return nil
+			},
+			shadow: func(ctx context.Context) error {
+				RecordEffect(ctx, map[string]int{"total": 10})
+				return nil
+			},
+		},
+		{
+			name: "different effects",
+			primary: func(ctx context.Context) error {
+				RecordEffect(ctx, total{Total: 10})
+				RecordEffect(ctx, "email sent")
+				return nil
+			},
+			shadow: func(ctx context.Context) error {
+				RecordEffect(ctx, total{Total: 12})
+				return nil
+			},
+			wantDiff: []string{
+				`effect 0: {"total":10} != {"total":12}`,
+				`effect 1: "email sent" missing in shadow`,
+			},
+		},
+		{
+			name:    "shadow failed",
+			primary: func(context.Context) error { return nil },
+			shadow:  func(context.Context) error { return errors.New("boom") },
+			wantDiff: []string{
+				"error: primary succeeded, shadow failed: boom",
+			},
+		},
+		{
+			name:    "both failed",
+			primary: func(context.Context) error { return errors.New("down") },
+			shadow:  func(context.Context) error { return errors.New("unavailable") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New()
+			mismatches := make(chan ShadowMismatch, 1)
+			e.Register(ShadowMismatchType, NewListener(func(event Event) error {
+				var m ShadowMismatch
+				assert.NoError(t, json.Unmarshal(event.Payload(), &m))
+				mismatches <- m
+				return nil
+			}))
+			shadowDone := make(chan struct{})
+			e.Register("order.created", &namedContextListener{name: "handler", handle: func(ctx context.Context, _ Event) error {
+				return tt.primary(ctx)
+			}})
+			e.Register("order.created", &namedContextListener{name: "handler", handle: func(ctx context.Context, _ Event) error {
+				defer close(shadowDone)
+				return tt.shadow(ctx)
+			}}, WithShadowCompare())
+
+			e.Emit(&tracedEvent{Event: NewEvent("order.created", nil), id: "evt-1"})
+			<-shadowDone
+
+			if tt.wantDiff == nil {
+				select {
+				case m := <-mismatches:
+					t.Fatalf("unexpected mismatch %v", m)
+				case <-time.After(20 * time.Millisecond):
+				}
+				return
+			}
+			select {
+			case m := <-mismatches:
+				assert.Equal(t, "handler", m.Listener)
+				assert.Equal(t, "order.created", m.Event)
+				assert.Equal(t, "evt-1", m.EventID)
+				assert.Equal(t, tt.wantDiff, m.Diff)
+			case <-time.After(time.Second):
+				t.Fatal("no mismatch emitted")
+			}
+		})
+	}
+}
+
+func TestEventify_WithShadowCompareWithoutPrimary(t *testing.T) {
+	e := New()
+	done := make(chan struct{})
+	e.Register("order.created", &namedContextListener{name: "handler", handle: func(ctx context.Context, _ Event) error {
+		defer close(done)
+		RecordEffect(ctx, "ignored")
+		return nil
+	}}, WithShadowCompare())
+
+	e.EmitBy("order.created", nil)
+	<-done
+
+	recordings := 0
+	e.recordings.Range(func(any, any) bool {
+		recordings++
+		return true
+	})
+	require.Equal(t, 0, recordings)
+}
+
+func TestRecordEffect_NotCompared(t *testing.T) {
+	assert.NotPanics(t, func() { RecordEffect(context.Background(), "effect") })
+}
@@ -0,0 +1,87 @@
+package eventify
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrFlowEnded is returned when a flow that already completed or failed is used.
+var ErrFlowEnded = errors.New("eventify: flow ended")
+
+// Flow is a coordinator for a sequence of actions that must be undone when the sequence fails.
+// Actions emitted through the flow whose type has a compensation, set with WithCompensation,
+// are recorded; Fail then emits their compensations in reverse order.
+// Flow events carry the flow ID as their correlation ID.
+type Flow struct {
+	eventify *Eventify
+	id       string
+	mutex    sync.Mutex
+	done     []Event
+	ended    bool
+}
+
+// StartFlow starts a flow with the ID.
+func (e *Eventify) StartFlow(id string) *Flow {
+	return &Flow{eventify: e, id: id}
+}
+
+// ID returns the ID of the flow.
+func (f *Flow) ID() string {
+	return f.id
+}
+
+// EmitBy emits an action of the flow, like TryEmitBy, and records it if its type has a compensation.
+func (f *Flow) EmitBy(eventType string, payload any) error {
+	bz, err := f.eventify._AnyToBytes(payload)
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.ended {
+		return ErrFlowEnded
+	}
+	event := &flowEvent{event: event{eventType: f.eventify._Normalize(eventType), payload: bz}, flowID: f.id}
+	if err := f.eventify.TryEmit(event); err != nil {
+		return err
+	}
+	if _, ok := f.eventify.compensations[event.eventType]; ok {
+		f.done = append(f.done, event)
+	}
+	return nil
+}
+
+// Complete ends the flow successfully; its actions are no longer compensated.
+func (f *Flow) Complete() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.ended = true
+	f.done = nil
+}
+
+// Fail ends the flow and emits the compensation of every recorded action, most recent first,
+// with the payload of the action. Calls after the flow ended have no effect.
+func (f *Flow) Fail(cause error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.ended {
+		return
+	}
+	f.ended = true
+	f.eventify.log.Debug("eventify flow failed", "correlation_id", f.id, "compensations", len(f.done), "error", cause)
+	for i := len(f.done) - 1; i >= 0; i-- {
+		action := f.done[i]
+		compensation := f.eventify.compensations[action.Type()]
+		f.eventify.Emit(&flowEvent{event: event{eventType: compensation, payload: action.Payload()}, flowID: f.id})
+	}
+	f.done = nil
+}
+
+type flowEvent struct {
+	event
+	flowID string
+}
+
+func (e *flowEvent) CorrelationID() string {
+	return e.flowID
+}
@@ -0,0 +1,53 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlow_Fail(t *testing.T) {
+	e := NewEventify(
+		WithCompensation("order.reserve", "order.release"),
+		WithCompensation("payment.charge", "payment.refund"),
+	)
+	var received []string
+	e.Register("*", NewListener(func(event Event) error {
+		assert.Equal(t, "flow-1", event.(Correlatable).CorrelationID())
+		received = append(received, event.Type()+":"+string(event.Payload()))
+		return nil
+	}))
+
+	flow := e.StartFlow("flow-1")
+	require.NoError(t, flow.EmitBy("order.reserve", "sku-1"))
+	require.NoError(t, flow.EmitBy("order.noted", "no compensation"))
+	require.NoError(t, flow.EmitBy("payment.charge", "42"))
+	flow.Fail(assert.AnError)
+	flow.Fail(assert.AnError)
+
+	assert.Equal(t, []string{
+		"order.reserve:sku-1",
+		"order.noted:no compensation",
+		"payment.charge:42",
+		"payment.refund:42",
+		"order.release:sku-1",
+	}, received)
+	assert.ErrorIs(t, flow.EmitBy("order.reserve", "sku-2"), ErrFlowEnded)
+}
+
+func TestFlow_Complete(t *testing.T) {
+	e := NewEventify(WithCompensation("order.reserve", "order.release"))
+	released := false
+	e.Register("order.release", NewListener(func(Event) error {
+		released = true
+		return nil
+	}))
+
+	flow := e.StartFlow("flow-2")
+	require.NoError(t, flow.EmitBy("order.reserve", nil))
+	flow.Complete()
+	flow.Fail(assert.AnError)
+
+	assert.False(t, released)
+}
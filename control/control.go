@@ -0,0 +1,256 @@
+// Package control exposes an HTTP control plane to manage a live Eventify instance, so ops tooling
+// and the CLI list, pause and resume listeners, adjust producer quotas, trigger replays and drain
+// every instance the same way.
+//
+// Every request must carry one of the configured tokens as "Authorization: Bearer <token>".
+// Without tokens, every request is rejected.
+// With WithSync, pause, resume and quota operations apply fleet-wide through signed control events.
+//
+// Endpoints returning data answer JSON, the others 204 No Content; errors are {"error": "..."}:
+//
+//	GET  /listeners                   the registrations, see eventify.Eventify.Registrations
+//	GET  /stats                       the listener statistics and delivery counts
+//	POST /labels/{label}/pause        pause the listeners with the label
+//	POST /labels/{label}/resume       resume the listeners with the label
+//	GET  /producers                   the producer counters
+//	PUT  /producers/{name}/quota      set the producer quota from {"rate": 10, "burst": 20}
+//	POST /replay                      replay from {"after": "event-id", "pattern": "order.*"}
+//	POST /drain?timeout=30s           wait for the deliveries under way, 30s by default
+package control
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// ErrReplayUnavailable is returned by POST /replay when no replay function is configured.
+var ErrReplayUnavailable = errors.New("control: replay unavailable")
+
+// defaultDrainTimeout is how long POST /drain waits without a timeout parameter.
+const defaultDrainTimeout = 30 * time.Second
+
+// ReplayRequest is the body of POST /replay.
+type ReplayRequest struct {
+	// After is the ID of the event to replay after, or "" to replay from the beginning.
+	After string `json:"after"`
+	// Pattern restricts the replay to the event types matching it, or to every type for "".
+	Pattern string `json:"pattern"`
+}
+
+// Quota is the body of PUT /producers/{name}/quota. A rate of 0 or less removes the quota.
+type Quota struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Stats is the response of GET /stats.
+type Stats struct {
+	Listeners  []eventify.ListenerStats           `json:"listeners"`
+	Deliveries map[eventify.DeliveryStatus]uint64 `json:"deliveries"`
+}
+
+// Server is an http.Handler serving the control plane of a bus.
+type Server struct {
+	bus    *eventify.Eventify
+	log    eventify.Log
+	tokens [][]byte
+	replay func(ctx context.Context, req ReplayRequest) error
+	sync   *Sync
+	mux    *http.ServeMux
+}
+
+// OptionFunc is a function that configures a Server.
+type OptionFunc func(*Server)
+
+// WithLogger sets the logger for the Server.
+func WithLogger(log eventify.Log) OptionFunc {
+	return func(s *Server) {
+		s.log = log
+	}
+}
+
+// WithTokens adds the bearer tokens accepted by the Server.
+func WithTokens(tokens ...string) OptionFunc {
+	return func(s *Server) {
+		for _, token := range tokens {
+			if token != "" {
+				s.tokens = append(s.tokens, []byte(token))
+			}
+		}
+	}
+}
+
+// WithReplay sets the function POST /replay calls, typically re-emitting events from an event store
+// into the bus. It runs in the request, so the response reports its error.
+func WithReplay(replay func(ctx context.Context, req ReplayRequest) error) OptionFunc {
+	return func(s *Server) {
+		s.replay = replay
+	}
+}
+
+// WithSync applies pause, resume and quota operations fleet-wide through the Sync
+// instead of only to the local bus.
+func WithSync(sync *Sync) OptionFunc {
+	return func(s *Server) {
+		s.sync = sync
+	}
+}
+
+// New creates a new Server for the bus.
+func New(bus *eventify.Eventify, opts ...OptionFunc) *Server {
+	s := &Server{
+		bus: bus,
+		log: &eventify.NoLog{},
+		mux: http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("GET /listeners", s.listeners)
+	s.mux.HandleFunc("GET /stats", s.stats)
+	s.mux.HandleFunc("POST /labels/{label}/pause", s.pause)
+	s.mux.HandleFunc("POST /labels/{label}/resume", s.resume)
+	s.mux.HandleFunc("GET /producers", s.producers)
+	s.mux.HandleFunc("PUT /producers/{name}/quota", s.quota)
+	s.mux.HandleFunc("POST /replay", s.replayEvents)
+	s.mux.HandleFunc("POST /drain", s.drain)
+	return s
+}
+
+// ServeHTTP authenticates the request and serves the endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether the request carries one of the tokens.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), t) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listeners(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.bus.Registrations())
+}
+
+func (s *Server) stats(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, Stats{Listeners: s.bus.Stats(), Deliveries: s.bus.DeliveryCounts()})
+}
+
+func (s *Server) pause(w http.ResponseWriter, r *http.Request) {
+	label := r.PathValue("label")
+	s.log.Debug("control pause", "label", label)
+	if s.sync != nil {
+		s.respond(w, s.sync.Pause(label))
+		return
+	}
+	s.bus.PauseByLabel(label)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) resume(w http.ResponseWriter, r *http.Request) {
+	label := r.PathValue("label")
+	s.log.Debug("control resume", "label", label)
+	if s.sync != nil {
+		s.respond(w, s.sync.Resume(label))
+		return
+	}
+	s.bus.ResumeByLabel(label)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) producers(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.bus.ProducerStats())
+}
+
+func (s *Server) quota(w http.ResponseWriter, r *http.Request) {
+	var quota Quota
+	if err := json.NewDecoder(r.Body).Decode(&quota); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := r.PathValue("name")
+	s.log.Debug("control quota", "producer", name, "rate", quota.Rate, "burst", quota.Burst)
+	if s.sync != nil {
+		s.respond(w, s.sync.SetQuota(name, quota))
+		return
+	}
+	s.bus.SetProducerQuota(name, quota.Rate, quota.Burst)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) replayEvents(w http.ResponseWriter, r *http.Request) {
+	if s.replay == nil {
+		writeError(w, http.StatusNotImplemented, ErrReplayUnavailable)
+		return
+	}
+	var req ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.log.Debug("control replay", "after", req.After, "pattern", req.Pattern)
+	if err := s.replay(r.Context(), req); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) drain(w http.ResponseWriter, r *http.Request) {
+	timeout := defaultDrainTimeout
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	s.log.Debug("control drain", "timeout", timeout)
+	if err := s.bus.Drain(ctx); err != nil {
+		writeError(w, http.StatusGatewayTimeout, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respond answers 204 No Content, or the error.
+func (s *Server) respond(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,168 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, s *Server, method, target, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Authorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []OptionFunc
+		token  string
+		status int
+	}{
+		{name: "no tokens configured", token: "secret", status: http.StatusUnauthorized},
+		{name: "missing token", opts: []OptionFunc{WithTokens("secret")}, status: http.StatusUnauthorized},
+		{name: "wrong token", opts: []OptionFunc{WithTokens("secret")}, token: "guess", status: http.StatusUnauthorized},
+		{name: "valid token", opts: []OptionFunc{WithTokens("old", "secret")}, token: "secret", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(eventify.New(), tt.opts...)
+			rec := do(t, s, http.MethodGet, "/listeners", tt.token, "")
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestServer_Listeners(t *testing.T) {
+	bus := eventify.New()
+	bus.Register("order.*", eventify.NewNamedListener("billing", nil), eventify.WithLabels("team=payments"))
+	s := New(bus, WithTokens("secret"))
+
+	rec := do(t, s, http.MethodPost, "/labels/team=payments/pause", "secret", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = do(t, s, http.MethodGet, "/listeners", "secret", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var registrations []eventify.Registration
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &registrations))
+	assert.Equal(t, []eventify.Registration{
+		{Pattern: "order.*", Listener: "billing", Labels: []string{"team=payments"}, Paused: true},
+	}, registrations)
+
+	rec = do(t, s, http.MethodPost, "/labels/team=payments/resume", "secret", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.False(t, bus.Registrations()[0].Paused)
+}
+
+func TestServer_Stats(t *testing.T) {
+	bus := eventify.New()
+	bus.Register("order.*", eventify.NewNamedListener("billing", nil))
+	bus.EmitBy("order.created", nil)
+	s := New(bus, WithTokens("secret"))
+
+	rec := do(t, s, http.MethodGet, "/stats", "secret", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stats Stats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	require.Len(t, stats.Listeners, 1)
+	assert.Equal(t, "billing", stats.Listeners[0].Listener)
+	assert.Equal(t, map[eventify.DeliveryStatus]uint64{eventify.StatusDelivered: 1}, stats.Deliveries)
+}
+
+func TestServer_Quota(t *testing.T) {
+	bus := eventify.New()
+	s := New(bus, WithTokens("secret"))
+
+	rec := do(t, s, http.MethodPut, "/producers/chatty/quota", "secret", `{"rate": 0.001, "burst": 1}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NoError(t, bus.Producer("chatty").EmitBy("log.line", nil))
+	assert.ErrorIs(t, bus.Producer("chatty").EmitBy("log.line", nil), eventify.ErrQuotaExceeded)
+
+	rec = do(t, s, http.MethodGet, "/producers", "secret", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var producers map[string]eventify.ProducerStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &producers))
+	assert.Equal(t, map[string]eventify.ProducerStats{"chatty": {Emitted: 1, Rejected: 1}}, producers)
+
+	rec = do(t, s, http.MethodPut, "/producers/chatty/quota", "secret", `{"rate": "fast"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServer_Replay(t *testing.T) {
+	replayErr := errors.New("store unavailable")
+	tests := []struct {
+		name   string
+		opts   []OptionFunc
+		body   string
+		status int
+	}{
+		{name: "unavailable", body: `{}`, status: http.StatusNotImplemented},
+		{
+			name: "replayed",
+			opts: []OptionFunc{WithReplay(func(_ context.Context, req ReplayRequest) error {
+				if req != (ReplayRequest{After: "evt-1", Pattern: "order.*"}) {
+					return errors.New("unexpected request")
+				}
+				return nil
+			})},
+			body:   `{"after": "evt-1", "pattern": "order.*"}`,
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "failed",
+			opts:   []OptionFunc{WithReplay(func(context.Context, ReplayRequest) error { return replayErr })},
+			body:   `{}`,
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "invalid body",
+			opts:   []OptionFunc{WithReplay(func(context.Context, ReplayRequest) error { return nil })},
+			body:   `{`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(eventify.New(), append(tt.opts, WithTokens("secret"))...)
+			rec := do(t, s, http.MethodPost, "/replay", "secret", tt.body)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestServer_Drain(t *testing.T) {
+	bus := eventify.New()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	bus.Register("slow.event", eventify.NewNamedListener("slow", func(eventify.Event) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	s := New(bus, WithTokens("secret"))
+	go bus.EmitBy("slow.event", nil)
+	<-started
+
+	rec := do(t, s, http.MethodPost, "/drain?timeout=10ms", "secret", "")
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+
+	close(release)
+	rec = do(t, s, http.MethodPost, "/drain", "secret", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = do(t, s, http.MethodPost, "/drain?timeout=soon", "secret", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
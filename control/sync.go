@@ -0,0 +1,204 @@
+package control
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Types of the control events, see Sync.
+const (
+	CommandPrefix = "eventify.control."
+	CommandPause  = CommandPrefix + "pause"
+	CommandResume = CommandPrefix + "resume"
+	CommandQuota  = CommandPrefix + "quota"
+)
+
+var (
+	// ErrBadSignature is returned for control events whose signature does not verify.
+	ErrBadSignature = errors.New("control: bad signature")
+	// ErrStaleCommand is returned for control events issued outside the accepted window.
+	ErrStaleCommand = errors.New("control: stale command")
+	// ErrReplayedCommand is returned for control events whose nonce was already seen.
+	ErrReplayedCommand = errors.New("control: replayed command")
+	// ErrUnknownCommand is returned for control events of an unknown type.
+	ErrUnknownCommand = errors.New("control: unknown command")
+)
+
+// defaultSyncWindow is the default maximum clock difference accepted for control events.
+const defaultSyncWindow = 30 * time.Second
+
+// Command is the payload of a control event.
+type Command struct {
+	// Label is the label to pause or resume.
+	Label string `json:"label,omitempty"`
+	// Producer is the name of the producer whose quota is set.
+	Producer string `json:"producer,omitempty"`
+	Quota    *Quota `json:"quota,omitempty"`
+	// Issued is when the command was issued, checked against the window.
+	Issued time.Time `json:"issued"`
+	// Nonce is unique per command, so a command can't be applied twice.
+	Nonce string `json:"nonce"`
+	// Signature is the HMAC-SHA256 of the event type and the command without signature.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Sync applies control operations fleet-wide: operations are published as signed control events
+// on the bus, and every instance running a Sync with the same key applies the ones it receives,
+// including its own. The events reach the other instances through whatever bridges the bus,
+// which must forward the "eventify.control.*" types.
+// Control events that are not signed with the key, issued outside the window or already applied are rejected.
+type Sync struct {
+	bus      *eventify.Eventify
+	key      []byte
+	window   time.Duration
+	now      func() time.Time
+	listener eventify.Listener
+
+	mutex  sync.Mutex
+	nonces map[string]time.Time
+}
+
+// SyncOption is a function that configures a Sync.
+type SyncOption func(*Sync)
+
+// WithSyncWindow sets how far the issue time of a control event may be from the local clock, 30s by default.
+// Nonces are remembered long enough to reject replays within it.
+func WithSyncWindow(window time.Duration) SyncOption {
+	return func(s *Sync) {
+		s.window = window
+	}
+}
+
+// NewSync creates a Sync signing and verifying control events with the key shared by the fleet,
+// and starts applying the control events emitted on the bus.
+func NewSync(bus *eventify.Eventify, key []byte, opts ...SyncOption) *Sync {
+	s := &Sync{
+		bus:    bus,
+		key:    key,
+		window: defaultSyncWindow,
+		now:    time.Now,
+		nonces: map[string]time.Time{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.listener = eventify.NewNamedListener("control.sync", s.apply)
+	bus.Register(CommandPrefix+"*", s.listener)
+	return s
+}
+
+// Pause pauses the listeners with the label on every instance.
+func (s *Sync) Pause(label string) error {
+	return s.publish(CommandPause, Command{Label: label})
+}
+
+// Resume resumes the listeners with the label on every instance.
+func (s *Sync) Resume(label string) error {
+	return s.publish(CommandResume, Command{Label: label})
+}
+
+// SetQuota sets the quota of the producer on every instance.
+func (s *Sync) SetQuota(producer string, quota Quota) error {
+	return s.publish(CommandQuota, Command{Producer: producer, Quota: &quota})
+}
+
+// Close stops applying control events.
+func (s *Sync) Close() {
+	s.bus.Unregister(CommandPrefix+"*", s.listener)
+}
+
+// publish signs the command and emits it.
+func (s *Sync) publish(eventType string, cmd Command) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	cmd.Issued = s.now()
+	cmd.Nonce = hex.EncodeToString(nonce)
+	signature, err := s.sign(eventType, cmd)
+	if err != nil {
+		return err
+	}
+	cmd.Signature = signature
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	return s.bus.TryEmit(eventify.NewEvent(eventType, payload))
+}
+
+// sign returns the signature of the command without its own signature.
+func (s *Sync) sign(eventType string, cmd Command) ([]byte, error) {
+	cmd.Signature = nil
+	message, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(eventType))
+	mac.Write([]byte{0})
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
+
+// apply verifies the control event and applies it to the bus.
+func (s *Sync) apply(event eventify.Event) error {
+	var cmd Command
+	if err := json.Unmarshal(event.Payload(), &cmd); err != nil {
+		return fmt.Errorf("control: invalid command: %w", err)
+	}
+	if err := s.verify(event.Type(), cmd); err != nil {
+		return err
+	}
+	switch event.Type() {
+	case CommandPause:
+		s.bus.PauseByLabel(cmd.Label)
+	case CommandResume:
+		s.bus.ResumeByLabel(cmd.Label)
+	case CommandQuota:
+		if cmd.Quota == nil {
+			return fmt.Errorf("control: invalid command: quota is required")
+		}
+		s.bus.SetProducerQuota(cmd.Producer, cmd.Quota.Rate, cmd.Quota.Burst)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, strings.TrimPrefix(event.Type(), CommandPrefix))
+	}
+	return nil
+}
+
+// verify checks the signature, issue time and nonce of the command and remembers the nonce.
+func (s *Sync) verify(eventType string, cmd Command) error {
+	expected, err := s.sign(eventType, cmd)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expected, cmd.Signature) {
+		return ErrBadSignature
+	}
+	now := s.now()
+	if d := now.Sub(cmd.Issued); d > s.window || d < -s.window {
+		return ErrStaleCommand
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for nonce, seen := range s.nonces {
+		if now.Sub(seen) > 2*s.window {
+			delete(s.nonces, nonce)
+		}
+	}
+	if _, ok := s.nonces[cmd.Nonce]; ok {
+		return ErrReplayedCommand
+	}
+	s.nonces[cmd.Nonce] = now
+	return nil
+}
@@ -0,0 +1,128 @@
+package control
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bridge forwards the control events emitted on the bus to the peers, like a cluster transport would.
+func bridge(bus *eventify.Eventify, peers ...*eventify.Eventify) {
+	bus.Register(CommandPrefix+"*", eventify.NewListener(func(event eventify.Event) error {
+		for _, peer := range peers {
+			peer.Emit(event)
+		}
+		return nil
+	}))
+}
+
+// paused reports whether the billing listener is paused.
+func paused(bus *eventify.Eventify) bool {
+	for _, r := range bus.Registrations() {
+		if r.Listener == "billing" {
+			return r.Paused
+		}
+	}
+	return false
+}
+
+func TestSync(t *testing.T) {
+	key := []byte("fleet-key")
+	a, b := eventify.New(), eventify.New()
+	for _, bus := range []*eventify.Eventify{a, b} {
+		bus.Register("order.*", eventify.NewNamedListener("billing", nil), eventify.WithLabels("team=payments"))
+	}
+	syncA := NewSync(a, key)
+	NewSync(b, key)
+	bridge(a, b)
+
+	require.NoError(t, syncA.Pause("team=payments"))
+	assert.True(t, paused(a))
+	assert.True(t, paused(b))
+
+	require.NoError(t, syncA.SetQuota("chatty", Quota{Rate: 0.001, Burst: 1}))
+	for _, bus := range []*eventify.Eventify{a, b} {
+		require.NoError(t, bus.Producer("chatty").EmitBy("log.line", nil))
+		assert.ErrorIs(t, bus.Producer("chatty").EmitBy("log.line", nil), eventify.ErrQuotaExceeded)
+	}
+
+	require.NoError(t, syncA.Resume("team=payments"))
+	assert.False(t, paused(a))
+	assert.False(t, paused(b))
+
+	syncA.Close()
+	require.NoError(t, syncA.Pause("team=payments"))
+	assert.False(t, paused(a), "closed sync should not apply commands")
+	assert.True(t, paused(b))
+}
+
+func TestSync_Rejects(t *testing.T) {
+	key := []byte("fleet-key")
+	issuer := &Sync{key: key, now: time.Now}
+	sign := func(eventType string, cmd Command) eventify.Event {
+		signature, err := issuer.sign(eventType, cmd)
+		require.NoError(t, err)
+		cmd.Signature = signature
+		payload, err := json.Marshal(cmd)
+		require.NoError(t, err)
+		return eventify.NewEvent(eventType, payload)
+	}
+	valid := Command{Label: "team=payments", Issued: time.Now(), Nonce: "n1"}
+	tampered := sign(CommandPause, valid)
+	var cmd Command
+	require.NoError(t, json.Unmarshal(tampered.Payload(), &cmd))
+	cmd.Label = "team=growth"
+	payload, _ := json.Marshal(cmd)
+
+	tests := []struct {
+		name  string
+		key   []byte
+		event eventify.Event
+		want  error
+	}{
+		{name: "other key", key: []byte("other"), event: sign(CommandPause, valid), want: ErrBadSignature},
+		{name: "tampered", key: key, event: eventify.NewEvent(CommandPause, payload), want: ErrBadSignature},
+		{name: "other type", key: key, event: eventify.NewEvent(CommandResume, tampered.Payload()), want: ErrBadSignature},
+		{name: "stale", key: key, event: sign(CommandPause, Command{Label: "team=payments", Issued: time.Now().Add(-time.Hour), Nonce: "n2"}), want: ErrStaleCommand},
+		{name: "unknown", key: key, event: sign(CommandPrefix+"reboot", Command{Issued: time.Now(), Nonce: "n3"}), want: ErrUnknownCommand},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSync(eventify.New(), tt.key)
+			assert.ErrorIs(t, s.apply(tt.event), tt.want)
+		})
+	}
+
+	t.Run("replayed", func(t *testing.T) {
+		s := NewSync(eventify.New(), key)
+		event := sign(CommandPause, Command{Label: "team=payments", Issued: time.Now(), Nonce: "n4"})
+		require.NoError(t, s.apply(event))
+		assert.ErrorIs(t, s.apply(event), ErrReplayedCommand)
+	})
+}
+
+func TestServer_WithSync(t *testing.T) {
+	key := []byte("fleet-key")
+	a, b := eventify.New(), eventify.New()
+	for _, bus := range []*eventify.Eventify{a, b} {
+		bus.Register("order.*", eventify.NewNamedListener("billing", nil), eventify.WithLabels("team=payments"))
+	}
+	s := New(a, WithTokens("secret"), WithSync(NewSync(a, key)))
+	NewSync(b, key)
+	bridge(a, b)
+
+	rec := do(t, s, http.MethodPost, "/labels/team=payments/pause", "secret", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.True(t, paused(a))
+	assert.True(t, paused(b))
+
+	rec = do(t, s, http.MethodPut, "/producers/chatty/quota", "secret", `{"rate": 0.001, "burst": 1}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NoError(t, b.Producer("chatty").EmitBy("log.line", nil))
+	assert.ErrorIs(t, b.Producer("chatty").EmitBy("log.line", nil), eventify.ErrQuotaExceeded)
+}
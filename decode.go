@@ -0,0 +1,53 @@
+package eventify
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// DecodeCache caches decoded payloads.
+// Embed it in custom events to let DecodedPayload decode their payload once per type.
+type DecodeCache struct {
+	cache sync.Map
+}
+
+func (c *DecodeCache) decodeCache() *sync.Map {
+	return &c.cache
+}
+
+type decodeCacher interface {
+	decodeCache() *sync.Map
+}
+
+type decoded[T any] struct {
+	value T
+	err   error
+}
+
+// DecodedPayload decodes the JSON payload of the event into a T.
+// Events created by NewEvent, and custom events embedding DecodeCache, decode their payload
+// once per type and share the result with every listener, so the value must not be modified.
+// The value of an AnyEvent is returned as is if it is a T.
+func DecodedPayload[T any](event Event) (T, error) {
+	if anyEvent, ok := event.(AnyEvent); ok {
+		if value, ok := anyEvent.Value().(T); ok {
+			return value, nil
+		}
+	}
+	cacher, ok := event.(decodeCacher)
+	if !ok {
+		var value T
+		err := json.Unmarshal(event.Payload(), &value)
+		return value, err
+	}
+	cache := cacher.decodeCache()
+	key := reflect.TypeFor[T]()
+	if result, ok := cache.Load(key); ok {
+		return result.(*decoded[T]).value, result.(*decoded[T]).err
+	}
+	result := &decoded[T]{}
+	result.err = json.Unmarshal(event.Payload(), &result.value)
+	actual, _ := cache.LoadOrStore(key, result)
+	return actual.(*decoded[T]).value, actual.(*decoded[T]).err
+}
@@ -0,0 +1,47 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userCreated struct {
+	Name string `json:"name"`
+}
+
+func TestDecodedPayload(t *testing.T) {
+	t.Run("decodes once per event", func(t *testing.T) {
+		e := New()
+		var decodedValues []*userCreated
+		for i := 0; i < 3; i++ {
+			e.Register("user.created", NewListener(func(event Event) error {
+				user, err := DecodedPayload[*userCreated](event)
+				decodedValues = append(decodedValues, user)
+				return err
+			}))
+		}
+
+		e.EmitBy("user.created", userCreated{Name: "bob"})
+
+		require.Len(t, decodedValues, 3)
+		assert.Equal(t, "bob", decodedValues[0].Name)
+		assert.Same(t, decodedValues[0], decodedValues[2])
+	})
+
+	t.Run("caches errors", func(t *testing.T) {
+		event := NewEvent("user.created", []byte("not json"))
+
+		_, err := DecodedPayload[userCreated](event)
+		require.Error(t, err)
+		_, err2 := DecodedPayload[userCreated](event)
+		assert.Equal(t, err, err2)
+	})
+
+	t.Run("decodes custom events without cache", func(t *testing.T) {
+		user, err := DecodedPayload[userCreated](&mockErrorEvent{})
+		assert.Error(t, err, "nil payload is not valid JSON")
+		assert.Empty(t, user.Name)
+	})
+}
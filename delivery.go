@@ -0,0 +1,177 @@
+package eventify
+
+import (
+	"sync"
+	"time"
+
+	"github.com/payme50rmb/eventify/backoff"
+)
+
+// Guarantee is the delivery guarantee of the events matching a pattern.
+type Guarantee int
+
+const (
+	// DeliveryDefault delivers synchronously unless the event or the listener implements IsAsync.
+	DeliveryDefault Guarantee = iota
+	// BestEffort delivers asynchronously without retries, suited for telemetry.
+	BestEffort
+	// AtLeastOnce delivers asynchronously and redelivers to a failing listener with backoff
+	// until it succeeds, fails with a Terminal error or the attempts run out, then reports the error
+	// to the ErrorHandler.
+	// Pending redeliveries are held in memory and do not survive a restart.
+	AtLeastOnce
+	// OrderedPerKey delivers asynchronously, one event at a time per key, in emit order.
+	// The key is the event's Key if it implements Keyed, otherwise its type.
+	OrderedPerKey
+)
+
+const (
+	atLeastOnceAttempts   = 10
+	atLeastOnceMinBackoff = 10 * time.Millisecond
+	atLeastOnceMaxBackoff = 5 * time.Second
+)
+
+// defaultRetryBackoff is the backoff of AtLeastOnce redeliveries unless set with WithRetryBackoff.
+func defaultRetryBackoff() backoff.Backoff {
+	return backoff.Capped(backoff.Exponential(atLeastOnceMinBackoff), atLeastOnceMaxBackoff)
+}
+
+type deliveryRule struct {
+	pattern   string
+	matcher   *Matcher
+	guarantee Guarantee
+}
+
+// _Guarantee returns the guarantee of the first delivery rule matching the event type.
+func (e *Eventify) _Guarantee(eventType string) Guarantee {
+	for _, rule := range e.deliveries {
+		if rule.matcher.Match(eventType) {
+			return rule.guarantee
+		}
+	}
+	return DeliveryDefault
+}
+
+// _Deliver dispatches the event to the listeners according to the guarantee and, for the default one, the mode.
+// The asynchronous deliveries are queued for the producer of the emit, see WithFairDispatch.
+// The done function, if any, is called with the final result of every listener.
+func (e *Eventify) _Deliver(event Event, listeners []Listener, guarantee Guarantee, config *emitConfig, done func(error)) {
+	switch guarantee {
+	case BestEffort:
+		for _, listener := range listeners {
+			e._Trigger(event, listener, true, config.producer, done)
+		}
+	case AtLeastOnce:
+		size := eventSize(event)
+		for _, listener := range listeners {
+			e.memory.async.Add(size)
+			e._Go(config.producer, func() {
+				e._DeliverAtLeastOnce(event, listener, e.retryBackoff(), 1, func(err error) {
+					defer e.memory.async.Add(-size)
+					defer e.inflight.Release(listener)
+					if errHandler, ok := event.(ErrorHandler); ok && err != nil {
+						errHandler.ErrorHandler(event, err)
+					}
+					e.ReportDelivery(event, listener, StatusOf(err), err)
+					if done != nil {
+						done(err)
+					}
+				})
+			})
+		}
+	case OrderedPerKey:
+		key := event.Type()
+		if keyed, ok := event.(Keyed); ok {
+			key = keyed.Key()
+		}
+		size := eventSize(event)
+		e.memory.queued.Add(size)
+		e.ordered.Enqueue(key, func() {
+			defer e.memory.queued.Add(-size)
+			for _, listener := range listeners {
+				e._Trigger(event, listener, false, config.producer, done)
+			}
+		})
+	default:
+		_, isAsyncEvent := event.(IsAsync)
+		isAsyncEvent = isAsyncEvent || e._AsyncType(e._Normalize(event.Type()))
+		for _, listener := range listeners {
+			_, isAsyncListener := listener.(IsAsync)
+			async := isAsyncEvent || isAsyncListener
+			mode := config.mode
+			if mode == dispatchDefault && e.syncDispatch {
+				mode = dispatchSync
+			}
+			switch mode {
+			case dispatchAsync:
+				async = true
+			case dispatchSync:
+				async = false
+			}
+			e._Trigger(event, listener, async, config.producer, done)
+		}
+	}
+}
+
+// _DeliverAtLeastOnce makes the attempt to deliver the event to the listener and, if it failed,
+// schedules the next one after the backoff. The finish function is called with the final result.
+func (e *Eventify) _DeliverAtLeastOnce(event Event, listener Listener, delays backoff.Backoff, attempt int, finish func(error)) {
+	err := e._Handle(event, listener)
+	if err == nil {
+		finish(nil)
+		return
+	}
+	log := withEventFields(e.log, event, listener)
+	if IsTerminal(err) {
+		log.Debug("eventify listener failed terminally", "event", event.Type(), "attempt", attempt, "status", StatusFailed, "error", err)
+		finish(err)
+		return
+	}
+	if attempt == atLeastOnceAttempts {
+		log.Debug("eventify listener failed", "event", event.Type(), "attempt", attempt, "status", StatusFailed, "error", err)
+		finish(err)
+		return
+	}
+	log.Debug("eventify listener failed, redelivering", "event", event.Type(), "attempt", attempt, "error", err)
+	e.scheduler.AfterFunc(delays.Next(), func() {
+		e._DeliverAtLeastOnce(event, listener, delays, attempt+1, finish)
+	})
+}
+
+// keyedQueue runs tasks one at a time per key, in the order they were enqueued.
+type keyedQueue struct {
+	scheduler Scheduler
+	mutex     sync.Mutex
+	queues    map[string][]func()
+}
+
+func newKeyedQueue(scheduler Scheduler) *keyedQueue {
+	return &keyedQueue{scheduler: scheduler, queues: map[string][]func(){}}
+}
+
+// Enqueue schedules the task after the pending tasks of the key.
+func (q *keyedQueue) Enqueue(key string, task func()) {
+	q.mutex.Lock()
+	pending, running := q.queues[key]
+	q.queues[key] = append(pending, task)
+	q.mutex.Unlock()
+	if !running {
+		q.scheduler.Go(func() { q.drain(key) })
+	}
+}
+
+func (q *keyedQueue) drain(key string) {
+	for {
+		q.mutex.Lock()
+		pending := q.queues[key]
+		if len(pending) == 0 {
+			delete(q.queues, key)
+			q.mutex.Unlock()
+			return
+		}
+		task := pending[0]
+		q.queues[key] = pending[1:]
+		q.mutex.Unlock()
+		task()
+	}
+}
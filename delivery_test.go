@@ -0,0 +1,88 @@
+package eventify
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type keyedEvent struct {
+	Event
+	key string
+}
+
+func (k *keyedEvent) Key() string { return k.key }
+
+func TestEventify_DeliveryOrderedPerKey(t *testing.T) {
+	e := NewEventify(WithDelivery("order.*", OrderedPerKey))
+	var mutex sync.Mutex
+	got := map[string][]string{}
+	var wg sync.WaitGroup
+	e.Register("order.*", NewListener(func(event Event) error {
+		defer wg.Done()
+		// Slow down the first events so later ones would overtake them if delivery were unordered.
+		if string(event.Payload()) == "1" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		key := event.(Keyed).Key()
+		got[key] = append(got[key], string(event.Payload()))
+		return nil
+	}))
+
+	for _, payload := range []string{"1", "2", "3"} {
+		for _, key := range []string{"a", "b"} {
+			wg.Add(1)
+			e.Emit(&keyedEvent{Event: NewEvent("order.updated", []byte(payload)), key: key})
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string][]string{"a": {"1", "2", "3"}, "b": {"1", "2", "3"}}, got)
+}
+
+func TestEventify_DeliveryAtLeastOnce(t *testing.T) {
+	e := NewEventify(WithDelivery("payment.*", AtLeastOnce))
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	e.Register("payment.captured", NewListener(func(event Event) error {
+		if attempts.Add(1) < 3 {
+			return assert.AnError
+		}
+		close(done)
+		return nil
+	}))
+
+	e.EmitBy("payment.captured", nil)
+
+	select {
+	case <-done:
+		assert.Equal(t, int32(3), attempts.Load())
+	case <-time.After(time.Second):
+		t.Fatal("event not redelivered")
+	}
+}
+
+func TestEventify_DeliveryBestEffort(t *testing.T) {
+	e := NewEventify(WithDelivery("metrics.*", BestEffort))
+	release := make(chan struct{})
+	done := make(chan struct{})
+	e.Register("metrics.tick", NewListener(func(event Event) error {
+		<-release
+		close(done)
+		return nil
+	}))
+
+	e.EmitBy("metrics.tick", nil)
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("best effort delivery should not block the emitter")
+	}
+}
@@ -0,0 +1,163 @@
+package eventify
+
+import (
+	"context"
+	"sync"
+)
+
+// UnregisterAndDrain removes the listener for the event type pattern, like Unregister, then waits
+// until the deliveries to the listener that were already under way have finished, so the
+// resources it holds can be closed safely afterwards.
+// It returns the context's error if the context is done first.
+// Only listeners implementing Namable can be unregistered, and only comparable listeners can be drained.
+func (e *Eventify) UnregisterAndDrain(ctx context.Context, eventTypePattern string, listener Listener) error {
+	e.Unregister(eventTypePattern, listener)
+	return e.inflight.Wait(ctx, listener)
+}
+
+// Drain waits until the deliveries under way to every listener have finished, e.g. before a deploy
+// once the producers were stopped. A delivery is under way from the emit matching the listener, so
+// events held by WithReorderWindow or queued for ordered delivery are waited for, until they are delivered.
+// Only deliveries to comparable listeners are counted.
+// It returns the context's error if the context is done first.
+func (e *Eventify) Drain(ctx context.Context) error {
+	return e.inflight.WaitAll(ctx)
+}
+
+// RegisterWithContext adds the listener for the event type pattern, like Register, for as long as the context
+// lives: once it is done, the listener is unregistered and drained, making per-request or per-session
+// subscriptions safe by construction. The returned channel is closed once the deliveries to the listener
+// under way at cancellation have finished.
+// Only comparable listeners can be registered with a context. It panics with ErrSealed while the registry is sealed,
+// but the listener is removed once the context is done even if the registry was sealed since.
+func (e *Eventify) RegisterWithContext(ctx context.Context, eventTypePattern string, listener Listener, opts ...RegisterOption) <-chan struct{} {
+	drained := make(chan struct{})
+	if err := e._Register(eventTypePattern, listener, opts, callSite(2)); err != nil {
+		panic(err)
+	}
+	context.AfterFunc(ctx, func() {
+		defer close(drained)
+		e._Remove(e._Normalize(eventTypePattern), listener, "context done")
+		_ = e.inflight.Wait(context.Background(), listener)
+	})
+	return drained
+}
+
+// inflightTracker counts the deliveries under way per listener.
+// A delivery is acquired while the listeners are matched under the registry lock
+// and released once the listener is done with the event.
+type inflightTracker struct {
+	mutex   sync.Mutex
+	counts  map[any]int
+	idle    map[any]chan struct{}
+	onIdle  map[any][]func()
+	total   int
+	allIdle chan struct{}
+}
+
+func newInflightTracker() *inflightTracker {
+	return &inflightTracker{
+		counts: map[any]int{},
+		idle:   map[any]chan struct{}{},
+		onIdle: map[any][]func(){},
+	}
+}
+
+func (t *inflightTracker) Acquire(listener Listener) {
+	key := statsKey(listener)
+	if key == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.counts[key]++
+	t.total++
+}
+
+func (t *inflightTracker) Release(listener Listener) {
+	key := statsKey(listener)
+	if key == nil {
+		return
+	}
+	t.mutex.Lock()
+	t.total--
+	if t.total == 0 && t.allIdle != nil {
+		close(t.allIdle)
+		t.allIdle = nil
+	}
+	t.counts[key]--
+	if t.counts[key] > 0 {
+		t.mutex.Unlock()
+		return
+	}
+	delete(t.counts, key)
+	if idle, ok := t.idle[key]; ok {
+		close(idle)
+		delete(t.idle, key)
+	}
+	callbacks := t.onIdle[key]
+	delete(t.onIdle, key)
+	t.mutex.Unlock()
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+// OnIdle calls the function once no delivery to the listener is under way: right away if none is,
+// otherwise from the Release of the last one.
+func (t *inflightTracker) OnIdle(listener Listener, callback func()) {
+	key := statsKey(listener)
+	t.mutex.Lock()
+	if key == nil || t.counts[key] == 0 {
+		t.mutex.Unlock()
+		callback()
+		return
+	}
+	t.onIdle[key] = append(t.onIdle[key], callback)
+	t.mutex.Unlock()
+}
+
+// Wait waits until no delivery to the listener is under way.
+func (t *inflightTracker) Wait(ctx context.Context, listener Listener) error {
+	key := statsKey(listener)
+	if key == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	if t.counts[key] == 0 {
+		t.mutex.Unlock()
+		return nil
+	}
+	idle, ok := t.idle[key]
+	if !ok {
+		idle = make(chan struct{})
+		t.idle[key] = idle
+	}
+	t.mutex.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitAll waits until no delivery to any listener is under way.
+func (t *inflightTracker) WaitAll(ctx context.Context) error {
+	t.mutex.Lock()
+	if t.total == 0 {
+		t.mutex.Unlock()
+		return nil
+	}
+	if t.allIdle == nil {
+		t.allIdle = make(chan struct{})
+	}
+	idle := t.allIdle
+	t.mutex.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,121 @@
+package eventify
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_UnregisterAndDrain(t *testing.T) {
+	e := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	listener := &namedAsyncListener{name: "slow", handle: func(Event) error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return nil
+	}}
+	e.Register("slow.event", listener)
+	e.EmitBy("slow.event", nil)
+	<-started
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	require.NoError(t, e.UnregisterAndDrain(context.Background(), "slow.event", listener))
+
+	assert.True(t, finished.Load(), "drain should wait for the in-flight invocation")
+	assert.Empty(t, loadAllListeners(e)["slow.event"])
+}
+
+func TestEventify_UnregisterAndDrainTimeout(t *testing.T) {
+	e := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	listener := &namedAsyncListener{name: "stuck", handle: func(Event) error {
+		close(started)
+		<-release
+		return nil
+	}}
+	e.Register("stuck.event", listener)
+	e.EmitBy("stuck.event", nil)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, e.UnregisterAndDrain(ctx, "stuck.event", listener), context.DeadlineExceeded)
+}
+
+type namedAsyncListener struct {
+	IAmAsync
+	name   string
+	handle func(Event) error
+}
+
+func (l *namedAsyncListener) Name() string             { return l.name }
+func (l *namedAsyncListener) Handle(event Event) error { return l.handle(event) }
+
+func TestEventify_RegisterWithContext(t *testing.T) {
+	e := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	listener := &asyncListener{handle: func(Event) error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return nil
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	drained := e.RegisterWithContext(ctx, "session.event", listener)
+	e.EmitBy("session.event", nil)
+	<-started
+
+	cancel()
+	require.Eventually(t, func() bool { return len(loadAllListeners(e)["session.event"]) == 0 }, time.Second, time.Millisecond)
+	select {
+	case <-drained:
+		t.Fatal("drained before the in-flight invocation finished")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	<-drained
+	assert.True(t, finished.Load())
+}
+
+func TestEventify_Drain(t *testing.T) {
+	e := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	e.Register("slow.event", &namedAsyncListener{name: "slow", handle: func(Event) error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return nil
+	}})
+	require.NoError(t, e.Drain(context.Background()), "nothing under way")
+	e.EmitBy("slow.event", nil)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, e.Drain(ctx), context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	require.NoError(t, e.Drain(context.Background()))
+	assert.True(t, finished.Load(), "drain should wait for the in-flight invocation")
+}
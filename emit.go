@@ -0,0 +1,136 @@
+package eventify
+
+import "errors"
+
+var (
+	// ErrNilEvent is returned when a nil event is emitted.
+	ErrNilEvent = errors.New("eventify: nil event")
+	// ErrEmptyEventType is returned when an event without a type is emitted.
+	ErrEmptyEventType = errors.New("eventify: empty event type")
+	// ErrMarshalPayload is returned when a payload can't be marshaled to JSON.
+	ErrMarshalPayload = errors.New("eventify: marshal payload")
+	// ErrStopped is returned when an event is emitted after Run returned.
+	ErrStopped = errors.New("eventify: bus stopped")
+)
+
+// EmitOption configures a single emit.
+type EmitOption func(*emitConfig)
+
+type emitConfig struct {
+	mode dispatchMode
+	// observe, if set, is called with the number of matched listeners and returns the function
+	// to call with the result of every one of them.
+	observe func(listeners int) func(error)
+	// producer is the name of the producer emitting the event, empty if none.
+	producer string
+}
+
+// dispatchMode overrides whether the listeners of an emit run asynchronously.
+type dispatchMode int
+
+const (
+	dispatchDefault dispatchMode = iota
+	dispatchAsync
+	dispatchSync
+)
+
+func newEmitConfig(opts []EmitOption) *emitConfig {
+	c := &emitConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithAsync dispatches the event to its listeners asynchronously, as if it implemented IsAsync.
+func WithAsync() EmitOption {
+	return func(c *emitConfig) {
+		c.mode = dispatchAsync
+	}
+}
+
+// WithSync dispatches the event to its listeners synchronously, ignoring the IsAsync markers
+// of the event and listeners and WithAsyncTypes, e.g. in a migration script that must wait for them.
+// The delivery guarantees configured with WithDelivery still apply.
+func WithSync() EmitOption {
+	return func(c *emitConfig) {
+		c.mode = dispatchSync
+	}
+}
+
+// joinDone returns a function calling both functions, either of which may be nil.
+func joinDone(a, b func(error)) func(error) {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return func(err error) {
+		a(err)
+		b(err)
+	}
+}
+
+// TryEmit is like Emit but returns an error instead of dropping the event silently:
+// ErrNilEvent or ErrEmptyEventType for invalid events, ErrStopped once Run returned,
+// and the rejection error, such as ErrPayloadTooLarge or ErrMemoryLimitExceeded, for rejected ones.
+// Listener errors are still reported to the event's ErrorHandler.
+func (e *Eventify) TryEmit(event Event, opts ...EmitOption) error {
+	if event == nil {
+		return ErrNilEvent
+	}
+	if e._Normalize(event.Type()) == "" {
+		return ErrEmptyEventType
+	}
+	return e._Emit(event, opts...)
+}
+
+// EmitCount is like Emit but returns the number of listeners the event was dispatched to,
+// so producers can detect events without consumers or fan-out explosions. Rejected events return 0.
+func (e *Eventify) EmitCount(event Event, opts ...EmitOption) int {
+	count := 0
+	opts = append(opts, func(c *emitConfig) {
+		c.observe = func(listeners int) func(error) {
+			count = listeners
+			return nil
+		}
+	})
+	e._Emit(event, opts...)
+	return count
+}
+
+// TryEmitBy is like EmitBy but returns an error instead of dropping the event silently.
+// A payload that can't be marshaled returns an error wrapping ErrMarshalPayload and the event is not emitted.
+func (e *Eventify) TryEmitBy(eventType string, payload any, opts ...EmitOption) error {
+	if event, ok := payload.(Event); ok {
+		return e.TryEmit(event, opts...)
+	}
+	bz, err := e._AnyToBytes(payload)
+	if err != nil {
+		return err
+	}
+	return e.TryEmit(NewEvent(e._Normalize(eventType), bz), opts...)
+}
+
+// MarshalErrorPolicy decides what EmitBy does when the payload can't be marshaled.
+// It is called with the event type and the error, which wraps ErrMarshalPayload,
+// and reports whether the event is still emitted, with a nil payload.
+type MarshalErrorPolicy func(eventType string, err error) bool
+
+var (
+	// MarshalNilPayload emits the event with a nil payload. It is the default policy.
+	MarshalNilPayload MarshalErrorPolicy = func(string, error) bool { return true }
+	// MarshalDrop drops the event.
+	MarshalDrop MarshalErrorPolicy = func(string, error) bool { return false }
+	// MarshalPanic panics with the error, surfacing the bug immediately. It is meant for development.
+	MarshalPanic MarshalErrorPolicy = func(_ string, err error) bool { panic(err) }
+)
+
+// MarshalReport drops the event and reports the error to the handler.
+func MarshalReport(handler func(eventType string, err error)) MarshalErrorPolicy {
+	return func(eventType string, err error) bool {
+		handler(eventType, err)
+		return false
+	}
+}
@@ -0,0 +1,142 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_TryEmit(t *testing.T) {
+	e := NewEventify(WithMaxPayloadSize(4))
+	var received []string
+	e.Register("*", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+
+	tests := []struct {
+		name string
+		emit func() error
+		want error
+	}{
+		{"valid event", func() error { return e.TryEmit(NewEvent("user.created", nil)) }, nil},
+		{"valid payload", func() error { return e.TryEmitBy("user.updated", "1234") }, nil},
+		{"nil event", func() error { return e.TryEmit(nil) }, ErrNilEvent},
+		{"empty type", func() error { return e.TryEmitBy("", "1") }, ErrEmptyEventType},
+		{"marshal failure", func() error { return e.TryEmitBy("user.deleted", make(chan int)) }, ErrMarshalPayload},
+		{"rejected", func() error { return e.TryEmitBy("user.deleted", "12345") }, ErrPayloadTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.emit()
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+	assert.Equal(t, []string{"user.created", "user.updated"}, received)
+}
+
+func TestEventify_EmitNil(t *testing.T) {
+	e := New()
+	assert.NotPanics(t, func() { e.Emit(nil) })
+	assert.Zero(t, e.EmitCount(nil))
+}
+
+func TestEventify_TryEmitStopped(t *testing.T) {
+	e := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, e.TryEmit(NewEvent("user.created", nil)))
+
+	require.NoError(t, e.Run(ctx))
+	assert.ErrorIs(t, e.TryEmit(NewEvent("user.created", nil)), ErrStopped)
+	assert.ErrorIs(t, e.TryEmitBy("user.created", "1"), ErrStopped)
+}
+
+func TestEventify_MarshalErrorPolicy(t *testing.T) {
+	var reported []string
+	tests := []struct {
+		name    string
+		policy  MarshalErrorPolicy
+		emitted bool
+	}{
+		{"nil payload", MarshalNilPayload, true},
+		{"drop", MarshalDrop, false},
+		{"report", MarshalReport(func(eventType string, err error) {
+			assert.ErrorIs(t, err, ErrMarshalPayload)
+			reported = append(reported, eventType)
+		}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEventify(WithMarshalErrorPolicy(tt.policy))
+			emitted := false
+			e.Register("bad.payload", NewListener(func(event Event) error {
+				emitted = true
+				assert.Nil(t, event.Payload())
+				return nil
+			}))
+
+			e.EmitBy("bad.payload", make(chan int))
+
+			assert.Equal(t, tt.emitted, emitted)
+		})
+	}
+	assert.Equal(t, []string{"bad.payload"}, reported)
+
+	t.Run("panic", func(t *testing.T) {
+		e := NewEventify(WithMarshalErrorPolicy(MarshalPanic))
+		assert.Panics(t, func() { e.EmitBy("bad.payload", make(chan int)) })
+	})
+}
+
+func TestEventify_EmitCount(t *testing.T) {
+	e := NewEventify(WithMaxPayloadSize(4))
+	e.Register("user.*", NewListener(func(Event) error { return nil }))
+	e.Register("user.created", NewListener(func(Event) error { return nil }))
+
+	tests := []struct {
+		name  string
+		event Event
+		want  int
+	}{
+		{"fan-out", NewEvent("user.created", nil), 2},
+		{"one listener", NewEvent("user.deleted", nil), 1},
+		{"no listeners", NewEvent("order.created", nil), 0},
+		{"rejected", NewEvent("user.created", []byte("12345")), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, e.EmitCount(tt.event))
+		})
+	}
+}
+
+type emittingCloser struct {
+	namedListener
+	close func() error
+}
+
+func (l *emittingCloser) Close(context.Context) error { return l.close() }
+
+func TestEventify_StoppedBeforeClose(t *testing.T) {
+	e := New()
+	var closeErr error
+	e.Register("user.created", &emittingCloser{
+		namedListener: namedListener{name: "closer", handle: func(Event) error { return nil }},
+		close: func() error {
+			closeErr = e.TryEmit(NewEvent("user.created", nil))
+			return nil
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, e.Run(ctx))
+	assert.ErrorIs(t, closeErr, ErrStopped)
+}
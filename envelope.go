@@ -0,0 +1,170 @@
+package eventify
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// EnvelopeVersion is the version of the envelope encoding written by MarshalBinary.
+const EnvelopeVersion = 1
+
+// Codecs of envelope payloads.
+const (
+	CodecJSON  = "json"
+	CodecBytes = "bytes"
+)
+
+// Metadata keys set by NewEnvelope from the optional event interfaces.
+const (
+	MetadataCorrelationID = "correlation_id"
+	MetadataCausationID   = "causation_id"
+	MetadataKey           = "key"
+)
+
+// ErrInvalidEnvelope is returned when an envelope can't be decoded.
+var ErrInvalidEnvelope = errors.New("eventify: invalid envelope")
+
+// Envelope is the encoding-neutral form of an event for anything that persists or transports events.
+// Its binary encoding is a version byte followed by tagged fields; readers skip the tags they don't know
+// and leave the fields that are missing empty, so envelopes written by one version stay readable by
+// older and newer ones.
+type Envelope struct {
+	Type     string
+	ID       string
+	Metadata map[string]string
+	// Codec describes the payload: CodecJSON or CodecBytes.
+	Codec   string
+	Payload []byte
+}
+
+// Envelope field tags. Tags are never reused; new fields get new tags.
+const (
+	tagType     = 1
+	tagID       = 2
+	tagMetadata = 3
+	tagCodec    = 4
+	tagPayload  = 5
+)
+
+// NewEnvelope creates the envelope of the event, with its ID if it implements Identifiable
+// and metadata from Correlatable, Caused and Keyed.
+func NewEnvelope(event Event) Envelope {
+	env := Envelope{Type: event.Type(), Codec: CodecBytes, Payload: event.Payload(), Metadata: map[string]string{}}
+	if json.Valid(env.Payload) {
+		env.Codec = CodecJSON
+	}
+	if identifiable, ok := event.(Identifiable); ok {
+		env.ID = identifiable.ID()
+	}
+	if correlatable, ok := event.(Correlatable); ok && correlatable.CorrelationID() != "" {
+		env.Metadata[MetadataCorrelationID] = correlatable.CorrelationID()
+	}
+	if caused, ok := event.(Caused); ok && caused.CausationID() != "" {
+		env.Metadata[MetadataCausationID] = caused.CausationID()
+	}
+	if keyed, ok := event.(Keyed); ok && keyed.Key() != "" {
+		env.Metadata[MetadataKey] = keyed.Key()
+	}
+	return env
+}
+
+// Event returns the event of the envelope. It implements Identifiable, Correlatable, Caused and Keyed
+// with the ID and metadata of the envelope.
+func (env Envelope) Event() Event {
+	return &envelopeEvent{event: event{eventType: env.Type, payload: env.Payload}, envelope: env}
+}
+
+// MarshalBinary encodes the envelope.
+func (env Envelope) MarshalBinary() ([]byte, error) {
+	buf := []byte{EnvelopeVersion}
+	buf = appendField(buf, tagType, []byte(env.Type))
+	if env.ID != "" {
+		buf = appendField(buf, tagID, []byte(env.ID))
+	}
+	keys := make([]string, 0, len(env.Metadata))
+	for key := range env.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry := binary.AppendUvarint(nil, uint64(len(key)))
+		entry = append(entry, key...)
+		entry = append(entry, env.Metadata[key]...)
+		buf = appendField(buf, tagMetadata, entry)
+	}
+	if env.Codec != "" {
+		buf = appendField(buf, tagCodec, []byte(env.Codec))
+	}
+	buf = appendField(buf, tagPayload, env.Payload)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes an envelope of any version, skipping unknown fields.
+func (env *Envelope) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] == 0 {
+		return fmt.Errorf("%w: missing version", ErrInvalidEnvelope)
+	}
+	*env = Envelope{Metadata: map[string]string{}}
+	data = data[1:]
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("%w: bad tag", ErrInvalidEnvelope)
+		}
+		data = data[n:]
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return fmt.Errorf("%w: bad field %d", ErrInvalidEnvelope, tag)
+		}
+		value := data[n : n+int(size)]
+		data = data[n+int(size):]
+		switch tag {
+		case tagType:
+			env.Type = string(value)
+		case tagID:
+			env.ID = string(value)
+		case tagMetadata:
+			keySize, n := binary.Uvarint(value)
+			if n <= 0 || keySize > uint64(len(value)-n) {
+				return fmt.Errorf("%w: bad metadata", ErrInvalidEnvelope)
+			}
+			env.Metadata[string(value[n:n+int(keySize)])] = string(value[n+int(keySize):])
+		case tagCodec:
+			env.Codec = string(value)
+		case tagPayload:
+			env.Payload = bytes.Clone(value)
+		}
+	}
+	return nil
+}
+
+func appendField(buf []byte, tag uint64, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, tag)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+type envelopeEvent struct {
+	event
+	envelope Envelope
+}
+
+func (e *envelopeEvent) ID() string {
+	return e.envelope.ID
+}
+
+func (e *envelopeEvent) CorrelationID() string {
+	return e.envelope.Metadata[MetadataCorrelationID]
+}
+
+func (e *envelopeEvent) CausationID() string {
+	return e.envelope.Metadata[MetadataCausationID]
+}
+
+func (e *envelopeEvent) Key() string {
+	return e.envelope.Metadata[MetadataKey]
+}
@@ -0,0 +1,79 @@
+package eventify
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	event := &causedEvent{Event: NewEvent("stock.reserved", []byte("not json")), id: "evt-2", causationID: "evt-1"}
+
+	bz, err := NewEnvelope(event).MarshalBinary()
+	require.NoError(t, err)
+	var env Envelope
+	require.NoError(t, env.UnmarshalBinary(bz))
+
+	assert.Equal(t, "stock.reserved", env.Type)
+	assert.Equal(t, "evt-2", env.ID)
+	assert.Equal(t, CodecBytes, env.Codec)
+	assert.Equal(t, map[string]string{MetadataCausationID: "evt-1"}, env.Metadata)
+	decoded := env.Event()
+	assert.Equal(t, []byte("not json"), decoded.Payload())
+	assert.Equal(t, "evt-2", decoded.(Identifiable).ID())
+	assert.Equal(t, "evt-1", decoded.(Caused).CausationID())
+}
+
+func TestEnvelope_ReadsV1(t *testing.T) {
+	data, err := os.ReadFile("testdata/envelope/v1.hex")
+	require.NoError(t, err)
+	bz, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	require.NoError(t, err)
+
+	var env Envelope
+	require.NoError(t, env.UnmarshalBinary(bz))
+
+	assert.Equal(t, Envelope{
+		Type:     "order.created",
+		ID:       "evt-1",
+		Metadata: map[string]string{MetadataCorrelationID: "flow-1", MetadataKey: "order-42"},
+		Codec:    CodecJSON,
+		Payload:  []byte(`{"id":42}`),
+	}, env)
+}
+
+func TestEnvelope_SkipsUnknownFields(t *testing.T) {
+	bz, err := Envelope{Type: "order.created", Payload: []byte("1")}.MarshalBinary()
+	require.NoError(t, err)
+	// A newer writer: higher version and a field this reader doesn't know.
+	bz[0] = 3
+	bz = appendField(bz, 42, []byte("from the future"))
+
+	var env Envelope
+	require.NoError(t, env.UnmarshalBinary(bz))
+
+	assert.Equal(t, "order.created", env.Type)
+	assert.Equal(t, []byte("1"), env.Payload)
+	assert.Empty(t, env.ID)
+}
+
+func TestEnvelope_Invalid(t *testing.T) {
+	bz, err := Envelope{Type: "order.created", Payload: []byte("payload")}.MarshalBinary()
+	require.NoError(t, err)
+
+	tests := map[string][]byte{
+		"empty":     nil,
+		"version 0": {0},
+		"truncated": bz[:len(bz)-2],
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			var env Envelope
+			assert.ErrorIs(t, env.UnmarshalBinary(data), ErrInvalidEnvelope)
+		})
+	}
+}
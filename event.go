@@ -1,16 +1,44 @@
 package eventify
 
+import "bytes"
+
 // ErrorHandler is an interface that can be implemented by events to handle errors that occur during event processing.
 type ErrorHandler interface {
 	ErrorHandler(event Event, err error)
 }
 
+// Identifiable is an interface that can be implemented by events to expose a unique event ID.
+// The ID is attached to every log line produced while the event is dispatched.
+type Identifiable interface {
+	ID() string
+}
+
+// Correlatable is an interface that can be implemented by events to expose a correlation ID
+// shared by all events of the same flow.
+// The correlation ID is attached to every log line produced while the event is dispatched.
+type Correlatable interface {
+	CorrelationID() string
+}
+
+// Keyed is an interface that can be implemented by events to expose the key of the entity they are about,
+// such as an order ID. Events with the same key are related, e.g. for ordered delivery.
+type Keyed interface {
+	Key() string
+}
+
 // Event is an interface that represents an event.
+// The payload is shared by every listener of the event and must be treated as read-only;
+// listeners that need to modify it should work on PayloadCopy.
 type Event interface {
 	Type() string
 	Payload() []byte
 }
 
+// PayloadCopy returns a copy of the event's payload that the caller may modify.
+func PayloadCopy(event Event) []byte {
+	return bytes.Clone(event.Payload())
+}
+
 // NewEvent creates a new event with the specified type and payload.
 func NewEvent(eventType string, payload []byte) Event {
 	return &event{
@@ -20,6 +48,7 @@ func NewEvent(eventType string, payload []byte) Event {
 }
 
 type event struct {
+	DecodeCache
 	eventType string
 	payload   []byte
 }
@@ -1,8 +1,17 @@
 package eventify
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"runtime/pprof"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/payme50rmb/eventify/backoff"
 )
 
 // Eventify is a struct that represents an event emitter.
@@ -10,6 +19,58 @@ type Eventify struct {
 	listeners sync.Map
 	mutex     sync.RWMutex
 	log       Log
+	sink      *sinkWriter
+	tracer    *tracer
+	ordered   *keyedQueue
+	inflight  *inflightTracker
+	fair      *fairQueue
+	stats     sync.Map
+	memory    memoryUsage
+	producers sync.Map
+	matchers  sync.Map
+	aliases   *aliases
+	unmatched atomic.Pointer[func(Event)]
+	labels    map[any][]string
+	paused    map[string]bool
+	growth    map[string]*patternGrowth
+	sealed    atomic.Bool
+	stopped   atomic.Bool
+	matches   matchCache
+	inits     sync.Map
+	closing   sync.Map
+	resources Resources
+	last      sync.Map
+	guards    sync.Map
+	shadows   sync.Map
+
+	hasShadows     atomic.Bool
+	recordings     sync.Map
+	hasComparisons atomic.Bool
+
+	deliveryCounts deliveryCounts
+	deliveryHook   atomic.Pointer[func(DeliveryReport)]
+
+	modulesMutex sync.Mutex
+	modules      []Module
+
+	deliveries     []deliveryRule
+	producerQuotas map[string]producerQuota
+	memoryLimit    int64
+	profilerLabels bool
+	maxPayloadSize int
+	payloadGuard   bool
+	syncDispatch   bool
+	recoverPanics  bool
+	normalize      func(string) string
+	catalog        map[string]bool
+	marshalPolicy  MarshalErrorPolicy
+	compensations  map[string]string
+	outcomes       []*Matcher
+	retryBackoff   func() backoff.Backoff
+	scheduler      Scheduler
+	asyncTypes     []*Matcher
+	reorderers     []*reorderer
+	schemas        *schemaRegistry
 }
 
 // New creates a new Eventify instance with the default logger.
@@ -31,6 +92,62 @@ func NewEventify(opts ...OptionFunc) *Eventify {
 		listeners: sync.Map{},
 		mutex:     sync.RWMutex{},
 		log:       o.log,
+		ordered:   newKeyedQueue(o.scheduler),
+		inflight:  newInflightTracker(),
+		aliases:   newAliases(),
+		labels:    map[any][]string{},
+		paused:    map[string]bool{},
+		growth:    map[string]*patternGrowth{},
+
+		producerQuotas: o.producerQuotas,
+		memoryLimit:    o.memoryLimit,
+		profilerLabels: o.profilerLabels,
+		maxPayloadSize: o.maxPayloadSize,
+		payloadGuard:   o.payloadGuard,
+		syncDispatch:   o.syncDispatch,
+		recoverPanics:  o.recoverPanics,
+		normalize:      o.normalize,
+		marshalPolicy:  o.marshalPolicy,
+		retryBackoff:   o.retryBackoff,
+		scheduler:      o.scheduler,
+	}
+	for _, rule := range o.deliveries {
+		ev.deliveries = append(ev.deliveries, deliveryRule{matcher: NewMatcher(ev._Normalize(rule.pattern)), guarantee: rule.guarantee})
+	}
+	ev.compensations = map[string]string{}
+	for action, compensation := range o.compensations {
+		ev.compensations[ev._Normalize(action)] = ev._Normalize(compensation)
+	}
+	for _, pattern := range o.outcomes {
+		ev.outcomes = append(ev.outcomes, NewMatcher(ev._Normalize(pattern)))
+	}
+	for _, rule := range o.reorders {
+		ev.reorderers = append(ev.reorderers, &reorderer{eventify: ev, matcher: NewMatcher(ev._Normalize(rule.pattern)), window: rule.window})
+	}
+	for _, pattern := range o.asyncTypes {
+		ev.asyncTypes = append(ev.asyncTypes, NewMatcher(ev._Normalize(pattern)))
+	}
+	if len(o.schemaPatterns) > 0 {
+		matchers := []*Matcher{}
+		for _, pattern := range o.schemaPatterns {
+			matchers = append(matchers, NewMatcher(ev._Normalize(pattern)))
+		}
+		ev.schemas = newSchemaRegistry(matchers)
+	}
+	if len(o.catalog) > 0 {
+		ev.catalog = map[string]bool{}
+		for _, eventType := range o.catalog {
+			ev.catalog[ev._Normalize(eventType)] = true
+		}
+	}
+	if o.fairWorkers > 0 {
+		ev.fair = newFairQueue(o.scheduler, o.fairWorkers)
+	}
+	if o.traceCapacity > 0 {
+		ev.tracer = newTracer(o.traceCapacity)
+	}
+	if o.sink != nil {
+		ev.sink = newSinkWriter(o.sink, o.sinkSampleRate, o.log, &ev.memory.sink)
 	}
 	return ev
 }
@@ -38,13 +155,49 @@ func NewEventify(opts ...OptionFunc) *Eventify {
 // Register adds an event listener for the specified event type.
 // The listener will be called whenever an event of the matching type is emitted.
 // Multiple listeners can be registered for the same event type.
+// Options such as WithLabels configure the registration.
+// Emits that already took their snapshot of the listeners, see Emit, don't deliver to the new listener.
 // This method is thread-safe.
-func (e *Eventify) Register(eventTypePattern string, listener Listener) {
+// It panics with ErrSealed while the registry is sealed.
+func (e *Eventify) Register(eventTypePattern string, listener Listener, opts ...RegisterOption) {
+	if err := e._Register(eventTypePattern, listener, opts, callSite(2)); err != nil {
+		panic(err)
+	}
+}
+
+// TryRegister adds the listener like Register, but returns errors instead of registering a listener
+// that can never be called: an error wrapping ErrInvalidPattern for a glob that can't be compiled,
+// and ErrSealed while the registry is sealed. Use it for patterns coming from remote clients.
+// This method is thread-safe.
+func (e *Eventify) TryRegister(eventTypePattern string, listener Listener, opts ...RegisterOption) error {
+	if err := ValidatePattern(e._Normalize(eventTypePattern)); err != nil {
+		return err
+	}
+	return e._Register(eventTypePattern, listener, opts, callSite(2))
+}
+
+// _Register adds the listener, recording the call site it was registered from.
+// It returns ErrSealed while the registry is sealed.
+func (e *Eventify) _Register(eventTypePattern string, listener Listener, opts []RegisterOption, site string) error {
+	eventTypePattern = e._Normalize(eventTypePattern)
+	if err := ValidatePattern(eventTypePattern); err != nil {
+		e.log.Debug("eventify pattern matches nothing", "pattern", eventTypePattern, "error", err)
+	}
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
+	if e.sealed.Load() {
+		return ErrSealed
+	}
 	listeners, _ := e.listeners.LoadOrStore(eventTypePattern, []Listener{})
-	e.listeners.Store(eventTypePattern, append(listeners.([]Listener), listener))
+	e.listeners.Store(eventTypePattern, append(slices.Clip(listeners.([]Listener)), listener))
+	r := newRegistration(opts)
+	e._Label(listener, r)
+	e._Guard(listener, r)
+	e._MarkShadow(listener, r)
+	e._Grown(eventTypePattern, site)
+	e._Expire(eventTypePattern, listener, r)
 	e.log.Debug("eventify register", "event_type_pattern", eventTypePattern, "listener", listener)
+	return nil
 }
 
 // Unregister removes event listeners for the specified event type.
@@ -52,10 +205,17 @@ func (e *Eventify) Register(eventTypePattern string, listener Listener) {
 // If specific listeners are provided, only those listeners will be removed.
 // This method is thread-safe.
 func (e *Eventify) Unregister(eventTypePattern string, listeners ...Listener) {
+	eventTypePattern = e._Normalize(eventTypePattern)
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
+	e._CheckSealed()
 	if len(listeners) == 0 {
-		e.listeners.Delete(eventTypePattern)
+		ls, ok := e.listeners.LoadAndDelete(eventTypePattern)
+		e.matchers.Delete(eventTypePattern)
+		delete(e.growth, eventTypePattern)
+		if ok {
+			e._Forget(ls.([]Listener))
+		}
 		return
 	}
 	e.log.Debug("eventify unregister", "event_type_pattern", eventTypePattern, "listeners", listeners)
@@ -85,85 +245,322 @@ func (e *Eventify) Unregister(eventTypePattern string, listeners ...Listener) {
 	if len(lsCopy) == 0 {
 		return
 	}
+	removed := []Listener{}
 	for _, listener := range lsCopy {
+		kept := false
 		for _, l := range namedListeners {
 			if listener.Name() != l.Name() {
 				newLs = append(newLs, listener.(Listener))
+				kept = true
 			}
 		}
+		if !kept {
+			removed = append(removed, listener.(Listener))
+		}
 	}
 	e.listeners.Store(eventTypePattern, newLs)
+	e._Forget(removed)
+	if len(removed) > 0 {
+		delete(e.growth, eventTypePattern)
+	}
+}
+
+// Replace atomically swaps the listener with the name registered for the event type pattern
+// with the new listener, keeping its position and labels. Every event is delivered to exactly one of them:
+// emits that matched the old listener before the swap still go to it, later ones go to the new one.
+// It reports whether a listener with the name was found.
+// This method is thread-safe.
+func (e *Eventify) Replace(eventTypePattern string, name string, listener Listener) bool {
+	eventTypePattern = e._Normalize(eventTypePattern)
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e._CheckSealed()
+	ls, ok := e.listeners.Load(eventTypePattern)
+	if !ok {
+		return false
+	}
+	listeners := append([]Listener{}, ls.([]Listener)...)
+	for i, l := range listeners {
+		if namable, ok := l.(Namable); ok && namable.Name() == name {
+			listeners[i] = listener
+			e.listeners.Store(eventTypePattern, listeners)
+			labels := e.labels[statsKey(l)]
+			e._Forget([]Listener{l})
+			if key := statsKey(listener); key != nil && labels != nil {
+				e.labels[key] = labels
+			}
+			e.log.Debug("eventify replace", "event_type_pattern", eventTypePattern, "name", name, "listener", listener)
+			return true
+		}
+	}
+	return false
 }
 
 // Emit dispatches an event to all registered listeners for the event's type.
-// The event is processed synchronously unless the event or listener implements IsAsync.
+// The event is processed synchronously unless the event or listener implements IsAsync,
+// its type is configured with WithAsyncTypes, or a delivery guarantee is configured for its type with WithDelivery.
+// Events held by a reordering window, see WithReorderWindow, are delivered once released.
+// Validators, listeners implementing IsValidator, always run synchronously first and may reject the event.
 // If the event implements ErrorHandler, any errors from listeners will be handled asynchronously.
-func (e *Eventify) Emit(event Event) {
-	e._Emit(event)
+//
+// Every emit delivers to a snapshot of the listeners taken once, atomically with respect to
+// Register, Unregister and Replace, before any listener runs. Listeners registered after the snapshot,
+// including by a listener of the event itself, only receive later events; listeners unregistered
+// after the snapshot still receive the event; UnregisterAndDrain waits for those deliveries.
+//
+// Options such as WithAsync and WithSync override the async markers for this emit only.
+// A nil event is logged and dropped.
+func (e *Eventify) Emit(event Event, opts ...EmitOption) {
+	e._Emit(event, opts...)
 }
 
 // EmitBy creates and emits a new event with the specified type and payload.
 // If the payload is already an Event, it will be emitted directly.
 // Otherwise, a new event is created with the given type and payload.
-// The payload will be automatically converted to bytes using JSON marshaling if needed.
-func (e *Eventify) EmitBy(eventType string, payload any) {
+// The payload will be automatically converted to bytes using JSON marshaling if needed;
+// marshal failures are handled by the policy set with WithMarshalErrorPolicy.
+func (e *Eventify) EmitBy(eventType string, payload any, opts ...EmitOption) {
 	if event, ok := payload.(Event); ok {
-		e._Emit(event)
+		e._Emit(event, opts...)
 		return
 	}
-	e._Emit(NewEvent(eventType, e._AnyToBytes(payload)))
+	bz, err := e._AnyToBytes(payload)
+	if err != nil {
+		e.log.Debug("eventify marshal payload failed", "event", eventType, "error", err)
+		if !e.marshalPolicy(eventType, err) {
+			return
+		}
+	}
+	e._Emit(NewEvent(e._Normalize(eventType), bz), opts...)
 }
 
-func (e *Eventify) _Emit(event Event) {
-	listeners := e._MatchedListeners(event.Type())
-	_, isAsyncEvent := event.(IsAsync)
-	for _, listener := range listeners {
-		_, isAsyncListener := listener.(IsAsync)
-		e._Trigger(event, listener, isAsyncEvent || isAsyncListener)
+// _Emit dispatches the event and returns the error it was rejected with, if any.
+func (e *Eventify) _Emit(event Event, opts ...EmitOption) error {
+	if event == nil {
+		e.log.Debug("eventify emit rejected", "error", ErrNilEvent)
+		return ErrNilEvent
+	}
+	eventType, listeners, err := e._Admit(event)
+	if err != nil {
+		e._Reject(event, err)
+		return err
+	}
+	e._Dispatch(event, eventType, listeners, newEmitConfig(opts))
+	return nil
+}
+
+// _Admit checks that the event can be emitted and runs its validators.
+// It returns the normalized type of the event and the listeners to deliver it to.
+func (e *Eventify) _Admit(event Event) (string, []Listener, error) {
+	if event == nil {
+		return "", nil, ErrNilEvent
+	}
+	if e.stopped.Load() {
+		return "", nil, ErrStopped
+	}
+	eventType := e._Normalize(event.Type())
+	if err := e._CheckType(eventType); err != nil {
+		return "", nil, err
+	}
+	if e.maxPayloadSize > 0 && len(event.Payload()) > e.maxPayloadSize {
+		return "", nil, ErrPayloadTooLarge
+	}
+	if e._OverMemoryLimit() {
+		return "", nil, ErrMemoryLimitExceeded
 	}
-	e.log.Debug("eventify emited", "event", event.Type(), "listeners", listeners)
+	listeners, err := e._Validate(event, e._MatchedListeners(e._Aliases(eventType)...))
+	if err != nil {
+		return "", nil, err
+	}
+	return eventType, listeners, nil
 }
 
-func (e *Eventify) _Trigger(event Event, listener Listener, async bool) {
+// _Dispatch delivers the admitted event to its listeners and shadows.
+func (e *Eventify) _Dispatch(event Event, eventType string, listeners []Listener, config *emitConfig) {
+	listeners, shadows := e._SplitShadows(listeners)
+	recordings := e._Compare(event, listeners, shadows)
+	if e.sink != nil {
+		e.sink.Record(event, len(listeners))
+	}
+	if e.tracer != nil {
+		e.tracer.Emitted(event)
+	}
+	e._InferSchema(eventType, event)
+	if len(listeners) == 0 {
+		e._Unmatched(event)
+	}
+	done := e._TrackOutcome(event, eventType, len(listeners))
+	if config.observe != nil {
+		done = joinDone(done, config.observe(len(listeners)))
+	}
+	if r := e._Reorderer(eventType); r != nil {
+		r.Hold(event, func() {
+			e._Deliver(event, listeners, e._Guarantee(eventType), config, done)
+			e._DeliverShadows(event, shadows, recordings)
+		})
+	} else {
+		e._Deliver(event, listeners, e._Guarantee(eventType), config, done)
+		e._DeliverShadows(event, shadows, recordings)
+	}
+	withEventFields(e.log, event, nil).Debug("eventify emited", "event", event.Type(), "listeners", listeners)
+}
+
+func (e *Eventify) _Trigger(event Event, listener Listener, async bool, producer string, done func(error)) {
 	errHandler, hasErrorHandler := event.(ErrorHandler)
+	log := withEventFields(e.log, event, listener)
 	if async {
-		go func() {
-			if err := listener.Handle(event); err != nil && hasErrorHandler {
-				go errHandler.ErrorHandler(event, err)
+		size := eventSize(event)
+		e.memory.async.Add(size)
+		e._Go(producer, func() {
+			defer e.memory.async.Add(-size)
+			defer e.inflight.Release(listener)
+			err := e._Handle(event, listener)
+			if err != nil {
+				log.Debug("eventify listener failed", "event", event.Type(), "status", StatusFailed, "error", err)
+				if hasErrorHandler {
+					e.scheduler.Go(func() { errHandler.ErrorHandler(event, err) })
+				}
 			}
-		}()
+			e.ReportDelivery(event, listener, StatusOf(err), err)
+			if done != nil {
+				done(err)
+			}
+		})
 		return
 	}
-	if err := listener.Handle(event); err != nil && hasErrorHandler {
-		errHandler.ErrorHandler(event, err)
+	defer e.inflight.Release(listener)
+	err := e._Handle(event, listener)
+	if err != nil {
+		log.Debug("eventify listener failed", "event", event.Type(), "status", StatusFailed, "error", err)
+		if hasErrorHandler {
+			errHandler.ErrorHandler(event, err)
+		}
+	}
+	e.ReportDelivery(event, listener, StatusOf(err), err)
+	if done != nil {
+		done(err)
+	}
+}
+
+// _Handle initializes and invokes the listener and records the invocation.
+func (e *Eventify) _Handle(event Event, listener Listener) error {
+	return e._HandleRecorded(event, listener, e._Recording(event, listener))
+}
+
+// _HandleRecorded is _Handle recording what the invocation did, see WithShadowCompare, unless the recording is nil.
+func (e *Eventify) _HandleRecorded(event Event, listener Listener, recording *recording) (err error) {
+	ctx := e.ResourceContext(context.Background())
+	if recording != nil {
+		ctx = context.WithValue(ctx, recordingKey{}, recording)
+		defer func() { recording.Done(err) }()
+	}
+	if err := e._Init(ctx, listener); err != nil {
+		return err
+	}
+	var fingerprint uint64
+	if e.payloadGuard {
+		fingerprint = payloadFingerprint(event.Payload())
+	}
+	start := time.Now()
+	if e.profilerLabels {
+		labels := pprof.Labels("eventify_event", event.Type(), "eventify_listener", listenerLabel(listener))
+		pprof.Do(ctx, labels, func(ctx context.Context) {
+			err = e._Guarded(ctx, event, listener)
+		})
+	} else {
+		err = e._Guarded(ctx, event, listener)
+	}
+	if e.payloadGuard && err == nil && payloadFingerprint(event.Payload()) != fingerprint {
+		err = fmt.Errorf("%w: by listener %s", ErrPayloadMutated, listenerLabel(listener))
+	}
+	e._Record(listener, start, time.Since(start), err)
+	if e.tracer != nil {
+		e.tracer.Handled(event, listener, start, err)
 	}
+	return err
 }
 
-func (e *Eventify) _MatchedListeners(eventType string) []Listener {
+// _Invoke calls the listener, with the context if it is a ContextListener.
+func (e *Eventify) _Invoke(ctx context.Context, event Event, listener Listener) (err error) {
+	if e.recoverPanics {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("%w: %v", ErrListenerPanic, p)
+				withEventFields(e.log, event, listener).Debug("eventify listener panicked", "event", event.Type(), "panic", p, "stack", string(debug.Stack()))
+			}
+		}()
+	}
+	return handleCtx(ctx, listener, event)
+}
+
+// _Matcher returns the matcher of the pattern, compiling it on first use.
+func (e *Eventify) _Matcher(pattern string) *Matcher {
+	if m, ok := e.matchers.Load(pattern); ok {
+		return m.(*Matcher)
+	}
+	m, _ := e.matchers.LoadOrStore(pattern, NewMatcher(pattern))
+	return m.(*Matcher)
+}
+
+// _MatchedListeners returns the listeners whose pattern matches any of the event types,
+// except paused ones, and acquires a delivery for each of them.
+// Every acquired delivery must be released once the listener is done with the event.
+func (e *Eventify) _MatchedListeners(eventTypes ...string) []Listener {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if e.sealed.Load() {
+		key := matchesKey(eventTypes)
+		listeners, ok := e.matches.Load(key)
+		if !ok {
+			listeners = e.matches.LoadOrStore(key, e._Match(eventTypes))
+		}
+		for _, listener := range listeners {
+			e.inflight.Acquire(listener)
+		}
+		return listeners
+	}
+	listeners := e._Match(eventTypes)
+	for _, listener := range listeners {
+		e.inflight.Acquire(listener)
+	}
+	return listeners
+}
+
+// _Match returns the listeners whose pattern matches any of the event types, except paused ones.
+// The caller must hold the lock.
+func (e *Eventify) _Match(eventTypes []string) []Listener {
 	listeners := make([]Listener, 0)
 	e.listeners.Range(func(key, value any) bool {
-		if NewMatcher(key.(string)).Match(eventType) {
-			listeners = append(listeners, value.([]Listener)...)
+		matcher := e._Matcher(key.(string))
+		for _, eventType := range eventTypes {
+			if matcher.Match(eventType) {
+				for _, listener := range value.([]Listener) {
+					if !e._Paused(listener) {
+						listeners = append(listeners, listener)
+					}
+				}
+				break
+			}
 		}
 		return true
 	})
 	return listeners
 }
 
-func (e *Eventify) _AnyToBytes(payload any) []byte {
+func (e *Eventify) _AnyToBytes(payload any) ([]byte, error) {
 	if payload == nil {
-		return nil
+		return nil, nil
 	}
 	switch p := payload.(type) {
 	case string:
-		return []byte(p)
+		return []byte(p), nil
 	case []byte:
-		return p
+		return p, nil
 	default:
 		bz, err := json.Marshal(payload)
 		if err != nil {
-			return nil
+			return nil, fmt.Errorf("%w: %w", ErrMarshalPayload, err)
 		}
-		return bz
+		return bz, nil
 	}
 }
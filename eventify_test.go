@@ -236,3 +236,78 @@ func (m *mockErrorEvent) Payload() []byte { return nil }
 func (m *mockErrorEvent) ErrorHandler(_ Event, err error) {
 	m.errChan <- err
 }
+
+type recordLog struct {
+	mu      sync.Mutex
+	entries []recordEntry
+}
+
+type recordEntry struct {
+	msg string
+	kvs []any
+}
+
+func (l *recordLog) Debug(msg string, kvs ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, recordEntry{msg: msg, kvs: kvs})
+}
+
+func (l *recordLog) find(msg string) (recordEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		if entry.msg == msg {
+			return entry, true
+		}
+	}
+	return recordEntry{}, false
+}
+
+type tracedEvent struct {
+	Event
+	id            string
+	correlationID string
+}
+
+func (t *tracedEvent) ID() string            { return t.id }
+func (t *tracedEvent) CorrelationID() string { return t.correlationID }
+
+func TestEventify_LogFields(t *testing.T) {
+	log := &recordLog{}
+	e := NewEventifyWithLog(log)
+	e.Register("traced.event", NewNamedListener("failing", func(event Event) error {
+		return assert.AnError
+	}))
+
+	e.Emit(&tracedEvent{Event: NewEvent("traced.event", nil), id: "evt-1", correlationID: "corr-1"})
+
+	emitted, ok := log.find("eventify emited")
+	require.True(t, ok)
+	assert.Subset(t, emitted.kvs, []any{"event_id", "evt-1", "correlation_id", "corr-1"})
+
+	failed, ok := log.find("eventify listener failed")
+	require.True(t, ok)
+	assert.Subset(t, failed.kvs, []any{"event_id", "evt-1", "correlation_id", "corr-1", "listener", "failing"})
+}
+
+func TestEventify_Replace(t *testing.T) {
+	e := New()
+	var got []string
+	e.Register("order.created", NewNamedListener("handler", func(event Event) error {
+		got = append(got, "v1")
+		return nil
+	}))
+
+	e.EmitBy("order.created", nil)
+	replaced := e.Replace("order.created", "handler", NewNamedListener("handler", func(event Event) error {
+		got = append(got, "v2")
+		return nil
+	}))
+	e.EmitBy("order.created", nil)
+
+	assert.True(t, replaced)
+	assert.Equal(t, []string{"v1", "v2"}, got)
+	assert.False(t, e.Replace("order.created", "missing", NewListener(nil)))
+	assert.False(t, e.Replace("order.deleted", "handler", NewListener(nil)))
+}
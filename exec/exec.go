@@ -0,0 +1,138 @@
+// Package exec runs commands for eventify events.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Config configures an exec listener.
+type Config struct {
+	// Command is the program to execute and Args its arguments.
+	Command string
+	Args    []string
+	// Dir is the working directory of the command.
+	Dir string
+	// Credential runs the command as another user, which requires the privilege to switch to it.
+	// It is only supported on Unix.
+	Credential *Credential
+	// Env is the environment of the command, in addition to the event variables.
+	// The environment of the current process is not inherited unless InheritEnv is set.
+	Env        []string
+	InheritEnv bool
+	// Timeout kills the command, and the processes it started, if it runs longer; zero means no timeout.
+	Timeout time.Duration
+	// MaxConcurrency caps the number of commands running at once; zero means no cap.
+	// Events beyond the cap wait for a running command to finish.
+	MaxConcurrency int
+	// MaxOutput caps the number of bytes captured from stdout and stderr each; zero means 64KiB.
+	MaxOutput int
+	// Output is called with the captured output of every command that exits successfully.
+	Output func(event eventify.Event, stdout, stderr []byte)
+}
+
+// Credential is the user and groups a command runs as.
+type Credential struct {
+	UID    uint32
+	GID    uint32
+	Groups []uint32
+}
+
+const defaultMaxOutput = 64 * 1024
+
+// waitDelay is how long a killed command's output is still read, in case processes outside
+// its process group hold its pipes open.
+const waitDelay = time.Second
+
+// NewListener creates a listener that executes a command for every event.
+// The payload is written to the command's stdin and the event is described by the
+// EVENTIFY_EVENT_TYPE, EVENTIFY_EVENT_ID and EVENTIFY_CORRELATION_ID environment variables.
+// A command exiting with a non-zero status is reported as an error including its stderr.
+// On Unix, the command runs in its own process group, killed as a whole on timeout.
+// Resource limits are not set by the listener: run the command through a wrapper such as prlimit(1),
+// or a cgroup, to bound its memory or CPU time.
+func NewListener(cfg Config) eventify.Listener {
+	if cfg.MaxOutput <= 0 {
+		cfg.MaxOutput = defaultMaxOutput
+	}
+	var slots chan struct{}
+	if cfg.MaxConcurrency > 0 {
+		slots = make(chan struct{}, cfg.MaxConcurrency)
+	}
+	return eventify.NewListener(func(event eventify.Event) error {
+		if slots != nil {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+		}
+		ctx := context.Background()
+		if cfg.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+			defer cancel()
+		}
+		cmd := osexec.CommandContext(ctx, cfg.Command, cfg.Args...)
+		cmd.Dir = cfg.Dir
+		cmd.WaitDelay = waitDelay
+		if err := sandbox(cmd, cfg); err != nil {
+			return fmt.Errorf("eventify exec %s: %w", cfg.Command, err)
+		}
+		cmd.Env = environ(cfg, event)
+		cmd.Stdin = bytes.NewReader(event.Payload())
+		stdout := &limitedBuffer{limit: cfg.MaxOutput}
+		stderr := &limitedBuffer{limit: cfg.MaxOutput}
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return fmt.Errorf("eventify exec %s: %w: %s", cfg.Command, err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		if cfg.Output != nil {
+			cfg.Output(event, stdout.Bytes(), stderr.Bytes())
+		}
+		return nil
+	})
+}
+
+func environ(cfg Config, event eventify.Event) []string {
+	env := []string{}
+	if cfg.InheritEnv {
+		env = append(env, os.Environ()...)
+	}
+	env = append(env, cfg.Env...)
+	env = append(env, "EVENTIFY_EVENT_TYPE="+event.Type())
+	if identifiable, ok := event.(eventify.Identifiable); ok {
+		env = append(env, "EVENTIFY_EVENT_ID="+identifiable.ID())
+	}
+	if correlatable, ok := event.(eventify.Correlatable); ok {
+		env = append(env, "EVENTIFY_CORRELATION_ID="+correlatable.CorrelationID())
+	}
+	return env
+}
+
+// limitedBuffer is a buffer that silently discards writes beyond its limit.
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
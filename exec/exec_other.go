@@ -0,0 +1,17 @@
+//go:build !unix
+
+package exec
+
+import (
+	"errors"
+	osexec "os/exec"
+)
+
+// sandbox only kills the command itself when it is cancelled: process groups and credentials
+// are not supported on this platform.
+func sandbox(_ *osexec.Cmd, cfg Config) error {
+	if cfg.Credential != nil {
+		return errors.New("credentials are not supported on this platform")
+	}
+	return nil
+}
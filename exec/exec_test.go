@@ -0,0 +1,105 @@
+package exec
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecListener(t *testing.T) {
+	t.Run("passes payload and type", func(t *testing.T) {
+		var stdout string
+		listener := NewListener(Config{
+			Command: "sh",
+			Args:    []string{"-c", `printf '%s:' "$EVENTIFY_EVENT_TYPE"; cat`},
+			Output: func(_ eventify.Event, out, _ []byte) {
+				stdout = string(out)
+			},
+		})
+
+		require.NoError(t, listener.Handle(eventify.NewEvent("job.run", []byte("payload"))))
+
+		assert.Equal(t, "job.run:payload", stdout)
+	})
+
+	t.Run("reports failures with stderr", func(t *testing.T) {
+		listener := NewListener(Config{
+			Command: "sh",
+			Args:    []string{"-c", "echo broken >&2; exit 3"},
+		})
+
+		err := listener.Handle(eventify.NewEvent("job.run", nil))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broken")
+	})
+
+	t.Run("kills commands on timeout", func(t *testing.T) {
+		listener := NewListener(Config{
+			Command: "sleep",
+			Args:    []string{"5"},
+			Timeout: 50 * time.Millisecond,
+		})
+
+		start := time.Now()
+		err := listener.Handle(eventify.NewEvent("job.run", nil))
+
+		require.Error(t, err)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("kills the process group on timeout", func(t *testing.T) {
+		listener := NewListener(Config{
+			Command: "sh",
+			Args:    []string{"-c", "sleep 5 | cat"},
+			Timeout: 50 * time.Millisecond,
+		})
+
+		start := time.Now()
+		err := listener.Handle(eventify.NewEvent("job.run", nil))
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), waitDelay)
+	})
+
+	t.Run("runs as the credential", func(t *testing.T) {
+		if os.Getuid() != 0 {
+			t.Skip("requires root")
+		}
+		var stdout string
+		listener := NewListener(Config{
+			Command:    "id",
+			Args:       []string{"-u"},
+			Dir:        os.TempDir(),
+			Credential: &Credential{UID: 65534, GID: 65534},
+			Output: func(_ eventify.Event, out, _ []byte) {
+				stdout = string(out)
+			},
+		})
+
+		require.NoError(t, listener.Handle(eventify.NewEvent("job.run", nil)))
+
+		assert.Equal(t, "65534\n", stdout)
+	})
+
+	t.Run("caps captured output", func(t *testing.T) {
+		var stdout []byte
+		listener := NewListener(Config{
+			Command:   "sh",
+			Args:      []string{"-c", "echo 0123456789"},
+			MaxOutput: 4,
+			Output: func(_ eventify.Event, out, _ []byte) {
+				stdout = out
+			},
+		})
+
+		require.NoError(t, listener.Handle(eventify.NewEvent("job.run", nil)))
+
+		assert.Equal(t, "0123", string(stdout))
+	})
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package exec
+
+import (
+	osexec "os/exec"
+	"syscall"
+)
+
+// sandbox starts the command in its own process group, as the user of the credential if any,
+// and kills the whole group when the command is cancelled.
+func sandbox(cmd *osexec.Cmd, cfg Config) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if cfg.Credential != nil {
+		cmd.SysProcAttr.Credential = &syscall.Credential{
+			Uid:    cfg.Credential.UID,
+			Gid:    cfg.Credential.GID,
+			Groups: cfg.Credential.Groups,
+		}
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return nil
+}
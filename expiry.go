@@ -0,0 +1,85 @@
+package eventify
+
+import (
+	"context"
+	"time"
+)
+
+// WithExpiry removes the listener automatically once the duration has passed since it was registered,
+// as timed by the scheduler of the bus. Only comparable listeners can expire.
+func WithExpiry(d time.Duration) RegisterOption {
+	return func(r *registration) {
+		r.expiry = d
+	}
+}
+
+// WithOwner removes the listener automatically once the context is done, tying it to the lifetime
+// of the component owning it. Only comparable listeners can be owned.
+func WithOwner(ctx context.Context) RegisterOption {
+	return func(r *registration) {
+		r.owner = ctx
+	}
+}
+
+// _Expire schedules the removal of the listener from the pattern according to the registration.
+func (e *Eventify) _Expire(eventTypePattern string, listener Listener, r *registration) {
+	if statsKey(listener) == nil {
+		return
+	}
+	if r.expiry > 0 {
+		e.scheduler.AfterFunc(r.expiry, func() {
+			e._Remove(eventTypePattern, listener, "expired")
+		})
+	}
+	if r.owner != nil {
+		context.AfterFunc(r.owner, func() {
+			e._Remove(eventTypePattern, listener, "owner done")
+		})
+	}
+}
+
+// _Remove removes one registration of the listener from the pattern, if it is still registered.
+// It is allowed while the registry is sealed, since the registration declared its lifetime.
+func (e *Eventify) _Remove(eventTypePattern string, listener Listener, reason string) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e._RemoveLocked(eventTypePattern, listener, reason)
+}
+
+// _RemoveLocked is _Remove for callers holding the write lock.
+func (e *Eventify) _RemoveLocked(eventTypePattern string, listener Listener, reason string) bool {
+	ls, ok := e.listeners.Load(eventTypePattern)
+	if !ok {
+		return false
+	}
+	listeners := ls.([]Listener)
+	for i, l := range listeners {
+		if statsKey(l) == nil || l != listener {
+			continue
+		}
+		e.listeners.Store(eventTypePattern, append(listeners[:i:i], listeners[i+1:]...))
+		delete(e.growth, eventTypePattern)
+		e.matches.Clear()
+		if !e._Registered(listener) {
+			e._Forget([]Listener{listener})
+		}
+		e.log.Debug("eventify remove", "event_type_pattern", eventTypePattern, "listener", listener, "reason", reason)
+		return true
+	}
+	return false
+}
+
+// _Registered reports whether the listener is registered for any pattern. The caller must hold the lock.
+func (e *Eventify) _Registered(listener Listener) bool {
+	registered := false
+	e.listeners.Range(func(_, value any) bool {
+		for _, l := range value.([]Listener) {
+			if statsKey(l) != nil && l == listener {
+				registered = true
+				return false
+			}
+		}
+		return true
+	})
+	return registered
+}
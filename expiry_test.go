@@ -0,0 +1,59 @@
+package eventify
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_WithExpiry(t *testing.T) {
+	e := New()
+	var calls atomic.Int32
+	listener := NewListener(func(Event) error {
+		calls.Add(1)
+		return nil
+	})
+	e.Register("tick", listener, WithExpiry(10*time.Millisecond))
+	e.Register("tick", NewListener(nil))
+
+	e.EmitBy("tick", nil)
+	require.Eventually(t, func() bool { return len(loadAllListeners(e)["tick"]) == 1 }, time.Second, time.Millisecond)
+	e.EmitBy("tick", nil)
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestEventify_WithExpirySimulated(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	listener := NewListener(nil)
+	e.Register("tick", listener, WithExpiry(time.Hour))
+	assert.Len(t, loadAllListeners(e)["tick"], 1)
+
+	sim.Run()
+
+	assert.Empty(t, loadAllListeners(e)["tick"], "the listener should expire on the virtual clock")
+	assert.Equal(t, time.Unix(0, 0).Add(time.Hour), sim.Now())
+}
+
+func TestEventify_WithOwner(t *testing.T) {
+	e := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	e.Register("tick", NewListener(func(Event) error {
+		calls.Add(1)
+		return nil
+	}), WithOwner(ctx))
+	e.Seal()
+
+	e.EmitBy("tick", nil)
+	cancel()
+	require.Eventually(t, func() bool { return len(loadAllListeners(e)["tick"]) == 0 }, time.Second, time.Millisecond)
+	e.EmitBy("tick", nil)
+
+	assert.Equal(t, int32(1), calls.Load(), "the owner should remove the listener even while sealed")
+}
@@ -0,0 +1,76 @@
+package eventify
+
+import "sync"
+
+// fairQueue runs the asynchronous deliveries with a fixed number of workers, taking them round-robin
+// from one queue per producer, so a chatty producer only delays its own deliveries. See WithFairDispatch.
+type fairQueue struct {
+	scheduler Scheduler
+	mutex     sync.Mutex
+	queues    map[string][]func()
+	// order holds the producers with queued deliveries, in the order they are served.
+	order []string
+	// idle is the number of workers not running.
+	idle int
+}
+
+func newFairQueue(scheduler Scheduler, workers int) *fairQueue {
+	return &fairQueue{scheduler: scheduler, queues: map[string][]func(){}, idle: max(workers, 1)}
+}
+
+// Go queues the task of the producer, starting a worker if one is idle.
+func (q *fairQueue) Go(producer string, task func()) {
+	q.mutex.Lock()
+	if len(q.queues[producer]) == 0 {
+		q.order = append(q.order, producer)
+	}
+	q.queues[producer] = append(q.queues[producer], task)
+	start := q.idle > 0
+	if start {
+		q.idle--
+	}
+	q.mutex.Unlock()
+	if start {
+		q.scheduler.Go(q.work)
+	}
+}
+
+// Len returns the number of queued tasks of the producer.
+func (q *fairQueue) Len(producer string) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.queues[producer])
+}
+
+// work runs the queued tasks, one producer after the other, until there are none left.
+func (q *fairQueue) work() {
+	for {
+		q.mutex.Lock()
+		if len(q.order) == 0 {
+			q.idle++
+			q.mutex.Unlock()
+			return
+		}
+		producer := q.order[0]
+		q.order = q.order[1:]
+		tasks := q.queues[producer]
+		task := tasks[0]
+		if len(tasks) == 1 {
+			delete(q.queues, producer)
+		} else {
+			q.queues[producer] = tasks[1:]
+			q.order = append(q.order, producer)
+		}
+		q.mutex.Unlock()
+		task()
+	}
+}
+
+// _Go runs the asynchronous delivery task of the producer, through the fair queue if there is one.
+func (e *Eventify) _Go(producer string, task func()) {
+	if e.fair != nil {
+		e.fair.Go(producer, task)
+		return
+	}
+	e.scheduler.Go(task)
+}
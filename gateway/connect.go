@@ -0,0 +1,236 @@
+package gateway
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// ConnectProcedure is the path of the server-streaming procedure served by Connect.
+const ConnectProcedure = "/eventify.gateway.v1.GatewayService/Subscribe"
+
+// Content types served by Connect.
+const (
+	ConnectContentType = "application/connect+json"
+	GRPCWebContentType = "application/grpc-web+json"
+)
+
+// Flags of the envelope preceding every message of a stream.
+const (
+	flagCompressed     = 0x01
+	flagConnectEnd     = 0x02
+	flagGRPCWebTrailer = 0x80
+)
+
+// ConnectRequest is the request of ConnectProcedure.
+type ConnectRequest struct {
+	// Patterns are the patterns subscribed to, with the Eventify pattern syntax.
+	Patterns []string `json:"patterns"`
+}
+
+// connectError is an error of a stream, with its Connect and gRPC codes.
+type connectError struct {
+	code    string
+	status  int
+	message string
+}
+
+func (e *connectError) Error() string {
+	return e.code + ": " + e.message
+}
+
+func newConnectError(code string, status int, format string, args ...any) *connectError {
+	return &connectError{code: code, status: status, message: fmt.Sprintf(format, args...)}
+}
+
+func invalidArgument(format string, args ...any) *connectError {
+	return newConnectError("invalid_argument", 3, format, args...)
+}
+
+// Connect is an http.Handler serving bus events as a server stream of the Connect and gRPC-Web protocols,
+// with the JSON codec, for the clients that can't use WebSockets: browsers behind proxies that only pass
+// HTTP/1.1 or HTTP/2 requests, or generated Connect and gRPC-Web clients. Mount it at ConnectProcedure.
+//
+// The request is a ConnectRequest; every event matching one of its patterns is sent as a Message with the
+// event op, as on the wire protocol. The stream lasts until the client cancels it or the bus is shut down.
+// Compression and the binary and text encodings of gRPC-Web are not supported.
+type Connect struct {
+	gateway *Gateway
+}
+
+// NewConnect creates a Connect handler for the bus. The options of the Gateway apply: WithAuthorize is asked
+// for OpSubscribe with every pattern of the request, and WithMaxMessageSize limits the request size.
+func NewConnect(bus *eventify.Eventify, opts ...OptionFunc) *Connect {
+	return &Connect{gateway: New(bus, opts...)}
+}
+
+// ServeHTTP serves the stream until the client cancels it.
+func (c *Connect) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !c.gateway.checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType != ConnectContentType && contentType != GRPCWebContentType {
+		http.Error(w, "unsupported content type "+contentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	s := &connectStream{
+		connect: c,
+		id:      c.gateway.nextID.Add(1),
+		w:       w,
+		rc:      http.NewResponseController(w),
+		grpcWeb: contentType == GRPCWebContentType,
+	}
+	w.Header().Set("Content-Type", contentType)
+	session, err := c.subscribe(r)
+	if err != nil {
+		c.gateway.log.Debug("gateway connect subscribe failed", "error", err, "conn", s.id)
+		s.end(err)
+		return
+	}
+	defer session.Close()
+	w.WriteHeader(http.StatusOK)
+	if err := s.rc.Flush(); err != nil {
+		return
+	}
+	for {
+		select {
+		case e := <-session.Events():
+			msg := Message{Op: OpEvent, Pattern: e.Pattern, Type: e.Event.Type(), Payload: encodePayload(e.Event.Payload())}
+			if err := s.send(msg); err != nil {
+				c.gateway.log.Debug("gateway connect send failed", "error", err, "conn", s.id)
+				return
+			}
+		case <-session.Done():
+			s.end(nil)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// subscribe reads the request and returns a session subscribed to its patterns.
+func (c *Connect) subscribe(r *http.Request) (*eventify.Session, error) {
+	flags, data, err := readEnvelope(r.Body, c.gateway.maxMessageSize)
+	switch {
+	case errors.Is(err, errMessageTooBig):
+		return nil, newConnectError("resource_exhausted", 8, "request larger than %d bytes", c.gateway.maxMessageSize)
+	case err != nil:
+		return nil, invalidArgument("invalid request envelope: %v", err)
+	case flags&flagCompressed != 0:
+		return nil, newConnectError("unimplemented", 12, "compressed requests are not supported")
+	}
+	var request ConnectRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, invalidArgument("invalid request: %v", err)
+	}
+	if len(request.Patterns) == 0 {
+		return nil, invalidArgument("patterns are required")
+	}
+	for _, pattern := range request.Patterns {
+		if !c.gateway.authorize(r, OpSubscribe, pattern) {
+			return nil, newConnectError("permission_denied", 7, "not allowed to subscribe to %q", pattern)
+		}
+	}
+	session := c.gateway.bus.NewSession(c.gateway.sessionOpts...)
+	for _, pattern := range request.Patterns {
+		if err := session.Subscribe(pattern); err != nil {
+			session.Close()
+			return nil, invalidArgument("%v", err)
+		}
+	}
+	return session, nil
+}
+
+type connectStream struct {
+	connect *Connect
+	id      uint64
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	grpcWeb bool
+}
+
+// send writes the message in an envelope and flushes it.
+func (s *connectStream) send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := writeEnvelope(s.w, 0, data); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// end writes the end of the stream with its error, if any: the end-stream message of Connect
+// or the trailers of gRPC-Web.
+func (s *connectStream) end(err error) {
+	var ce *connectError
+	if err != nil && !errors.As(err, &ce) {
+		ce = newConnectError("internal", 13, "%v", err)
+	}
+	var flags byte
+	var data []byte
+	if s.grpcWeb {
+		flags = flagGRPCWebTrailer
+		if ce == nil {
+			data = []byte("grpc-status: 0\r\n")
+		} else {
+			data = fmt.Appendf(nil, "grpc-status: %d\r\ngrpc-message: %s\r\n", ce.status, url.PathEscape(ce.message))
+		}
+	} else {
+		flags = flagConnectEnd
+		end := map[string]any{}
+		if ce != nil {
+			end["error"] = map[string]string{"code": ce.code, "message": ce.message}
+		}
+		data, _ = json.Marshal(end)
+	}
+	if err := writeEnvelope(s.w, flags, data); err != nil {
+		s.connect.gateway.log.Debug("gateway connect end failed", "error", err, "conn", s.id)
+		return
+	}
+	s.rc.Flush()
+}
+
+// readEnvelope reads a message preceded by its envelope: a flags byte and its big-endian 32-bit length.
+func readEnvelope(r io.Reader, maxSize int64) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if int64(size) > maxSize {
+		return 0, nil, errMessageTooBig
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	return header[0], data, nil
+}
+
+// writeEnvelope writes the message preceded by its envelope.
+func writeEnvelope(w io.Writer, flags byte, data []byte) error {
+	var header [5]byte
+	header[0] = flags
+	binary.BigEndian.PutUint32(header[1:], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
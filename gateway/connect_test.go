@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postStream(t *testing.T, ctx context.Context, url, contentType string, request any) *http.Response {
+	t.Helper()
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+	var body bytes.Buffer
+	require.NoError(t, writeEnvelope(&body, 0, data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+ConnectProcedure, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestConnect(t *testing.T) {
+	bus := eventify.New()
+	server := httptest.NewServer(NewConnect(bus))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp := postStream(t, ctx, server.URL, ConnectContentType, ConnectRequest{Patterns: []string{"order.*"}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ConnectContentType, resp.Header.Get("Content-Type"))
+
+	bus.EmitBy("user.created", "ignored")
+	bus.EmitBy("order.created", map[string]any{"id": 42})
+	bus.Emit(eventify.NewEvent("order.note", []byte("plain text")))
+
+	for _, want := range []Message{
+		{Op: OpEvent, Pattern: "order.*", Type: "order.created", Payload: json.RawMessage(`{"id":42}`)},
+		{Op: OpEvent, Pattern: "order.*", Type: "order.note", Payload: json.RawMessage(`"plain text"`)},
+	} {
+		flags, data, err := readEnvelope(resp.Body, defaultMaxMessageSize)
+		require.NoError(t, err)
+		assert.Zero(t, flags)
+		var msg Message
+		require.NoError(t, json.Unmarshal(data, &msg))
+		assert.Equal(t, want, msg)
+	}
+}
+
+func TestConnect_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		request     any
+		wantFlags   byte
+		wantEnd     string
+	}{
+		{
+			name:        "connect denied",
+			contentType: ConnectContentType,
+			request:     ConnectRequest{Patterns: []string{"order.*", "admin.*"}},
+			wantFlags:   flagConnectEnd,
+			wantEnd:     `{"error":{"code":"permission_denied","message":"not allowed to subscribe to \"admin.*\""}}`,
+		},
+		{
+			name:        "connect without patterns",
+			contentType: ConnectContentType,
+			request:     ConnectRequest{},
+			wantFlags:   flagConnectEnd,
+			wantEnd:     `{"error":{"code":"invalid_argument","message":"patterns are required"}}`,
+		},
+		{
+			name:        "grpc-web denied",
+			contentType: GRPCWebContentType,
+			request:     ConnectRequest{Patterns: []string{"admin.*"}},
+			wantFlags:   flagGRPCWebTrailer,
+			wantEnd:     "grpc-status: 7\r\ngrpc-message: not%20allowed%20to%20subscribe%20to%20%22admin.%2A%22\r\n",
+		},
+		{
+			name:        "grpc-web invalid request",
+			contentType: GRPCWebContentType,
+			request:     []string{"order.*"},
+			wantFlags:   flagGRPCWebTrailer,
+			wantEnd:     "grpc-status: 3\r\ngrpc-message: invalid%20request:%20json:%20cannot%20unmarshal%20array%20into%20Go%20value%20of%20type%20gateway.ConnectRequest\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(NewConnect(eventify.New(),
+				WithAuthorize(func(_ *http.Request, _, pattern string) bool { return pattern != "admin.*" })))
+			defer server.Close()
+
+			resp := postStream(t, context.Background(), server.URL, tt.contentType, tt.request)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			flags, data, err := readEnvelope(resp.Body, defaultMaxMessageSize)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFlags, flags)
+			assert.Equal(t, tt.wantEnd, string(data))
+			_, _, err = readEnvelope(resp.Body, defaultMaxMessageSize)
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestConnect_RejectsRequests(t *testing.T) {
+	server := httptest.NewServer(NewConnect(eventify.New(), WithMaxMessageSize(8)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + ConnectProcedure)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+ConnectProcedure, "application/grpc-web+proto", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp = postStream(t, context.Background(), server.URL, ConnectContentType, ConnectRequest{Patterns: []string{"order.*"}})
+	_, data, err := readEnvelope(resp.Body, defaultMaxMessageSize)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"error":{"code":"resource_exhausted","message":"request larger than 8 bytes"}}`, string(data))
+}
@@ -0,0 +1,252 @@
+// Package gateway exposes an Eventify instance to browsers and other non-Go clients
+// over WebSocket, using the JSON wire protocol described in protocol.md.
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// sendBufferSize is the number of control frames queued per connection before they are dropped.
+// Events are buffered by the connection's session.
+const sendBufferSize = 1024
+
+// defaultMaxMessageSize is the default maximum size of a message received from a client.
+const defaultMaxMessageSize = 1024 * 1024
+
+// controlReplyTimeout is how long an ack or error frame waits for room in a full send buffer
+// before the connection of the client not reading its replies is closed.
+const controlReplyTimeout = 5 * time.Second
+
+// Gateway is an http.Handler serving the wire protocol over WebSocket.
+type Gateway struct {
+	bus            *eventify.Eventify
+	log            eventify.Log
+	maxMessageSize int64
+	checkOrigin    func(r *http.Request) bool
+	authorize      func(r *http.Request, op, target string) bool
+	sessionOpts    []eventify.SessionOption
+	nextID         atomic.Uint64
+}
+
+// OptionFunc is a function that configures a Gateway.
+type OptionFunc func(*Gateway)
+
+// WithLogger sets the logger for the Gateway.
+func WithLogger(log eventify.Log) OptionFunc {
+	return func(g *Gateway) {
+		g.log = log
+	}
+}
+
+// WithMaxMessageSize sets the maximum size of a message received from a client, 1MiB by default.
+// A size of 0 or less also means 1MiB: messages are never unbounded.
+func WithMaxMessageSize(size int64) OptionFunc {
+	return func(g *Gateway) {
+		g.maxMessageSize = size
+	}
+}
+
+// WithCheckOrigin sets the function deciding whether a handshake request is accepted.
+// By default, requests with an Origin header are only accepted from the same host, so other sites
+// can't connect with the cookies of the user; requests without one, from non-browser clients, are accepted.
+func WithCheckOrigin(check func(r *http.Request) bool) OptionFunc {
+	return func(g *Gateway) {
+		g.checkOrigin = check
+	}
+}
+
+// WithAuthorize sets the function deciding whether a client may subscribe to a pattern
+// or emit an event type. The op is OpSubscribe or OpEmit. By default subscriptions are allowed
+// and emits are not: a Gateway accepting emits must set it.
+func WithAuthorize(authorize func(r *http.Request, op, target string) bool) OptionFunc {
+	return func(g *Gateway) {
+		g.authorize = authorize
+	}
+}
+
+// WithSessionOptions configures the session of every connection, e.g. its event buffer
+// with eventify.WithSessionBuffer or its emit rate with eventify.WithSessionRate.
+func WithSessionOptions(opts ...eventify.SessionOption) OptionFunc {
+	return func(g *Gateway) {
+		g.sessionOpts = append(g.sessionOpts, opts...)
+	}
+}
+
+// New creates a new Gateway for the bus.
+func New(bus *eventify.Eventify, opts ...OptionFunc) *Gateway {
+	g := &Gateway{
+		bus:            bus,
+		log:            &eventify.NoLog{},
+		maxMessageSize: defaultMaxMessageSize,
+		checkOrigin:    sameOrigin,
+		authorize:      func(_ *http.Request, op, _ string) bool { return op != OpEmit },
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// sameOrigin accepts requests without an Origin header or whose Origin has the host of the request.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// ServeHTTP upgrades the request to a WebSocket and serves the wire protocol until the client disconnects.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !g.checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	ws, err := upgrade(w, r, g.maxMessageSize)
+	if err != nil {
+		g.log.Debug("gateway handshake failed", "error", err)
+		return
+	}
+	c := &conn{
+		gateway: g,
+		id:      g.nextID.Add(1),
+		request: r,
+		ws:      ws,
+		send:    make(chan Message, sendBufferSize),
+		session: g.bus.NewSession(g.sessionOpts...),
+	}
+	if !c.write(Message{Op: OpWelcome, Version: ProtocolVersion}) {
+		return
+	}
+	go c.writeLoop()
+	c.readLoop()
+}
+
+type conn struct {
+	gateway *Gateway
+	id      uint64
+	request *http.Request
+	ws      *wsConn
+	send    chan Message
+	session *eventify.Session
+	once    sync.Once
+}
+
+func (c *conn) readLoop() {
+	defer c.close()
+	for {
+		data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.enqueue(Message{Op: OpError, Error: "invalid frame: " + err.Error()})
+			continue
+		}
+		if err := c.handle(msg); err != nil {
+			c.enqueue(Message{Op: OpError, ID: msg.ID, Error: err.Error()})
+			continue
+		}
+		c.enqueue(Message{Op: OpAck, ID: msg.ID})
+	}
+}
+
+func (c *conn) handle(msg Message) error {
+	switch msg.Op {
+	case OpSubscribe:
+		if msg.Pattern == "" {
+			return fmt.Errorf("pattern is required")
+		}
+		if !c.gateway.authorize(c.request, OpSubscribe, msg.Pattern) {
+			return fmt.Errorf("not allowed to subscribe to %q", msg.Pattern)
+		}
+		return c.session.Subscribe(msg.Pattern)
+	case OpUnsubscribe:
+		if msg.Pattern == "" {
+			return fmt.Errorf("pattern is required")
+		}
+		return c.session.Unsubscribe(msg.Pattern)
+	case OpEmit:
+		if msg.Type == "" {
+			return fmt.Errorf("type is required")
+		}
+		if !c.gateway.authorize(c.request, OpEmit, msg.Type) {
+			return fmt.Errorf("not allowed to emit %q", msg.Type)
+		}
+		payload, err := decodePayload(msg.Payload)
+		if err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		return c.session.Emit(eventify.NewEvent(msg.Type, payload))
+	default:
+		return fmt.Errorf("unknown op %q", msg.Op)
+	}
+}
+
+// enqueue queues the reply for the writer. Replies are never dropped: when the buffer stays full
+// for controlReplyTimeout, the client is not reading and the connection is closed.
+func (c *conn) enqueue(msg Message) {
+	select {
+	case c.send <- msg:
+		return
+	default:
+	}
+	timer := time.NewTimer(controlReplyTimeout)
+	defer timer.Stop()
+	select {
+	case c.send <- msg:
+	case <-c.session.Done():
+	case <-timer.C:
+		c.gateway.log.Debug("gateway send buffer full, connection closed", "op", msg.Op, "conn", c.id)
+		c.close()
+	}
+}
+
+func (c *conn) writeLoop() {
+	for {
+		select {
+		case msg := <-c.send:
+			if !c.write(msg) {
+				return
+			}
+		case e := <-c.session.Events():
+			if !c.write(Message{Op: OpEvent, Pattern: e.Pattern, Type: e.Event.Type(), Payload: encodePayload(e.Event.Payload())}) {
+				return
+			}
+		case <-c.session.Done():
+			return
+		}
+	}
+}
+
+// write sends the message and reports whether the connection is still usable.
+func (c *conn) write(msg Message) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.gateway.log.Debug("gateway encode failed", "error", err, "conn", c.id)
+		return true
+	}
+	if err := c.ws.WriteMessage(data); err != nil {
+		c.close()
+		return false
+	}
+	return true
+}
+
+func (c *conn) close() {
+	c.once.Do(func() {
+		c.session.Close()
+		c.ws.Close()
+	})
+}
@@ -0,0 +1,253 @@
+package gateway
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dialGateway(t *testing.T, server *httptest.Server) *wsConn {
+	t.Helper()
+	addr := strings.TrimPrefix(server.URL, "http://")
+	netConn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	ws, err := dial(netConn, addr, "/")
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func readFrame(t *testing.T, ws *wsConn) Message {
+	t.Helper()
+	ws.conn.SetReadDeadline(time.Now().Add(time.Second))
+	data, err := ws.ReadMessage()
+	require.NoError(t, err)
+	var msg Message
+	require.NoError(t, json.Unmarshal(data, &msg))
+	return msg
+}
+
+func sendFrame(t *testing.T, ws *wsConn, msg Message) {
+	t.Helper()
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	require.NoError(t, ws.WriteMessage(data))
+}
+
+func allowAll(*http.Request, string, string) bool {
+	return true
+}
+
+type conformanceStep struct {
+	Send    json.RawMessage `json:"send"`
+	SendRaw string          `json:"send_raw"`
+	Expect  json.RawMessage `json:"expect"`
+	Emit    *struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	} `json:"emit"`
+}
+
+func TestConformance(t *testing.T) {
+	files, err := filepath.Glob("testdata/conformance/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var script struct {
+				Steps []conformanceStep `json:"steps"`
+			}
+			require.NoError(t, json.Unmarshal(data, &script))
+
+			bus := eventify.New()
+			server := httptest.NewServer(New(bus, WithAuthorize(allowAll)))
+			defer server.Close()
+			ws := dialGateway(t, server)
+
+			for i, step := range script.Steps {
+				switch {
+				case step.Send != nil:
+					require.NoError(t, ws.WriteMessage(step.Send), "step %d", i)
+				case step.SendRaw != "":
+					require.NoError(t, ws.WriteMessage([]byte(step.SendRaw)), "step %d", i)
+				case step.Emit != nil:
+					payload, err := decodePayload(step.Emit.Payload)
+					require.NoError(t, err)
+					bus.Emit(eventify.NewEvent(step.Emit.Type, payload))
+				case step.Expect != nil:
+					var want Message
+					require.NoError(t, json.Unmarshal(step.Expect, &want))
+					got := readFrame(t, ws)
+					if want.Op == OpError && got.Op == OpError && want.Error == "" {
+						got.Error = ""
+					}
+					if want.Payload != nil {
+						assert.JSONEq(t, string(want.Payload), string(got.Payload), "step %d", i)
+						want.Payload, got.Payload = nil, nil
+					}
+					assert.Equal(t, want, got, "step %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGateway_Authorize(t *testing.T) {
+	bus := eventify.New()
+	server := httptest.NewServer(New(bus, WithAuthorize(func(r *http.Request, op, target string) bool {
+		return !strings.HasPrefix(target, "admin.")
+	})))
+	defer server.Close()
+	ws := dialGateway(t, server)
+	readFrame(t, ws)
+
+	sendFrame(t, ws, Message{Op: OpSubscribe, ID: "1", Pattern: "admin.*"})
+	msg := readFrame(t, ws)
+	assert.Equal(t, OpError, msg.Op)
+	assert.Equal(t, "1", msg.ID)
+
+	sendFrame(t, ws, Message{Op: OpEmit, ID: "2", Type: "admin.reset"})
+	msg = readFrame(t, ws)
+	assert.Equal(t, OpError, msg.Op)
+}
+
+func TestGateway_SessionRate(t *testing.T) {
+	bus := eventify.New()
+	server := httptest.NewServer(New(bus, WithAuthorize(allowAll), WithSessionOptions(eventify.WithSessionRate(0, 1))))
+	defer server.Close()
+	ws := dialGateway(t, server)
+	readFrame(t, ws)
+
+	sendFrame(t, ws, Message{Op: OpEmit, ID: "1", Type: "chat.message"})
+	assert.Equal(t, OpAck, readFrame(t, ws).Op)
+	sendFrame(t, ws, Message{Op: OpEmit, ID: "2", Type: "chat.message"})
+	msg := readFrame(t, ws)
+	assert.Equal(t, OpError, msg.Op)
+	assert.Equal(t, eventify.ErrQuotaExceeded.Error(), msg.Error)
+}
+
+func TestGateway_RejectsPlainHTTP(t *testing.T) {
+	server := httptest.NewServer(New(eventify.New()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGateway_CheckOrigin(t *testing.T) {
+	server := httptest.NewServer(New(eventify.New(), WithCheckOrigin(func(r *http.Request) bool {
+		return r.Header.Get("Origin") == "https://app.example.com"
+	})))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestGateway_Defaults(t *testing.T) {
+	server := httptest.NewServer(New(eventify.New()))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "cross-site handshakes are rejected")
+
+	ws := dialGateway(t, server)
+	readFrame(t, ws)
+	sendFrame(t, ws, Message{Op: OpSubscribe, ID: "1", Pattern: "order.*"})
+	assert.Equal(t, OpAck, readFrame(t, ws).Op)
+	sendFrame(t, ws, Message{Op: OpEmit, ID: "2", Type: "order.created"})
+	msg := readFrame(t, ws)
+	assert.Equal(t, OpError, msg.Op, "emits require WithAuthorize")
+	assert.Equal(t, "2", msg.ID)
+}
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "", want: true},
+		{origin: "https://app.example.com", want: true},
+		{origin: "http://APP.example.com", want: true},
+		{origin: "https://evil.example.com", want: false},
+		{origin: "https://app.example.com:8443", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://app.example.com/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			assert.Equal(t, tt.want, sameOrigin(req))
+		})
+	}
+}
+
+func TestWebSocket_RejectsInvalidControlFrames(t *testing.T) {
+	tests := []struct {
+		name   string
+		header byte
+		size   int
+	}{
+		{name: "fragmented ping", header: opPing, size: 1},
+		{name: "oversized ping", header: 0x80 | opPing, size: 126},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer client.Close()
+			ws := &wsConn{conn: server, r: bufio.NewReader(server)}
+			frame := []byte{tt.header, 0x80 | byte(min(tt.size, 126))}
+			if tt.size >= 126 {
+				frame = binary.BigEndian.AppendUint16(frame, uint16(tt.size))
+			}
+			frame = append(frame, 0, 0, 0, 0)
+			frame = append(frame, make([]byte, tt.size)...)
+			go client.Write(frame)
+
+			_, err := ws.ReadMessage()
+
+			assert.ErrorIs(t, err, errProtocol)
+		})
+	}
+}
+
+func TestWebSocket_RejectsHugeFramesByDefault(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	ws := &wsConn{conn: server, r: bufio.NewReader(server)}
+	frame := []byte{0x80 | opBinary, 0x80 | 127}
+	frame = binary.BigEndian.AppendUint64(frame, 1<<62)
+	go client.Write(frame)
+
+	_, err := ws.ReadMessage()
+
+	assert.ErrorIs(t, err, errMessageTooBig)
+}
@@ -0,0 +1,332 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// GraphQLProtocol is the WebSocket subprotocol of the graphql-ws protocol spoken by GraphQL.
+const GraphQLProtocol = "graphql-transport-ws"
+
+// Message types of the graphql-ws protocol.
+const (
+	gqlConnectionInit = "connection_init"
+	gqlConnectionAck  = "connection_ack"
+	gqlPing           = "ping"
+	gqlPong           = "pong"
+	gqlSubscribe      = "subscribe"
+	gqlNext           = "next"
+	gqlError          = "error"
+	gqlComplete       = "complete"
+)
+
+// Close codes of the graphql-ws protocol.
+const (
+	gqlInvalidMessage     = 4400
+	gqlUnauthorized       = 4401
+	gqlSubscriberExists   = 4409
+	gqlTooManyInitRequest = 4429
+)
+
+// gqlMessage is a message of the graphql-ws protocol.
+type gqlMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// gqlSubscribePayload is the payload of a subscribe message.
+type gqlSubscribePayload struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// gqlErrorPayload is an error of the payload of an error message.
+type gqlErrorPayload struct {
+	Message string `json:"message"`
+}
+
+// GraphQL is an http.Handler serving bus events as GraphQL subscriptions over WebSocket,
+// with the graphql-ws protocol (subprotocol graphql-transport-ws).
+//
+// Every subscription field is mapped to an event pattern: a subscription such as
+//
+//	subscription { order: orderCreated { id total customer { email } } }
+//
+// receives the events matching the pattern of orderCreated, their JSON payload reduced to the selected fields,
+// as {"data": {"order": {...}}}. Non-JSON payloads are sent as strings whatever the selection, and a field
+// without selection set receives the whole payload. Only this subset of GraphQL is supported: a single
+// subscription field per operation, aliases and nested selection sets; arguments, variables, fragments and
+// directives are rejected.
+type GraphQL struct {
+	gateway *Gateway
+	fields  map[string]string
+}
+
+// NewGraphQL creates a GraphQL handler for the bus, mapping the subscription fields to event patterns,
+// such as "orderCreated" to "order.created". The options of the Gateway apply: WithAuthorize is asked
+// for OpSubscribe with the pattern of the field.
+func NewGraphQL(bus *eventify.Eventify, fields map[string]string, opts ...OptionFunc) *GraphQL {
+	return &GraphQL{gateway: New(bus, opts...), fields: fields}
+}
+
+// ServeHTTP upgrades the request to a WebSocket and serves the graphql-ws protocol until the client disconnects.
+func (g *GraphQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !g.gateway.checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	ws, err := upgrade(w, r, g.gateway.maxMessageSize, GraphQLProtocol)
+	if err != nil {
+		g.gateway.log.Debug("gateway graphql handshake failed", "error", err)
+		return
+	}
+	c := &gqlConn{
+		graphql:       g,
+		id:            g.gateway.nextID.Add(1),
+		request:       r,
+		ws:            ws,
+		session:       g.gateway.bus.NewSession(g.gateway.sessionOpts...),
+		subscriptions: map[string]*gqlSubscription{},
+	}
+	go c.writeLoop()
+	c.readLoop()
+}
+
+// gqlSubscription is a subscription of a connection.
+type gqlSubscription struct {
+	pattern   string
+	key       string
+	selection []gqlField
+}
+
+type gqlConn struct {
+	graphql *GraphQL
+	id      uint64
+	request *http.Request
+	ws      *wsConn
+	session *eventify.Session
+	once    sync.Once
+
+	mutex         sync.Mutex
+	initialized   bool
+	subscriptions map[string]*gqlSubscription
+}
+
+func (c *gqlConn) readLoop() {
+	defer c.close(1000, "")
+	for {
+		data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg gqlMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			c.close(gqlInvalidMessage, "Invalid message received")
+			return
+		}
+		if !c.handle(msg) {
+			return
+		}
+	}
+}
+
+// handle handles the message and reports whether the connection is still usable.
+func (c *gqlConn) handle(msg gqlMessage) bool {
+	c.mutex.Lock()
+	initialized := c.initialized
+	c.mutex.Unlock()
+	switch msg.Type {
+	case gqlConnectionInit:
+		if initialized {
+			c.close(gqlTooManyInitRequest, "Too many initialisation requests")
+			return false
+		}
+		c.mutex.Lock()
+		c.initialized = true
+		c.mutex.Unlock()
+		return c.write(gqlMessage{Type: gqlConnectionAck})
+	case gqlPing:
+		return c.write(gqlMessage{Type: gqlPong})
+	case gqlPong:
+		return true
+	case gqlSubscribe:
+		if !initialized {
+			c.close(gqlUnauthorized, "Unauthorized")
+			return false
+		}
+		if msg.ID == "" {
+			c.close(gqlInvalidMessage, "Invalid message received")
+			return false
+		}
+		return c.subscribe(msg)
+	case gqlComplete:
+		c.complete(msg.ID)
+		return true
+	default:
+		c.close(gqlInvalidMessage, "Invalid message received")
+		return false
+	}
+}
+
+func (c *gqlConn) subscribe(msg gqlMessage) bool {
+	c.mutex.Lock()
+	_, exists := c.subscriptions[msg.ID]
+	c.mutex.Unlock()
+	if exists {
+		c.close(gqlSubscriberExists, "Subscriber for "+msg.ID+" already exists")
+		return false
+	}
+	var payload gqlSubscribePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		c.close(gqlInvalidMessage, "Invalid message received")
+		return false
+	}
+	sub, err := c.graphql.parse(payload.Query)
+	if err == nil && !c.graphql.gateway.authorize(c.request, OpSubscribe, sub.pattern) {
+		err = fmt.Errorf("not allowed to subscribe to %q", sub.key)
+	}
+	if err == nil {
+		// The subscription is recorded first so the events of its pattern that arrive right away reach it.
+		c.mutex.Lock()
+		c.subscriptions[msg.ID] = sub
+		c.mutex.Unlock()
+		if err = c.session.Subscribe(sub.pattern); err != nil {
+			c.mutex.Lock()
+			delete(c.subscriptions, msg.ID)
+			c.mutex.Unlock()
+		}
+	}
+	if err != nil {
+		errs, _ := json.Marshal([]gqlErrorPayload{{Message: err.Error()}})
+		return c.write(gqlMessage{ID: msg.ID, Type: gqlError, Payload: errs})
+	}
+	return true
+}
+
+// complete ends the subscription, unsubscribing from its pattern unless another subscription uses it.
+func (c *gqlConn) complete(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	sub, ok := c.subscriptions[id]
+	if !ok {
+		return
+	}
+	delete(c.subscriptions, id)
+	for _, other := range c.subscriptions {
+		if other.pattern == sub.pattern {
+			return
+		}
+	}
+	if err := c.session.Unsubscribe(sub.pattern); err != nil {
+		c.graphql.gateway.log.Debug("gateway graphql unsubscribe failed", "error", err, "conn", c.id)
+	}
+}
+
+func (c *gqlConn) writeLoop() {
+	for {
+		select {
+		case e := <-c.session.Events():
+			for id, sub := range c.matching(e.Pattern) {
+				data := map[string]any{sub.key: selectPayload(e.Event.Payload(), sub.selection)}
+				payload, err := json.Marshal(map[string]any{"data": data})
+				if err != nil {
+					c.graphql.gateway.log.Debug("gateway graphql encode failed", "error", err, "conn", c.id)
+					continue
+				}
+				if !c.write(gqlMessage{ID: id, Type: gqlNext, Payload: payload}) {
+					return
+				}
+			}
+		case <-c.session.Done():
+			return
+		}
+	}
+}
+
+// matching returns the subscriptions to the pattern by ID.
+func (c *gqlConn) matching(pattern string) map[string]*gqlSubscription {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	subs := map[string]*gqlSubscription{}
+	for id, sub := range c.subscriptions {
+		if sub.pattern == pattern {
+			subs[id] = sub
+		}
+	}
+	return subs
+}
+
+// write sends the message and reports whether the connection is still usable.
+func (c *gqlConn) write(msg gqlMessage) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.graphql.gateway.log.Debug("gateway graphql encode failed", "error", err, "conn", c.id)
+		return true
+	}
+	if err := c.ws.WriteMessage(data); err != nil {
+		c.close(1000, "")
+		return false
+	}
+	return true
+}
+
+func (c *gqlConn) close(code uint16, reason string) {
+	c.once.Do(func() {
+		c.session.Close()
+		c.ws.CloseWithCode(code, reason)
+	})
+}
+
+// parse parses the subscription query into the subscription it describes.
+func (g *GraphQL) parse(query string) (*gqlSubscription, error) {
+	field, err := parseSubscription(query)
+	if err != nil {
+		return nil, err
+	}
+	pattern, ok := g.fields[field.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown subscription field %q", field.name)
+	}
+	return &gqlSubscription{pattern: pattern, key: field.key(), selection: field.selection}, nil
+}
+
+// selectPayload returns the selected fields of the JSON payload, the whole payload without selection,
+// or the payload as a string if it is not JSON.
+func selectPayload(payload []byte, selection []gqlField) any {
+	if len(payload) == 0 {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return string(payload)
+	}
+	return selectValue(value, selection)
+}
+
+func selectValue(value any, selection []gqlField) any {
+	if len(selection) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		selected := make(map[string]any, len(selection))
+		for _, field := range selection {
+			selected[field.key()] = selectValue(v[field.name], field.selection)
+		}
+		return selected
+	case []any:
+		selected := make([]any, len(v))
+		for i, element := range v {
+			selected[i] = selectValue(element, selection)
+		}
+		return selected
+	default:
+		return value
+	}
+}
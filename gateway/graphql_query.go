@@ -0,0 +1,183 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// gqlField is a field of a selection set.
+type gqlField struct {
+	alias     string
+	name      string
+	selection []gqlField
+}
+
+// key returns the key of the field in the response: its alias if any, its name otherwise.
+func (f gqlField) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// errUnsupportedGraphQL is returned for the GraphQL features GraphQL doesn't support.
+var errUnsupportedGraphQL = errors.New("unsupported GraphQL")
+
+// gqlParser parses the subset of GraphQL supported by GraphQL.
+type gqlParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseSubscription parses a subscription operation with a single root field.
+func parseSubscription(query string) (gqlField, error) {
+	tokens, err := tokenizeGraphQL(query)
+	if err != nil {
+		return gqlField{}, err
+	}
+	p := &gqlParser{tokens: tokens}
+	switch p.peek() {
+	case "subscription":
+		p.next()
+		if isGraphQLName(p.peek()) {
+			p.next()
+		}
+	case "query", "mutation":
+		return gqlField{}, fmt.Errorf("%w: only subscriptions are supported, not %s", errUnsupportedGraphQL, p.peek())
+	default:
+		return gqlField{}, fmt.Errorf("%w: expected a subscription operation", errUnsupportedGraphQL)
+	}
+	selection, err := p.selectionSet()
+	if err != nil {
+		return gqlField{}, err
+	}
+	if p.peek() != "" {
+		return gqlField{}, fmt.Errorf("%w: a single operation is supported, found %q", errUnsupportedGraphQL, p.peek())
+	}
+	if len(selection) != 1 {
+		return gqlField{}, fmt.Errorf("subscription must select exactly one field, found %d", len(selection))
+	}
+	return selection[0], nil
+}
+
+func (p *gqlParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *gqlParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+// selectionSet parses "{" field+ "}".
+func (p *gqlParser) selectionSet() ([]gqlField, error) {
+	if token := p.next(); token != "{" {
+		return nil, unexpectedGraphQLToken(token, "{")
+	}
+	var fields []gqlField
+	for p.peek() != "}" {
+		field, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty selection set")
+	}
+	return fields, nil
+}
+
+// field parses [alias ":"] name [selectionSet].
+func (p *gqlParser) field() (gqlField, error) {
+	token := p.next()
+	if !isGraphQLName(token) {
+		return gqlField{}, unexpectedGraphQLToken(token, "a field")
+	}
+	field := gqlField{name: token}
+	if p.peek() == ":" {
+		p.next()
+		token = p.next()
+		if !isGraphQLName(token) {
+			return gqlField{}, unexpectedGraphQLToken(token, "a field")
+		}
+		field.alias, field.name = field.name, token
+	}
+	switch p.peek() {
+	case "(":
+		return gqlField{}, fmt.Errorf("%w: arguments of %s", errUnsupportedGraphQL, field.name)
+	case "@":
+		return gqlField{}, fmt.Errorf("%w: directives on %s", errUnsupportedGraphQL, field.name)
+	case "{":
+		selection, err := p.selectionSet()
+		if err != nil {
+			return gqlField{}, err
+		}
+		field.selection = selection
+	}
+	return field, nil
+}
+
+func unexpectedGraphQLToken(token, expected string) error {
+	switch {
+	case token == "":
+		return fmt.Errorf("unexpected end of query, expected %s", expected)
+	case token == "...":
+		return fmt.Errorf("%w: fragments", errUnsupportedGraphQL)
+	case strings.HasPrefix(token, "$"):
+		return fmt.Errorf("%w: variables", errUnsupportedGraphQL)
+	default:
+		return fmt.Errorf("unexpected %q, expected %s", token, expected)
+	}
+}
+
+// tokenizeGraphQL splits the query into names, punctuators and "$"-prefixed variables,
+// skipping whitespace, commas and comments.
+func tokenizeGraphQL(query string) ([]string, error) {
+	var tokens []string
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',' || r == '\uFEFF':
+			i++
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' && runes[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(string(runes[i:min(i+3, len(runes))]), "..."):
+			tokens = append(tokens, "...")
+			i += 3
+		case strings.ContainsRune("{}():@!=[]", r):
+			tokens = append(tokens, string(r))
+			i++
+		case r == '$' || r == '_' || isASCIILetter(r):
+			start := i
+			i++
+			for i < len(runes) && (runes[i] == '_' || isASCIILetter(runes[i]) || runes[i] >= '0' && runes[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case r == '"' || r >= '0' && r <= '9' || r == '-':
+			return nil, fmt.Errorf("%w: literal values", errUnsupportedGraphQL)
+		default:
+			return nil, fmt.Errorf("unexpected character %q in query", r)
+		}
+	}
+	return tokens, nil
+}
+
+func isASCIILetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isGraphQLName(token string) bool {
+	return token != "" && (token[0] == '_' || isASCIILetter(rune(token[0])))
+}
@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSubscription(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    gqlField
+		wantErr string
+	}{
+		{
+			name:  "nested selection",
+			query: "subscription OnOrder { orderCreated { id, total customer { email } } }",
+			want: gqlField{name: "orderCreated", selection: []gqlField{
+				{name: "id"}, {name: "total"}, {name: "customer", selection: []gqlField{{name: "email"}}},
+			}},
+		},
+		{
+			name:  "alias and comments",
+			query: "# new orders\nsubscription {\n  order: orderCreated { ref: id }\n}",
+			want:  gqlField{alias: "order", name: "orderCreated", selection: []gqlField{{alias: "ref", name: "id"}}},
+		},
+		{name: "whole payload", query: "subscription { orderCreated }", want: gqlField{name: "orderCreated"}},
+		{name: "query", query: "query { orders { id } }", wantErr: "only subscriptions are supported"},
+		{name: "shorthand", query: "{ orderCreated }", wantErr: "expected a subscription operation"},
+		{name: "two fields", query: "subscription { a b }", wantErr: "exactly one field"},
+		{name: "arguments", query: `subscription { orderCreated(id: "1") { id } }`, wantErr: "literal values"},
+		{name: "fragments", query: "subscription { orderCreated { ...Order } }", wantErr: "fragments"},
+		{name: "unterminated", query: "subscription { orderCreated { id }", wantErr: "unexpected end of query"},
+		{name: "empty selection", query: "subscription { orderCreated { } }", wantErr: "empty selection set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSubscription(tt.query)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelectPayload(t *testing.T) {
+	selection := []gqlField{{name: "id"}, {alias: "items", name: "lines", selection: []gqlField{{name: "sku"}}}, {name: "missing"}}
+	payload := []byte(`{"id":"o-1","total":10,"lines":[{"sku":"a","qty":1},{"sku":"b","qty":2}]}`)
+
+	assert.Equal(t, map[string]any{
+		"id":      "o-1",
+		"items":   []any{map[string]any{"sku": "a"}, map[string]any{"sku": "b"}},
+		"missing": nil,
+	}, selectPayload(payload, selection))
+	assert.Equal(t, "plain text", selectPayload([]byte("plain text"), selection))
+	assert.Nil(t, selectPayload(nil, selection))
+}
+
+func dialGraphQL(t *testing.T, server *httptest.Server) *wsConn {
+	t.Helper()
+	addr := strings.TrimPrefix(server.URL, "http://")
+	netConn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	ws, err := dial(netConn, addr, "/", GraphQLProtocol)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func sendGraphQL(t *testing.T, ws *wsConn, msg string) {
+	t.Helper()
+	require.NoError(t, ws.WriteMessage([]byte(msg)))
+}
+
+func readGraphQL(t *testing.T, ws *wsConn) string {
+	t.Helper()
+	ws.conn.SetReadDeadline(time.Now().Add(time.Second))
+	data, err := ws.ReadMessage()
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGraphQL(t *testing.T) {
+	bus := eventify.New()
+	server := httptest.NewServer(NewGraphQL(bus, map[string]string{"orderCreated": "order.created", "userCreated": "user.*"},
+		WithAuthorize(func(_ *http.Request, op, target string) bool { return target != "user.*" })))
+	defer server.Close()
+	ws := dialGraphQL(t, server)
+
+	sendGraphQL(t, ws, `{"type":"connection_init"}`)
+	assert.JSONEq(t, `{"type":"connection_ack"}`, readGraphQL(t, ws))
+	sendGraphQL(t, ws, `{"type":"ping"}`)
+	assert.JSONEq(t, `{"type":"pong"}`, readGraphQL(t, ws))
+
+	sendGraphQL(t, ws, `{"id":"1","type":"subscribe","payload":{"query":"subscription { order: orderCreated { id customer { email } } }"}}`)
+	sendGraphQL(t, ws, `{"id":"2","type":"subscribe","payload":{"query":"subscription { unknown { id } }"}}`)
+	assert.JSONEq(t, `{"id":"2","type":"error","payload":[{"message":"unknown subscription field \"unknown\""}]}`, readGraphQL(t, ws))
+	sendGraphQL(t, ws, `{"id":"3","type":"subscribe","payload":{"query":"subscription { userCreated }"}}`)
+	assert.JSONEq(t, `{"id":"3","type":"error","payload":[{"message":"not allowed to subscribe to \"userCreated\""}]}`, readGraphQL(t, ws))
+
+	bus.EmitBy("order.created", map[string]any{"id": "o-1", "total": 10, "customer": map[string]any{"email": "a@b.c", "name": "A"}})
+	assert.JSONEq(t, `{"id":"1","type":"next","payload":{"data":{"order":{"id":"o-1","customer":{"email":"a@b.c"}}}}}`, readGraphQL(t, ws))
+
+	sendGraphQL(t, ws, `{"id":"1","type":"complete"}`)
+	sendGraphQL(t, ws, `{"type":"ping"}`)
+	assert.JSONEq(t, `{"type":"pong"}`, readGraphQL(t, ws))
+	bus.EmitBy("order.created", map[string]any{"id": "o-2"})
+	sendGraphQL(t, ws, `{"type":"ping"}`)
+	assert.JSONEq(t, `{"type":"pong"}`, readGraphQL(t, ws), "no event after complete")
+}
+
+func TestGraphQL_ProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+	}{
+		{name: "subscribe before init", messages: []string{`{"id":"1","type":"subscribe","payload":{"query":"subscription { orderCreated }"}}`}},
+		{name: "init twice", messages: []string{`{"type":"connection_init"}`, `{"type":"connection_init"}`}},
+		{name: "invalid message", messages: []string{`{"type":"connection_init"}`, `not json`}},
+		{name: "duplicate id", messages: []string{
+			`{"type":"connection_init"}`,
+			`{"id":"1","type":"subscribe","payload":{"query":"subscription { orderCreated }"}}`,
+			`{"id":"1","type":"subscribe","payload":{"query":"subscription { orderCreated }"}}`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(NewGraphQL(eventify.New(), map[string]string{"orderCreated": "order.created"}))
+			defer server.Close()
+			ws := dialGraphQL(t, server)
+
+			for _, msg := range tt.messages {
+				sendGraphQL(t, ws, msg)
+			}
+
+			ws.conn.SetReadDeadline(time.Now().Add(time.Second))
+			for {
+				data, err := ws.ReadMessage()
+				if err != nil {
+					assert.ErrorIs(t, err, errConnectionDone)
+					return
+				}
+				var msg gqlMessage
+				require.NoError(t, json.Unmarshal(data, &msg))
+				require.Equal(t, gqlConnectionAck, msg.Type, "only acks before the close")
+			}
+		})
+	}
+}
+
+func TestGraphQL_RequiresSubprotocol(t *testing.T) {
+	server := httptest.NewServer(NewGraphQL(eventify.New(), nil))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+	netConn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer netConn.Close()
+
+	_, err = dial(netConn, addr, "/")
+	assert.ErrorIs(t, err, errNotWebSocket)
+}
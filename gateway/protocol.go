@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ProtocolVersion is the version of the wire protocol announced in the welcome frame.
+const ProtocolVersion = 1
+
+// Frame operations of the wire protocol, see protocol.md.
+const (
+	OpWelcome     = "welcome"
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+	OpEmit        = "emit"
+	OpAck         = "ack"
+	OpError       = "error"
+	OpEvent       = "event"
+)
+
+// Message is a frame of the wire protocol.
+// Every WebSocket text message carries exactly one Message encoded as JSON.
+type Message struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id,omitempty"`
+	Pattern string          `json:"pattern,omitempty"`
+	Type    string          `json:"type,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Version int             `json:"version,omitempty"`
+}
+
+// encodePayload converts an event payload into its wire form:
+// valid JSON is sent as is, anything else as a JSON string.
+func encodePayload(payload []byte) json.RawMessage {
+	if len(payload) == 0 {
+		return nil
+	}
+	if json.Valid(payload) {
+		return payload
+	}
+	bz, _ := json.Marshal(string(payload))
+	return bz
+}
+
+// decodePayload converts a wire payload into event payload bytes:
+// a JSON string becomes its raw contents, any other JSON value is kept as JSON.
+func decodePayload(raw json.RawMessage) ([]byte, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+	return raw, nil
+}
@@ -0,0 +1,293 @@
+package gateway
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is the magic value of RFC 6455 used to compute Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+var (
+	errNotWebSocket   = errors.New("gateway: not a websocket handshake")
+	errMessageTooBig  = errors.New("gateway: message too big")
+	errProtocol       = errors.New("gateway: websocket protocol error")
+	errConnectionDone = errors.New("gateway: connection closed")
+)
+
+// wsConn is a minimal RFC 6455 connection supporting text messages,
+// fragmentation, ping/pong and the closing handshake.
+type wsConn struct {
+	conn     net.Conn
+	r        *bufio.Reader
+	isClient bool
+	maxSize  int64
+
+	writeMutex sync.Mutex
+}
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upgrade performs the server side of the opening handshake.
+// With a subprotocol, the client must offer it in Sec-WebSocket-Protocol.
+func upgrade(w http.ResponseWriter, r *http.Request, maxSize int64, subprotocol ...string) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		http.Error(w, "websocket handshake required", http.StatusBadRequest)
+		return nil, errNotWebSocket
+	}
+	protocolHeader := ""
+	if len(subprotocol) > 0 {
+		if !headerContains(r.Header, "Sec-WebSocket-Protocol", subprotocol[0]) {
+			http.Error(w, "websocket subprotocol "+subprotocol[0]+" required", http.StatusBadRequest)
+			return nil, errNotWebSocket
+		}
+		protocolHeader = "Sec-WebSocket-Protocol: " + subprotocol[0] + "\r\n"
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errNotWebSocket
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n" +
+		protocolHeader + "\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, r: rw.Reader, maxSize: maxSize}, nil
+}
+
+// dial performs the client side of the opening handshake on an established connection,
+// offering the subprotocol if any.
+func dial(conn net.Conn, host, path string, subprotocol ...string) (*wsConn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	request := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: " + host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n"
+	for _, protocol := range subprotocol {
+		request += "Sec-WebSocket-Protocol: " + protocol + "\r\n"
+	}
+	request += "\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, fmt.Errorf("%w: status %s", errNotWebSocket, resp.Status)
+	}
+	return &wsConn{conn: conn, r: r, isClient: true}, nil
+}
+
+// ReadMessage returns the next text or binary message, answering pings on the way.
+// It returns errConnectionDone once the peer closes the connection.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return nil, errConnectionDone
+		case opText, opBinary, opContinuation:
+			if opcode != opContinuation && message != nil || opcode == opContinuation && message == nil {
+				return nil, errProtocol
+			}
+			message = append(message, payload...)
+			if message == nil {
+				message = []byte{}
+			}
+			if int64(len(message)) > c.limit() {
+				return nil, errMessageTooBig
+			}
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, errProtocol
+		}
+	}
+}
+
+// WriteMessage writes a text message.
+func (c *wsConn) WriteMessage(message []byte) error {
+	return c.writeFrame(opText, message)
+}
+
+// Close sends a close frame and closes the connection.
+func (c *wsConn) Close() error {
+	c.writeFrame(opClose, nil)
+	return c.conn.Close()
+}
+
+// CloseWithCode sends a close frame with the status code and reason and closes the connection.
+func (c *wsConn) CloseWithCode(code uint16, reason string) error {
+	c.writeFrame(opClose, append(binary.BigEndian.AppendUint16(nil, code), reason...))
+	return c.conn.Close()
+}
+
+// limit returns the maximum size of a message, defaultMaxMessageSize if none is set.
+func (c *wsConn) limit() int64 {
+	if c.maxSize <= 0 {
+		return defaultMaxMessageSize
+	}
+	return c.maxSize
+}
+
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.r, header[:]); err != nil {
+		return
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	if masked == c.isClient {
+		err = errProtocol
+		return
+	}
+	size := int64(header[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.r, ext[:]); err != nil {
+			return
+		}
+		size = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.r, ext[:]); err != nil {
+			return
+		}
+		size = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	// The size is checked before allocating the payload, since it is declared by the peer.
+	if size < 0 || size > c.limit() {
+		err = errMessageTooBig
+		return
+	}
+	// Control frames can't be fragmented and carry at most 125 bytes (RFC 6455 section 5.5).
+	if opcode&0x8 != 0 && (!fin || size > 125) {
+		err = errProtocol
+		return
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.r, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(c.r, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	header := make([]byte, 0, 14)
+	header = append(header, 0x80|opcode)
+	maskBit := byte(0)
+	if c.isClient {
+		maskBit = 0x80
+	}
+	switch size := len(payload); {
+	case size < 126:
+		header = append(header, maskBit|byte(size))
+	case size <= 0xFFFF:
+		header = append(header, maskBit|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(size))
+	default:
+		header = append(header, maskBit|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(size))
+	}
+	if c.isClient {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		header = append(header, mask[:]...)
+		masked := make([]byte, len(payload))
+		for i := range payload {
+			masked[i] = payload[i] ^ mask[i%4]
+		}
+		payload = masked
+	}
+	if _, err := c.conn.Write(header); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(payload)
+	return err
+}
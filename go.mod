@@ -2,7 +2,10 @@ module github.com/payme50rmb/eventify
 
 go 1.24.4
 
-require github.com/stretchr/testify v1.11.1
+require (
+	github.com/expr-lang/expr v1.17.8
+	github.com/stretchr/testify v1.11.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,95 @@
+package eventify
+
+import (
+	"errors"
+	"sync"
+)
+
+// Group collects events of a unit of work so they are emitted together or not at all,
+// and business logic failing halfway doesn't emit half a set of events.
+type Group struct {
+	eventify *Eventify
+	mutex    sync.Mutex
+	events   []Event
+}
+
+// Group returns a new empty group.
+func (e *Eventify) Group() *Group {
+	return &Group{eventify: e}
+}
+
+// Emit adds the event to the group.
+func (g *Group) Emit(event Event) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.events = append(g.events, event)
+}
+
+// EmitBy creates a new event and adds it to the group.
+// A payload that can't be marshaled returns an error wrapping ErrMarshalPayload.
+func (g *Group) EmitBy(eventType string, payload any) error {
+	if event, ok := payload.(Event); ok {
+		g.Emit(event)
+		return nil
+	}
+	bz, err := g.eventify._AnyToBytes(payload)
+	if err != nil {
+		return err
+	}
+	g.Emit(NewEvent(g.eventify._Normalize(eventType), bz))
+	return nil
+}
+
+// Len returns the number of events in the group.
+func (g *Group) Len() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return len(g.events)
+}
+
+// Flush emits the events of the group in order and empties it.
+// Every event is checked and validated before any is delivered: if one has an unknown type or a payload
+// too large, exceeds the memory limit or is vetoed by a validator, none is emitted and the errors are returned;
+// the rejected events are reported like rejected emits.
+// The validators of the other events have run nonetheless.
+// Events emitted by other goroutines may be interleaved with those of the group.
+func (g *Group) Flush() error {
+	g.mutex.Lock()
+	events := g.events
+	g.events = nil
+	g.mutex.Unlock()
+	types := make([]string, len(events))
+	listeners := make([][]Listener, len(events))
+	var errs []error
+	for i, event := range events {
+		var err error
+		types[i], listeners[i], err = g.eventify._Admit(event)
+		if err != nil {
+			if event != nil {
+				g.eventify._Reject(event, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		// The admitted events are discarded, so their deliveries must not be awaited by Drain.
+		for _, admitted := range listeners {
+			for _, listener := range admitted {
+				g.eventify.inflight.Release(listener)
+			}
+		}
+		g.eventify.log.Debug("eventify group discarded", "events", len(events), "error", errors.Join(errs...))
+		return errors.Join(errs...)
+	}
+	for i, event := range events {
+		g.eventify._Dispatch(event, types[i], listeners[i], newEmitConfig(nil))
+	}
+	return nil
+}
+
+// Discard drops the events of the group.
+func (g *Group) Discard() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.events = nil
+}
@@ -0,0 +1,67 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	e := NewEventify(WithMaxPayloadSize(4))
+	var received []string
+	e.Register("*", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+
+	group := e.Group()
+	group.Emit(NewEvent("order.created", nil))
+	require.NoError(t, group.EmitBy("stock.reserved", "1"))
+	assert.Empty(t, received)
+	assert.Equal(t, 2, group.Len())
+
+	require.NoError(t, group.Flush())
+	assert.Equal(t, []string{"order.created", "stock.reserved"}, received)
+	assert.Zero(t, group.Len())
+
+	group.Emit(NewEvent("order.cancelled", nil))
+	group.Discard()
+	require.NoError(t, group.Flush())
+	assert.Len(t, received, 2)
+
+	group.Emit(NewEvent("order.created", nil))
+	group.Emit(NewEvent("order.huge", []byte("12345")))
+	assert.ErrorIs(t, group.Flush(), ErrPayloadTooLarge)
+	assert.Len(t, received, 2, "no event of a group with a rejected event is emitted")
+	assert.ErrorIs(t, group.EmitBy("order.bad", make(chan int)), ErrMarshalPayload)
+
+	e.Register("order.vetoed", NewValidator(func(Event) error { return assert.AnError }))
+	group.Emit(NewEvent("order.created", nil))
+	group.Emit(NewEvent("order.vetoed", nil))
+	assert.ErrorIs(t, group.Flush(), assert.AnError)
+	assert.Len(t, received, 2, "no event of a group with a vetoed event is emitted")
+}
+
+func TestGroup_PartialRejection(t *testing.T) {
+	e := NewEventify()
+	var received []string
+	e.Register("order.*", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+	e.Register("order.vetoed", NewValidator(func(Event) error { return assert.AnError }))
+
+	group := e.Group()
+	group.Emit(NewEvent("order.created", nil))
+	group.Emit(NewEvent("order.vetoed", nil))
+	assert.ErrorIs(t, group.Flush(), ErrVetoed)
+	assert.Empty(t, received)
+	assert.Equal(t, uint64(1), e.DeliveryCounts()[StatusVetoed])
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, e.Drain(ctx))
+}
@@ -0,0 +1,131 @@
+package eventify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTimeLimitExceeded is returned, wrapping the listener's own error if any, for the invocations
+// that ran longer than the time limit of their listener.
+var ErrTimeLimitExceeded = errors.New("eventify: listener time limit exceeded")
+
+// ViolationType is the type of the events emitted when a listener exceeds one of its limits.
+const ViolationType = "eventify.listener_violation"
+
+// Kinds of limit violations.
+const (
+	ViolationTime  = "time"
+	ViolationAlloc = "alloc"
+)
+
+// Violation is the payload of a ViolationType event.
+type Violation struct {
+	// Listener is the name of the listener, or its type if it isn't Namable.
+	Listener string `json:"listener"`
+	// Event is the type of the event the listener was handling.
+	Event string `json:"event"`
+	// Kind is ViolationTime or ViolationAlloc.
+	Kind string `json:"kind"`
+	// Limit is the limit, in nanoseconds for ViolationTime and in bytes for ViolationAlloc.
+	Limit int64 `json:"limit"`
+	// Used is how much the invocation used when the violation was detected, in the unit of the limit.
+	Used int64 `json:"used"`
+}
+
+// WithTimeLimit limits every invocation of the listener to the duration. A ContextListener gets a context
+// with the deadline; a watchdog emits a ViolationType event as soon as an invocation overruns,
+// while it is still running, and the invocation then fails with ErrTimeLimitExceeded.
+// Go can't stop a handler that ignores its context, so the limit reports runaway handlers rather than killing them.
+// Limits are kept per listener, so only comparable listeners can be limited.
+func WithTimeLimit(d time.Duration) RegisterOption {
+	return func(r *registration) {
+		r.timeLimit = d
+	}
+}
+
+// WithAllocLimit emits a ViolationType event for the invocations of the listener that allocated more than
+// bytes. Allocations are only tracked in debug builds, with the eventify_debug build tag, because reading
+// them costs on every invocation; the limit is ignored otherwise. They are measured process-wide,
+// so concurrent invocations and goroutines are counted too.
+// Limits are kept per listener, so only comparable listeners can be limited.
+func WithAllocLimit(bytes int64) RegisterOption {
+	return func(r *registration) {
+		r.allocLimit = bytes
+	}
+}
+
+// listenerGuard holds the limits of a listener.
+type listenerGuard struct {
+	timeLimit  time.Duration
+	allocLimit int64
+}
+
+// _Guard records the limits of the registration of the listener.
+func (e *Eventify) _Guard(listener Listener, r *registration) {
+	key := statsKey(listener)
+	if key == nil || (r.timeLimit <= 0 && r.allocLimit <= 0) {
+		return
+	}
+	e.guards.Store(key, &listenerGuard{timeLimit: r.timeLimit, allocLimit: r.allocLimit})
+}
+
+// _Guarded invokes the listener within its limits, if it has any.
+func (e *Eventify) _Guarded(ctx context.Context, event Event, listener Listener) error {
+	key := statsKey(listener)
+	if key == nil {
+		return e._Invoke(ctx, event, listener)
+	}
+	value, ok := e.guards.Load(key)
+	if !ok {
+		return e._Invoke(ctx, event, listener)
+	}
+	guard := value.(*listenerGuard)
+	if guard.timeLimit <= 0 && (guard.allocLimit <= 0 || !allocTracking) {
+		return e._Invoke(ctx, event, listener)
+	}
+
+	// reported is set by the watchdog or by the invocation, whichever is first, so an overrun is reported once.
+	var reported atomic.Bool
+	if guard.timeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, guard.timeLimit)
+		defer cancel()
+		e.scheduler.AfterFunc(guard.timeLimit, func() {
+			if reported.CompareAndSwap(false, true) {
+				e._Violated(event, listener, ViolationTime, int64(guard.timeLimit), int64(guard.timeLimit))
+			}
+		})
+	}
+	allocated := allocatedBytes()
+	start := time.Now()
+	err := e._Invoke(ctx, event, listener)
+	elapsed := time.Since(start)
+	overrun := guard.timeLimit > 0 && elapsed > guard.timeLimit
+	if reported.CompareAndSwap(false, true) && overrun {
+		e._Violated(event, listener, ViolationTime, int64(guard.timeLimit), int64(elapsed))
+	}
+	if guard.allocLimit > 0 && allocTracking {
+		if used := int64(allocatedBytes() - allocated); used > guard.allocLimit {
+			e._Violated(event, listener, ViolationAlloc, guard.allocLimit, used)
+		}
+	}
+	if overrun {
+		if err != nil {
+			return fmt.Errorf("%w after %s: %w", ErrTimeLimitExceeded, elapsed, err)
+		}
+		return fmt.Errorf("%w after %s", ErrTimeLimitExceeded, elapsed)
+	}
+	return err
+}
+
+// _Violated emits the violation of a limit by the listener.
+func (e *Eventify) _Violated(event Event, listener Listener, kind string, limit, used int64) {
+	violation := Violation{Listener: listenerLabel(listener), Event: event.Type(), Kind: kind, Limit: limit, Used: used}
+	withEventFields(e.log, event, listener).Debug("eventify listener limit exceeded", "event", event.Type(), "kind", kind, "limit", limit, "used", used)
+	payload, _ := json.Marshal(violation)
+	e.Emit(NewEvent(ViolationType, payload))
+}
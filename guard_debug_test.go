@@ -0,0 +1,36 @@
+//go:build eventify_debug
+
+package eventify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allocSink []byte
+
+func TestEventify_WithAllocLimit(t *testing.T) {
+	e := NewEventify()
+	var violations []Violation
+	e.Register(ViolationType, NewListener(func(event Event) error {
+		var v Violation
+		assert.NoError(t, json.Unmarshal(event.Payload(), &v))
+		violations = append(violations, v)
+		return nil
+	}))
+	e.Register("job.run", NewNamedListener("greedy", func(Event) error {
+		allocSink = make([]byte, 1<<20)
+		return nil
+	}), WithAllocLimit(1<<10))
+
+	e.EmitBy("job.run", nil)
+
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "greedy", violations[0].Listener)
+		assert.Equal(t, ViolationAlloc, violations[0].Kind)
+		assert.Equal(t, int64(1<<10), violations[0].Limit)
+		assert.GreaterOrEqual(t, violations[0].Used, int64(1<<20))
+	}
+}
@@ -0,0 +1,138 @@
+package eventify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_WithTimeLimit(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	tests := []struct {
+		name          string
+		handle        func(ctx context.Context) error
+		wantErr       []error
+		wantViolation bool
+	}{
+		{
+			name:   "within limit",
+			handle: func(context.Context) error { return nil },
+		},
+		{
+			name: "overrun until deadline",
+			handle: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			wantErr:       []error{ErrTimeLimitExceeded},
+			wantViolation: true,
+		},
+		{
+			name: "overrun with error",
+			handle: func(ctx context.Context) error {
+				<-ctx.Done()
+				return handlerErr
+			},
+			wantErr:       []error{ErrTimeLimitExceeded, handlerErr},
+			wantViolation: true,
+		},
+		{
+			name:    "failed within limit",
+			handle:  func(context.Context) error { return handlerErr },
+			wantErr: []error{handlerErr},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEventify()
+			var mutex sync.Mutex
+			violations := []Violation{}
+			e.Register(ViolationType, NewListener(func(event Event) error {
+				var v Violation
+				assert.NoError(t, json.Unmarshal(event.Payload(), &v))
+				mutex.Lock()
+				defer mutex.Unlock()
+				violations = append(violations, v)
+				return nil
+			}))
+			var got error
+			e.OnDelivery(func(report DeliveryReport) {
+				if report.Event.Type() == "job.run" {
+					got = report.Err
+				}
+			})
+			listener := NewContextListener(func(ctx context.Context, _ Event) error {
+				return tt.handle(ctx)
+			})
+			e.Register("job.run", listener, WithTimeLimit(20*time.Millisecond))
+
+			e.EmitBy("job.run", nil)
+
+			if tt.wantErr == nil {
+				assert.NoError(t, got)
+			}
+			for _, want := range tt.wantErr {
+				assert.ErrorIs(t, got, want)
+			}
+			if !tt.wantViolation {
+				time.Sleep(40 * time.Millisecond)
+				mutex.Lock()
+				defer mutex.Unlock()
+				assert.Empty(t, violations)
+				return
+			}
+			assert.Eventually(t, func() bool {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return len(violations) == 1
+			}, time.Second, time.Millisecond)
+			mutex.Lock()
+			defer mutex.Unlock()
+			assert.Equal(t, "*eventify.contextListener", violations[0].Listener)
+			assert.Equal(t, "job.run", violations[0].Event)
+			assert.Equal(t, ViolationTime, violations[0].Kind)
+			assert.Equal(t, int64(20*time.Millisecond), violations[0].Limit)
+			assert.GreaterOrEqual(t, violations[0].Used, int64(20*time.Millisecond))
+		})
+	}
+}
+
+func TestEventify_WithTimeLimitUnregister(t *testing.T) {
+	e := NewEventify()
+	listener := NewNamedListener("slow", func(Event) error { return nil })
+	e.Register("job.run", listener, WithTimeLimit(time.Millisecond))
+	_, ok := e.guards.Load(statsKey(listener))
+	assert.True(t, ok)
+
+	e.Unregister("job.run", listener)
+
+	_, ok = e.guards.Load(statsKey(listener))
+	assert.False(t, ok)
+}
+
+func TestEventify_WithTimeLimitAfterPartialUnregister(t *testing.T) {
+	e := NewEventify()
+	var got error
+	e.OnDelivery(func(report DeliveryReport) {
+		got = report.Err
+	})
+	listener := &namedContextListener{name: "job", handle: func(ctx context.Context, _ Event) error {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+		return nil
+	}}
+	e.Register("job.run", listener, WithTimeLimit(10*time.Millisecond))
+	e.Register("job.retry", listener, WithTimeLimit(10*time.Millisecond))
+
+	e.Unregister("job.retry", listener)
+	e.EmitBy("job.run", nil)
+
+	assert.ErrorIs(t, got, ErrTimeLimitExceeded, "the limit still applies to the remaining registration")
+}
@@ -0,0 +1,139 @@
+// Package httpmw provides HTTP middleware emitting an event for every request,
+// so request analytics and auditing can be built as ordinary eventify listeners.
+package httpmw
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Event types emitted by the middleware.
+const (
+	RequestStarted   = "http.request.started"
+	RequestCompleted = "http.request.completed"
+)
+
+// Request is the payload of the events emitted by the middleware.
+// Status, Bytes and Latency, in nanoseconds, are only set on RequestCompleted.
+type Request struct {
+	Method  string        `json:"method"`
+	Path    string        `json:"path"`
+	TraceID string        `json:"trace_id"`
+	Status  int           `json:"status,omitempty"`
+	Bytes   int64         `json:"bytes,omitempty"`
+	Latency time.Duration `json:"latency,omitempty"`
+}
+
+// Middleware returns middleware emitting RequestStarted before and RequestCompleted after every request,
+// also when the handler panics, with status 500 unless one was written; the panic is then propagated.
+// The trace ID is taken from the W3C traceparent header, then the X-Request-ID header, and generated otherwise;
+// the events carry it as their correlation ID.
+func Middleware(bus *eventify.Eventify) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			req := Request{Method: r.Method, Path: r.URL.Path, TraceID: traceID(r)}
+			emit(bus, RequestStarted, req)
+			rec := &recorder{ResponseWriter: w}
+			defer func() {
+				req.Status = rec.status
+				p := recover()
+				switch {
+				case p != nil && req.Status == 0:
+					req.Status = http.StatusInternalServerError
+				case req.Status == 0:
+					req.Status = http.StatusOK
+				}
+				req.Bytes = rec.bytes
+				req.Latency = time.Since(start)
+				emit(bus, RequestCompleted, req)
+				if p != nil {
+					panic(p)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+func emit(bus *eventify.Eventify, eventType string, req Request) {
+	payload, _ := json.Marshal(req)
+	bus.Emit(&requestEvent{Event: eventify.NewEvent(eventType, payload), traceID: req.TraceID})
+}
+
+// traceID returns the trace ID of the request.
+func traceID(r *http.Request) string {
+	// traceparent: version-traceid-parentid-flags
+	if parts := strings.Split(r.Header.Get("traceparent"), "-"); len(parts) == 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+	if id := r.Header.Get("X-Request-ID"); id != "" {
+		return id
+	}
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+type requestEvent struct {
+	eventify.Event
+	traceID string
+}
+
+func (e *requestEvent) CorrelationID() string {
+	return e.traceID
+}
+
+// recorder records the status and size of a response.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher for the handlers streaming their response.
+func (r *recorder) Flush() {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker for the handlers taking over the connection, such as WebSocket upgrades,
+// recorded with status 101.
+func (r *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
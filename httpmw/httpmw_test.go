@@ -0,0 +1,119 @@
+package httpmw
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/payme50rmb/eventify/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	bus := eventify.New()
+	var events []eventify.Event
+	bus.Register("http.request.*", eventify.NewListener(func(event eventify.Event) error {
+		events = append(events, event)
+		return nil
+	}))
+	handler := Middleware(bus)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, RequestStarted, events[0].Type())
+	assert.Equal(t, RequestCompleted, events[1].Type())
+	completed, err := eventify.DecodedPayload[Request](events[1])
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, completed.Method)
+	assert.Equal(t, "/orders", completed.Path)
+	assert.Equal(t, http.StatusCreated, completed.Status)
+	assert.Equal(t, int64(len("created")), completed.Bytes)
+	assert.Positive(t, completed.Latency)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", completed.TraceID)
+	assert.Equal(t, completed.TraceID, events[1].(eventify.Correlatable).CorrelationID())
+}
+
+func TestTraceID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	assert.Equal(t, "req-1", traceID(req))
+
+	generated := traceID(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Len(t, generated, 32)
+}
+
+func TestMiddleware_PanicStillCompletes(t *testing.T) {
+	bus := eventify.New()
+	var completed []eventify.Event
+	bus.Register(RequestCompleted, eventify.NewListener(func(event eventify.Event) error {
+		completed = append(completed, event)
+		return nil
+	}))
+	handler := Middleware(bus)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	require.Len(t, completed, 1)
+	request, err := eventify.DecodedPayload[Request](completed[0])
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, request.Status)
+}
+
+func TestMiddleware_WebSocketGateway(t *testing.T) {
+	bus := eventify.New()
+	completed := make(chan Request, 1)
+	bus.Register(RequestCompleted, eventify.NewListener(func(event eventify.Event) error {
+		req, err := eventify.DecodedPayload[Request](event)
+		completed <- req
+		return err
+	}))
+	server := httptest.NewServer(Middleware(bus)(gateway.New(bus)))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: " + strings.TrimPrefix(server.URL, "http://") + "\r\n" +
+		"Connection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"))
+	require.NoError(t, err)
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	conn.Close()
+
+	select {
+	case req := <-completed:
+		assert.Equal(t, http.StatusSwitchingProtocols, req.Status)
+	case <-time.After(time.Second):
+		t.Fatal("request not completed")
+	}
+}
+
+func TestMiddleware_Flusher(t *testing.T) {
+	handler := Middleware(eventify.New())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok)
+		w.Write([]byte("chunk"))
+		flusher.Flush()
+	}))
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, rec.Flushed)
+}
@@ -0,0 +1,125 @@
+package ipc
+
+import (
+	"bufio"
+	"net"
+	"sync"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Client is a connection to a Server.
+type Client struct {
+	conn  net.Conn
+	log   eventify.Log
+	guard *guard
+
+	writeMutex sync.Mutex
+
+	mutex     sync.RWMutex
+	listeners map[string][]eventify.Listener
+
+	done chan struct{}
+	err  error
+}
+
+// Dial connects to a Server listening on the Unix domain socket at the path.
+func Dial(path string, opts ...OptionFunc) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn, opts...), nil
+}
+
+// NewClient creates a Client on an established connection.
+func NewClient(conn net.Conn, opts ...OptionFunc) *Client {
+	o := newOptions(opts...)
+	c := &Client{
+		conn:      conn,
+		log:       o.log,
+		guard:     newGuard(o.signer, o.signatureWindow),
+		listeners: map[string][]eventify.Listener{},
+		done:      make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Emit emits the event on the server's bus.
+func (c *Client) Emit(event eventify.Event) error {
+	return c.write(frame{kind: kindEmit, fields: [][]byte{[]byte(event.Type()), event.Payload()}})
+}
+
+// Subscribe registers the listener for events matching the pattern on the server's bus.
+// Listeners are called sequentially from the connection's read loop.
+func (c *Client) Subscribe(pattern string, listener eventify.Listener) error {
+	c.mutex.Lock()
+	first := len(c.listeners[pattern]) == 0
+	c.listeners[pattern] = append(c.listeners[pattern], listener)
+	c.mutex.Unlock()
+	if !first {
+		return nil
+	}
+	return c.write(frame{kind: kindSubscribe, fields: [][]byte{[]byte(pattern)}})
+}
+
+// Unsubscribe removes all listeners for the pattern.
+func (c *Client) Unsubscribe(pattern string) error {
+	c.mutex.Lock()
+	delete(c.listeners, pattern)
+	c.mutex.Unlock()
+	return c.write(frame{kind: kindUnsubscribe, fields: [][]byte{[]byte(pattern)}})
+}
+
+// Done returns a channel that is closed when the connection is lost or closed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that ended the connection, once Done is closed.
+func (c *Client) Err() error {
+	<-c.done
+	return c.err
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) write(f frame) error {
+	f, err := c.guard.seal(f)
+	if err != nil {
+		return err
+	}
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return writeFrame(c.conn, f)
+}
+
+func (c *Client) readLoop() {
+	defer close(c.done)
+	r := bufio.NewReader(c.conn)
+	for {
+		f, err := readFrame(r)
+		if err != nil {
+			c.err = err
+			return
+		}
+		if f, err = c.guard.open(f); err != nil {
+			c.log.Debug("ipc frame rejected", "error", err)
+			continue
+		}
+		if f.kind != kindEvent {
+			continue
+		}
+		c.mutex.RLock()
+		listeners := c.listeners[string(f.field(0))]
+		c.mutex.RUnlock()
+		event := eventify.NewEvent(string(f.field(1)), f.field(2))
+		for _, listener := range listeners {
+			listener.Handle(event)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package ipc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrFrameTooLarge is returned when the fields of a frame exceed the maximum size.
+var ErrFrameTooLarge = errors.New("ipc: frame too large")
+
+// maxFrameSize is the maximum total size of the fields of a frame.
+const maxFrameSize = 64 * 1024 * 1024
+
+// Frame kinds.
+const (
+	kindEmit        byte = iota + 1 // client -> server: type, payload
+	kindSubscribe                   // client -> server: pattern
+	kindUnsubscribe                 // client -> server: pattern
+	kindEvent                       // server -> client: pattern, type, payload
+)
+
+// frame is the unit exchanged on the socket.
+// It is encoded as a kind byte, a field count byte, then every field as a uvarint length followed by its bytes.
+type frame struct {
+	kind   byte
+	fields [][]byte
+}
+
+func writeFrame(w io.Writer, f frame) error {
+	buf := make([]byte, 0, 2+len(f.fields)*binary.MaxVarintLen64)
+	buf = append(buf, f.kind, byte(len(f.fields)))
+	for _, field := range f.fields {
+		buf = binary.AppendUvarint(buf, uint64(len(field)))
+		buf = append(buf, field...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+func readFrame(r *bufio.Reader) (frame, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return frame{}, err
+	}
+	f := frame{kind: header[0], fields: make([][]byte, header[1])}
+	var total uint64
+	for i := range f.fields {
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return frame{}, err
+		}
+		if size > maxFrameSize-total {
+			return frame{}, fmt.Errorf("%w: over %d bytes", ErrFrameTooLarge, maxFrameSize)
+		}
+		total += size
+		// The field grows as its bytes arrive rather than being allocated at the size the peer declared.
+		var field bytes.Buffer
+		if _, err := io.CopyN(&field, r, int64(size)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return frame{}, err
+		}
+		f.fields[i] = field.Bytes()
+	}
+	return f, nil
+}
+
+func (f frame) field(i int) []byte {
+	if i >= len(f.fields) {
+		return nil
+	}
+	return f.fields[i]
+}
@@ -0,0 +1,182 @@
+package ipc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T, bus *eventify.Eventify) (*Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "eventify.sock")
+	server := NewServer(bus)
+	ready := make(chan struct{})
+	go func() {
+		close(ready)
+		server.ListenAndServe(path)
+	}()
+	<-ready
+	t.Cleanup(func() { server.Close() })
+	require.Eventually(t, func() bool {
+		c, err := Dial(path)
+		if err != nil {
+			return false
+		}
+		c.Close()
+		return true
+	}, time.Second, 5*time.Millisecond)
+	return server, path
+}
+
+func TestClient_Emit(t *testing.T) {
+	bus := eventify.New()
+	received := make(chan eventify.Event, 1)
+	bus.Register("sidecar.*", eventify.NewListener(func(event eventify.Event) error {
+		received <- event
+		return nil
+	}))
+	_, path := startServer(t, bus)
+
+	client, err := Dial(path)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Emit(eventify.NewEvent("sidecar.ready", []byte("pid 1"))))
+
+	select {
+	case event := <-received:
+		assert.Equal(t, "sidecar.ready", event.Type())
+		assert.Equal(t, []byte("pid 1"), event.Payload())
+	case <-time.After(time.Second):
+		t.Fatal("event not received by the bus")
+	}
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	bus := eventify.New()
+	server, path := startServer(t, bus)
+
+	client, err := Dial(path)
+	require.NoError(t, err)
+
+	received := make(chan eventify.Event, 10)
+	require.NoError(t, client.Subscribe("order.*", eventify.NewListener(func(event eventify.Event) error {
+		received <- event
+		return nil
+	})))
+	require.Eventually(t, func() bool {
+		bus.EmitBy("order.created", "o-1")
+		select {
+		case event := <-received:
+			return event.Type() == "order.created" && string(event.Payload()) == "o-1"
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, time.Second, time.Millisecond)
+
+	client.Close()
+	require.Eventually(t, func() bool {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		return len(server.conns) == 0
+	}, time.Second, 5*time.Millisecond, "disconnected clients should be cleaned up")
+	for len(received) > 0 {
+		<-received
+	}
+	bus.EmitBy("order.created", "o-2")
+	select {
+	case <-received:
+		t.Fatal("disconnected client should not receive events")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestFrame_RoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	in := frame{kind: kindEvent, fields: [][]byte{[]byte("a.*"), []byte("a.b"), nil}}
+
+	require.NoError(t, writeFrame(buf, in))
+	out, err := readFrame(bufio.NewReader(buf))
+
+	require.NoError(t, err)
+	assert.Equal(t, in.kind, out.kind)
+	assert.Equal(t, "a.*", string(out.field(0)))
+	assert.Equal(t, "a.b", string(out.field(1)))
+	assert.Empty(t, out.field(2))
+}
+
+func TestFrame_Truncated(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    uint64
+		wantErr error
+	}{
+		{name: "over the limit", size: maxFrameSize + 1, wantErr: ErrFrameTooLarge},
+		{name: "declared but missing", size: maxFrameSize, wantErr: io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := binary.AppendUvarint([]byte{kindEmit, 1}, tt.size)
+			buf = append(buf, "short"...)
+
+			_, err := readFrame(bufio.NewReader(bytes.NewReader(buf)))
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestServer_Module(t *testing.T) {
+	bus := eventify.New()
+	path := filepath.Join(t.TempDir(), "eventify.sock")
+	bus.Mount(NewServer(bus).Module(path))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bus.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		c, err := Dial(path)
+		if err != nil {
+			return false
+		}
+		c.Close()
+		return true
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("run not stopped")
+	}
+}
+
+func TestServer_SealedBus(t *testing.T) {
+	bus := eventify.New()
+	server, path := startServer(t, bus)
+	bus.Seal()
+
+	client, err := Dial(path)
+	require.NoError(t, err)
+	require.NoError(t, client.Subscribe("order.*", eventify.NewListener(func(eventify.Event) error { return nil })))
+	require.NoError(t, client.Subscribe("x[z-a]", eventify.NewListener(func(eventify.Event) error { return nil })))
+	require.NoError(t, client.Emit(eventify.NewEvent("order.created", nil)))
+
+	client.Close()
+	require.Eventually(t, func() bool {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		return len(server.conns) == 0
+	}, time.Second, 5*time.Millisecond, "the server should survive subscriptions refused by a sealed bus")
+	assert.Empty(t, bus.Registrations())
+}
@@ -0,0 +1,49 @@
+package ipc
+
+import (
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+type options struct {
+	log             eventify.Log
+	signer          Signer
+	signatureWindow time.Duration
+}
+
+// OptionFunc is a function that configures a Server or a Client.
+type OptionFunc func(*options)
+
+// WithLogger sets the logger.
+func WithLogger(log eventify.Log) OptionFunc {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+// WithSigner signs every outbound frame and rejects inbound frames that are not signed by the peer.
+// Both ends of a connection must use compatible signers.
+func WithSigner(signer Signer) OptionFunc {
+	return func(o *options) {
+		o.signer = signer
+	}
+}
+
+// WithSignatureWindow sets how far a signed frame's timestamp may be from the local clock, 30s by default.
+// Frames outside the window are rejected as stale, and nonces are remembered long enough to reject replays within it.
+func WithSignatureWindow(window time.Duration) OptionFunc {
+	return func(o *options) {
+		o.signatureWindow = window
+	}
+}
+
+func newOptions(opts ...OptionFunc) *options {
+	o := &options{
+		log: &eventify.NoLog{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,201 @@
+// Package ipc bridges an Eventify instance to other processes on the same host.
+//
+// A Server exposes a bus on a Unix domain socket (or any net.Listener) and Clients
+// connected to it can emit events on the bus and subscribe to patterns, with a compact
+// binary framing and no network stack involved.
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// sendBufferSize is the number of events queued per connection before new events are dropped.
+const sendBufferSize = 1024
+
+// Server exposes an Eventify instance to clients.
+type Server struct {
+	bus    *eventify.Eventify
+	log    eventify.Log
+	guard  *guard
+	nextID atomic.Uint64
+
+	mutex     sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*serverConn]struct{}
+	closed    bool
+}
+
+// NewServer creates a new Server for the bus.
+func NewServer(bus *eventify.Eventify, opts ...OptionFunc) *Server {
+	o := newOptions(opts...)
+	return &Server{
+		bus:       bus,
+		log:       o.log,
+		guard:     newGuard(o.signer, o.signatureWindow),
+		listeners: map[net.Listener]struct{}{},
+		conns:     map[*serverConn]struct{}{},
+	}
+}
+
+// ListenAndServe listens on the Unix domain socket at the path and serves clients.
+func (s *Server) ListenAndServe(path string) error {
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Module returns an eventify.Module serving clients on the Unix domain socket at the path
+// until its context is done, then closing the server.
+func (s *Server) Module(path string) eventify.Module {
+	return eventify.ModuleFunc(func(ctx context.Context) error {
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return err
+		}
+		stop := context.AfterFunc(ctx, func() { s.Close() })
+		defer stop()
+		err = s.Serve(l)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	})
+}
+
+// Serve accepts clients on the listener until the listener or the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return net.ErrClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mutex.Unlock()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.listeners, l)
+		s.mutex.Unlock()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			conn.Close()
+			return net.ErrClosed
+		}
+		c := &serverConn{
+			server:  s,
+			id:      s.nextID.Add(1),
+			conn:    conn,
+			session: s.bus.NewSession(eventify.WithSessionBuffer(sendBufferSize)),
+		}
+		s.conns[c] = struct{}{}
+		s.mutex.Unlock()
+		go c.writeLoop()
+		go c.readLoop()
+	}
+}
+
+// Close stops accepting clients and disconnects the connected ones.
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	s.closed = true
+	listeners := s.listeners
+	conns := s.conns
+	s.listeners = map[net.Listener]struct{}{}
+	s.conns = map[*serverConn]struct{}{}
+	s.mutex.Unlock()
+	for l := range listeners {
+		l.Close()
+	}
+	for c := range conns {
+		c.close()
+	}
+	return nil
+}
+
+type serverConn struct {
+	server  *Server
+	id      uint64
+	conn    net.Conn
+	session *eventify.Session
+	once    sync.Once
+}
+
+func (c *serverConn) readLoop() {
+	defer c.close()
+	r := bufio.NewReader(c.conn)
+	for {
+		f, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		if f, err = c.server.guard.open(f); err != nil {
+			c.server.log.Debug("ipc frame rejected", "error", err, "conn", c.id)
+			continue
+		}
+		switch f.kind {
+		case kindEmit:
+			c.server.bus.Emit(eventify.NewEvent(string(f.field(0)), f.field(1)))
+		case kindSubscribe:
+			if err := c.session.Subscribe(string(f.field(0))); err != nil {
+				c.server.log.Debug("ipc subscribe rejected", "error", err, "conn", c.id)
+			}
+		case kindUnsubscribe:
+			if err := c.session.Unsubscribe(string(f.field(0))); err != nil {
+				c.server.log.Debug("ipc unsubscribe rejected", "error", err, "conn", c.id)
+			}
+		default:
+			c.server.log.Debug("ipc unknown frame", "kind", f.kind, "conn", c.id)
+		}
+	}
+}
+
+func (c *serverConn) writeLoop() {
+	w := bufio.NewWriter(c.conn)
+	for {
+		select {
+		case e := <-c.session.Events():
+			f, err := c.server.guard.seal(frame{kind: kindEvent, fields: [][]byte{[]byte(e.Pattern), []byte(e.Event.Type()), e.Event.Payload()}})
+			if err != nil {
+				c.server.log.Debug("ipc frame signing failed", "error", err, "conn", c.id)
+				continue
+			}
+			if err := writeFrame(w, f); err != nil {
+				c.close()
+				return
+			}
+			if len(c.session.Events()) == 0 {
+				if err := w.Flush(); err != nil {
+					c.close()
+					return
+				}
+			}
+		case <-c.session.Done():
+			return
+		}
+	}
+}
+
+func (c *serverConn) close() {
+	c.once.Do(func() {
+		c.session.Close()
+		c.conn.Close()
+		c.server.mutex.Lock()
+		delete(c.server.conns, c)
+		c.server.mutex.Unlock()
+	})
+}
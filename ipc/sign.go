@@ -0,0 +1,171 @@
+package ipc
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrBadSignature is returned when a frame signature does not verify.
+	ErrBadSignature = errors.New("ipc: bad signature")
+	// ErrStaleFrame is returned when a frame timestamp is outside the accepted window.
+	ErrStaleFrame = errors.New("ipc: stale frame")
+	// ErrReplayedFrame is returned when a frame nonce has already been seen.
+	ErrReplayedFrame = errors.New("ipc: replayed frame")
+)
+
+// defaultSignatureWindow is the default maximum clock difference accepted for signed frames.
+const defaultSignatureWindow = 30 * time.Second
+
+// nonceSize is the size of the random nonce of a signed frame.
+const nonceSize = 16
+
+// Signer is an interface that signs outbound frames and verifies inbound ones.
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
+	Verify(message, signature []byte) error
+}
+
+// NewHMACSigner creates a signer using HMAC-SHA256 with a key shared by both ends.
+func NewHMACSigner(key []byte) Signer {
+	return &hmacSigner{key: key}
+}
+
+type hmacSigner struct {
+	key []byte
+}
+
+func (s *hmacSigner) Sign(message []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
+
+func (s *hmacSigner) Verify(message, signature []byte) error {
+	expected, _ := s.Sign(message)
+	if !hmac.Equal(expected, signature) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// NewEd25519Signer creates a signer that signs with the private key and accepts
+// frames signed by any of the peer public keys.
+func NewEd25519Signer(key ed25519.PrivateKey, peers ...ed25519.PublicKey) Signer {
+	return &ed25519Signer{key: key, peers: peers}
+}
+
+type ed25519Signer struct {
+	key   ed25519.PrivateKey
+	peers []ed25519.PublicKey
+}
+
+func (s *ed25519Signer) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, message), nil
+}
+
+func (s *ed25519Signer) Verify(message, signature []byte) error {
+	for _, peer := range s.peers {
+		if ed25519.Verify(peer, message, signature) {
+			return nil
+		}
+	}
+	return ErrBadSignature
+}
+
+// guard seals outbound frames with a timestamp, a nonce and a signature,
+// and opens inbound frames rejecting forged, stale and replayed ones.
+type guard struct {
+	signer Signer
+	window time.Duration
+
+	mutex  sync.Mutex
+	nonces map[string]time.Time
+	pruned time.Time
+}
+
+func newGuard(signer Signer, window time.Duration) *guard {
+	if signer == nil {
+		return nil
+	}
+	if window <= 0 {
+		window = defaultSignatureWindow
+	}
+	return &guard{signer: signer, window: window, nonces: map[string]time.Time{}}
+}
+
+func signedBytes(f frame) []byte {
+	buf := &bytes.Buffer{}
+	writeFrame(buf, f)
+	return buf.Bytes()
+}
+
+// seal appends the timestamp, nonce and signature fields to the frame.
+func (g *guard) seal(f frame) (frame, error) {
+	if g == nil {
+		return f, nil
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return frame{}, err
+	}
+	ts := binary.BigEndian.AppendUint64(nil, uint64(time.Now().UnixNano()))
+	sealed := frame{kind: f.kind, fields: append(append([][]byte{}, f.fields...), ts, nonce)}
+	signature, err := g.signer.Sign(signedBytes(sealed))
+	if err != nil {
+		return frame{}, err
+	}
+	sealed.fields = append(sealed.fields, signature)
+	return sealed, nil
+}
+
+// open verifies and strips the timestamp, nonce and signature fields of the frame.
+func (g *guard) open(f frame) (frame, error) {
+	if g == nil {
+		return f, nil
+	}
+	n := len(f.fields)
+	if n < 3 || len(f.fields[n-3]) != 8 {
+		return frame{}, ErrBadSignature
+	}
+	signed := frame{kind: f.kind, fields: f.fields[:n-1]}
+	if err := g.signer.Verify(signedBytes(signed), f.fields[n-1]); err != nil {
+		return frame{}, err
+	}
+	ts := time.Unix(0, int64(binary.BigEndian.Uint64(f.fields[n-3])))
+	now := time.Now()
+	if ts.Before(now.Add(-g.window)) || ts.After(now.Add(g.window)) {
+		return frame{}, ErrStaleFrame
+	}
+	if !g.remember(string(f.fields[n-2]), now) {
+		return frame{}, ErrReplayedFrame
+	}
+	return frame{kind: f.kind, fields: f.fields[:n-3]}, nil
+}
+
+// remember records the nonce and reports whether it was new.
+// Nonces are kept for twice the window, after which the timestamp check rejects their frames anyway.
+func (g *guard) remember(nonce string, now time.Time) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if now.Sub(g.pruned) > g.window {
+		for n, seen := range g.nonces {
+			if now.Sub(seen) > 2*g.window {
+				delete(g.nonces, n)
+			}
+		}
+		g.pruned = now
+	}
+	if _, ok := g.nonces[nonce]; ok {
+		return false
+	}
+	g.nonces[nonce] = now
+	return true
+}
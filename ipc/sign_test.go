@@ -0,0 +1,106 @@
+package ipc
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard(t *testing.T) {
+	g := newGuard(NewHMACSigner([]byte("secret")), time.Minute)
+	in := frame{kind: kindEmit, fields: [][]byte{[]byte("order.created"), []byte("{}")}}
+
+	sealed, err := g.seal(in)
+	require.NoError(t, err)
+
+	t.Run("opens sealed frames", func(t *testing.T) {
+		out, err := g.open(sealed)
+		require.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	t.Run("rejects replays", func(t *testing.T) {
+		_, err := g.open(sealed)
+		assert.ErrorIs(t, err, ErrReplayedFrame)
+	})
+
+	t.Run("rejects tampered frames", func(t *testing.T) {
+		tampered, _ := g.seal(in)
+		tampered.fields[0] = []byte("order.deleted")
+		_, err := g.open(tampered)
+		assert.ErrorIs(t, err, ErrBadSignature)
+	})
+
+	t.Run("rejects other keys", func(t *testing.T) {
+		other, _ := newGuard(NewHMACSigner([]byte("other")), time.Minute).seal(in)
+		_, err := g.open(other)
+		assert.ErrorIs(t, err, ErrBadSignature)
+	})
+
+	t.Run("rejects stale frames", func(t *testing.T) {
+		stale := frame{kind: in.kind, fields: append(append([][]byte{}, in.fields...),
+			binary.BigEndian.AppendUint64(nil, uint64(time.Now().Add(-time.Hour).UnixNano())),
+			make([]byte, nonceSize),
+		)}
+		signature, _ := g.signer.Sign(signedBytes(stale))
+		stale.fields = append(stale.fields, signature)
+		_, err := g.open(stale)
+		assert.ErrorIs(t, err, ErrStaleFrame)
+	})
+
+	t.Run("rejects unsigned frames", func(t *testing.T) {
+		_, err := g.open(in)
+		assert.ErrorIs(t, err, ErrBadSignature)
+	})
+}
+
+func TestSignedConnection(t *testing.T) {
+	serverPub, serverKey, _ := ed25519.GenerateKey(nil)
+	clientPub, clientKey, _ := ed25519.GenerateKey(nil)
+
+	bus := eventify.New()
+	received := make(chan eventify.Event, 1)
+	bus.Register("signed.*", eventify.NewListener(func(event eventify.Event) error {
+		received <- event
+		return nil
+	}))
+
+	path := filepath.Join(t.TempDir(), "eventify.sock")
+	server := NewServer(bus, WithSigner(NewEd25519Signer(serverKey, clientPub)))
+	go server.ListenAndServe(path)
+	defer server.Close()
+
+	var client *Client
+	require.Eventually(t, func() bool {
+		var err error
+		client, err = Dial(path, WithSigner(NewEd25519Signer(clientKey, serverPub)))
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	defer client.Close()
+
+	_, intruderKey, _ := ed25519.GenerateKey(nil)
+	intruder, err := Dial(path, WithSigner(NewEd25519Signer(intruderKey, serverPub)))
+	require.NoError(t, err)
+	defer intruder.Close()
+
+	require.NoError(t, intruder.Emit(eventify.NewEvent("signed.forged", nil)))
+	require.NoError(t, client.Emit(eventify.NewEvent("signed.genuine", nil)))
+
+	select {
+	case event := <-received:
+		assert.Equal(t, "signed.genuine", event.Type())
+	case <-time.After(time.Second):
+		t.Fatal("signed event not received")
+	}
+	select {
+	case event := <-received:
+		t.Fatalf("forged event %s should be rejected", event.Type())
+	case <-time.After(20 * time.Millisecond):
+	}
+}
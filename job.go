@@ -0,0 +1,114 @@
+package eventify
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Event types emitted by jobs.
+const (
+	JobStarted   = "job.started"
+	JobProgress  = "job.progress"
+	JobCompleted = "job.completed"
+	JobFailed    = "job.failed"
+)
+
+// JobStatus is the payload of the events emitted by a job.
+type JobStatus struct {
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Percent float64        `json:"percent"`
+	Message string         `json:"message,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Meta    map[string]any `json:"meta,omitempty"`
+	Time    time.Time      `json:"time"`
+}
+
+// Job reports the progress of a long-running job with standardized events,
+// so UIs and monitors get consistent job telemetry.
+// Job events carry the job ID as their correlation ID and are emitted in order,
+// so synchronous listeners of job events must not call the job's methods.
+type Job struct {
+	eventify *Eventify
+	mutex    sync.Mutex
+	status   JobStatus
+	done     bool
+}
+
+// StartJob starts a job with the name and metadata and emits JobStarted.
+func (e *Eventify) StartJob(name string, meta map[string]any) *Job {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	job := &Job{
+		eventify: e,
+		status:   JobStatus{ID: hex.EncodeToString(id), Name: name, Meta: meta},
+	}
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	job._Emit(JobStarted)
+	return job
+}
+
+// ID returns the unique ID of the job.
+func (j *Job) ID() string {
+	return j.status.ID
+}
+
+// Progress emits JobProgress with the percent complete, between 0 and 100, and an optional message.
+func (j *Job) Progress(percent float64, message string) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.done {
+		return
+	}
+	j.status.Percent = min(max(percent, 0), 100)
+	j.status.Message = message
+	j._Emit(JobProgress)
+}
+
+// Complete emits JobCompleted. Calls after the job is completed or failed have no effect.
+func (j *Job) Complete() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.done {
+		return
+	}
+	j.done = true
+	j.status.Percent = 100
+	j.status.Message = ""
+	j._Emit(JobCompleted)
+}
+
+// Fail emits JobFailed with the error. Calls after the job is completed or failed have no effect.
+func (j *Job) Fail(err error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.done {
+		return
+	}
+	j.done = true
+	j.status.Message = ""
+	if err != nil {
+		j.status.Error = err.Error()
+	}
+	j._Emit(JobFailed)
+}
+
+// _Emit emits the event type with the current status. The caller must hold the lock.
+func (j *Job) _Emit(eventType string) {
+	j.status.Time = time.Now()
+	payload, _ := json.Marshal(j.status)
+	j.eventify.Emit(&jobEvent{event: event{eventType: eventType, payload: payload}, jobID: j.status.ID})
+}
+
+type jobEvent struct {
+	event
+	jobID string
+}
+
+func (e *jobEvent) CorrelationID() string {
+	return e.jobID
+}
@@ -0,0 +1,54 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_StartJob(t *testing.T) {
+	e := New()
+	var statuses []JobStatus
+	var types []string
+	e.Register("job.*", NewListener(func(event Event) error {
+		status, err := DecodedPayload[JobStatus](event)
+		require.NoError(t, err)
+		assert.Equal(t, status.ID, event.(Correlatable).CorrelationID())
+		types = append(types, event.Type())
+		statuses = append(statuses, status)
+		return nil
+	}))
+
+	job := e.StartJob("import", map[string]any{"file": "users.csv"})
+	job.Progress(40, "400/1000 rows")
+	job.Progress(140, "")
+	job.Complete()
+	job.Fail(assert.AnError)
+
+	assert.Equal(t, []string{JobStarted, JobProgress, JobProgress, JobCompleted}, types)
+	require.Len(t, statuses, 4)
+	assert.Equal(t, job.ID(), statuses[0].ID)
+	assert.Equal(t, "import", statuses[0].Name)
+	assert.Equal(t, "users.csv", statuses[0].Meta["file"])
+	assert.Equal(t, 40.0, statuses[1].Percent)
+	assert.Equal(t, "400/1000 rows", statuses[1].Message)
+	assert.Equal(t, 100.0, statuses[2].Percent)
+	assert.Equal(t, 100.0, statuses[3].Percent)
+}
+
+func TestJob_Fail(t *testing.T) {
+	e := New()
+	var failed JobStatus
+	e.Register(JobFailed, NewListener(func(event Event) error {
+		var err error
+		failed, err = DecodedPayload[JobStatus](event)
+		return err
+	}))
+
+	job := e.StartJob("export", nil)
+	job.Fail(assert.AnError)
+
+	assert.Equal(t, job.ID(), failed.ID)
+	assert.Equal(t, assert.AnError.Error(), failed.Error)
+}
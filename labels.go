@@ -0,0 +1,150 @@
+package eventify
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// RegisterOption configures a registration made with Register.
+type RegisterOption func(*registration)
+
+type registration struct {
+	labels     []string
+	expiry     time.Duration
+	owner      context.Context
+	timeLimit  time.Duration
+	allocLimit int64
+	shadow     bool
+	compare    bool
+}
+
+func newRegistration(opts []RegisterOption) *registration {
+	r := &registration{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// WithLabels attaches labels, such as "team=payments", to the listener for bulk operations
+// with UnregisterByLabel, PauseByLabel and StatsByLabel.
+// Labels are kept per listener, so only comparable listeners can be labeled.
+func WithLabels(labels ...string) RegisterOption {
+	return func(r *registration) {
+		r.labels = append(r.labels, labels...)
+	}
+}
+
+// UnregisterByLabel removes the listeners with the label from every event type pattern
+// and returns the number of registrations removed.
+// This method is thread-safe.
+func (e *Eventify) UnregisterByLabel(label string) int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e._CheckSealed()
+	count := 0
+	removed := []Listener{}
+	e.listeners.Range(func(key, value any) bool {
+		kept := []Listener{}
+		for _, listener := range value.([]Listener) {
+			if e._HasLabel(listener, label) {
+				removed = append(removed, listener)
+				count++
+			} else {
+				kept = append(kept, listener)
+			}
+		}
+		if len(kept) != len(value.([]Listener)) {
+			e.listeners.Store(key, kept)
+			delete(e.growth, key.(string))
+		}
+		return true
+	})
+	e._Forget(removed)
+	e.log.Debug("eventify unregister by label", "label", label, "count", count)
+	return count
+}
+
+// PauseByLabel stops delivering events to the listeners with the label until ResumeByLabel.
+// Events emitted while a listener is paused are not delivered to it later.
+// This method is thread-safe.
+func (e *Eventify) PauseByLabel(label string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.paused[label] = true
+	e.matches.Clear()
+	e.log.Debug("eventify pause by label", "label", label)
+}
+
+// ResumeByLabel resumes delivering events to the listeners with the label.
+// This method is thread-safe.
+func (e *Eventify) ResumeByLabel(label string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	delete(e.paused, label)
+	e.matches.Clear()
+	e.log.Debug("eventify resume by label", "label", label)
+}
+
+// StatsByLabel returns the invocation statistics of the listeners with the label, sorted by listener.
+func (e *Eventify) StatsByLabel(label string) []ListenerStats {
+	stats := []ListenerStats{}
+	for _, s := range e.Stats() {
+		if slices.Contains(s.Labels, label) {
+			stats = append(stats, s)
+		}
+	}
+	return stats
+}
+
+// _Label records the labels of the registration of the listener. The caller must hold the write lock.
+func (e *Eventify) _Label(listener Listener, r *registration) {
+	key := statsKey(listener)
+	if key == nil || len(r.labels) == 0 {
+		return
+	}
+	for _, label := range r.labels {
+		if !slices.Contains(e.labels[key], label) {
+			e.labels[key] = append(e.labels[key], label)
+		}
+	}
+}
+
+// _HasLabel reports whether the listener has the label. The caller must hold the lock.
+func (e *Eventify) _HasLabel(listener Listener, label string) bool {
+	key := statsKey(listener)
+	return key != nil && slices.Contains(e.labels[key], label)
+}
+
+// _Paused reports whether the listener has a paused label. The caller must hold the lock.
+func (e *Eventify) _Paused(listener Listener) bool {
+	if len(e.paused) == 0 {
+		return false
+	}
+	key := statsKey(listener)
+	if key == nil {
+		return false
+	}
+	for _, label := range e.labels[key] {
+		if e.paused[label] {
+			return true
+		}
+	}
+	return false
+}
+
+// _Forget drops the statistics, labels, limits and shadow marks of the removed listeners that are not registered
+// for another pattern, and closes them. The caller must hold the write lock.
+func (e *Eventify) _Forget(listeners []Listener) {
+	unregistered := slices.DeleteFunc(slices.Clone(listeners), e._Registered)
+	e._ForgetStats(unregistered)
+	e._Close(listeners)
+	for _, listener := range unregistered {
+		delete(e.labels, statsKey(listener))
+		if key := statsKey(listener); key != nil {
+			e.guards.Delete(key)
+			e.shadows.Delete(key)
+		}
+	}
+}
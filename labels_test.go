@@ -0,0 +1,82 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_Labels(t *testing.T) {
+	e := New()
+	var received []string
+	record := func(name string) Listener {
+		return NewNamedListener(name, func(event Event) error {
+			received = append(received, name)
+			return nil
+		})
+	}
+	e.Register("order.*", record("charge"), WithLabels("team=payments"))
+	e.Register("order.refunded", record("refund"), WithLabels("team=payments", "tier=critical"))
+	e.Register("order.*", record("email"), WithLabels("team=growth"))
+
+	e.PauseByLabel("team=payments")
+	e.EmitBy("order.refunded", nil)
+	assert.Equal(t, []string{"email"}, received)
+
+	e.ResumeByLabel("team=payments")
+	received = nil
+	e.EmitBy("order.refunded", nil)
+	assert.ElementsMatch(t, []string{"charge", "refund", "email"}, received)
+
+	stats := e.StatsByLabel("team=payments")
+	require.Len(t, stats, 2)
+	assert.Equal(t, "charge", stats[0].Listener)
+	assert.Equal(t, []string{"team=payments", "tier=critical"}, stats[1].Labels)
+
+	assert.Equal(t, 2, e.UnregisterByLabel("team=payments"))
+	received = nil
+	e.EmitBy("order.refunded", nil)
+	assert.Equal(t, []string{"email"}, received)
+	assert.Empty(t, e.StatsByLabel("team=payments"))
+}
+
+func TestEventify_ReplaceKeepsLabels(t *testing.T) {
+	e := New()
+	e.Register("order.*", NewNamedListener("charge", nil), WithLabels("team=payments"))
+	replaced := false
+	e.Replace("order.*", "charge", NewNamedListener("charge", func(Event) error {
+		replaced = true
+		return nil
+	}))
+
+	e.PauseByLabel("team=payments")
+	e.EmitBy("order.created", nil)
+
+	assert.False(t, replaced)
+}
+
+func TestEventify_PartialUnregisterKeepsLabelsAndStats(t *testing.T) {
+	e := New()
+	calls := 0
+	listener := NewNamedListener("audit", func(Event) error {
+		calls++
+		return nil
+	})
+	e.Register("order.*", listener, WithLabels("team=audit"))
+	e.Register("user.*", listener, WithLabels("team=audit"))
+	e.EmitBy("order.created", nil)
+
+	e.Unregister("order.*", listener)
+	require.Len(t, e.Stats(), 1, "the listener is still registered for user.*")
+	assert.Equal(t, uint64(1), e.Stats()[0].Invocations)
+
+	e.PauseByLabel("team=audit")
+	e.EmitBy("user.created", nil)
+	assert.Equal(t, 1, calls, "the label still pauses the listener")
+
+	e.ResumeByLabel("team=audit")
+	e.Unregister("user.*", listener)
+	assert.Empty(t, e.Stats())
+	assert.Empty(t, e.StatsByLabel("team=audit"))
+}
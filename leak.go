@@ -0,0 +1,80 @@
+package eventify
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+)
+
+// leakGrowth is the number of registrations on a pattern without any removal
+// after which the pattern is reported as a leak suspect.
+const leakGrowth = 64
+
+// LeakSuspect is a pattern whose listener count keeps growing.
+type LeakSuspect struct {
+	Pattern string
+	// Listeners is the number of listeners registered for the pattern.
+	Listeners int
+	// Growth is the number of registrations since a listener was last removed from the pattern.
+	Growth int
+	// CallSites are the number of those registrations per "file:line" they were made from.
+	CallSites map[string]int
+}
+
+// LeakReport returns the patterns that had at least 64 listeners registered without any being removed,
+// most grown first. They usually come from code registering listeners per request or per instance
+// without unregistering them; the call sites point at it.
+func (e *Eventify) LeakReport() []LeakSuspect {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	suspects := []LeakSuspect{}
+	for pattern, growth := range e.growth {
+		if growth.registrations < leakGrowth {
+			continue
+		}
+		ls, _ := e.listeners.Load(pattern)
+		suspect := LeakSuspect{
+			Pattern:   pattern,
+			Listeners: len(ls.([]Listener)),
+			Growth:    growth.registrations,
+			CallSites: map[string]int{},
+		}
+		for site, count := range growth.sites {
+			suspect.CallSites[site] = count
+		}
+		suspects = append(suspects, suspect)
+	}
+	sort.Slice(suspects, func(i, j int) bool {
+		if suspects[i].Growth != suspects[j].Growth {
+			return suspects[i].Growth > suspects[j].Growth
+		}
+		return suspects[i].Pattern < suspects[j].Pattern
+	})
+	return suspects
+}
+
+// patternGrowth counts the registrations on a pattern since a listener was last removed from it.
+type patternGrowth struct {
+	registrations int
+	sites         map[string]int
+}
+
+// _Grown records a registration on the pattern. The caller must hold the write lock.
+func (e *Eventify) _Grown(pattern string, site string) {
+	growth, ok := e.growth[pattern]
+	if !ok {
+		growth = &patternGrowth{sites: map[string]int{}}
+		e.growth[pattern] = growth
+	}
+	growth.registrations++
+	growth.sites[site]++
+}
+
+// callSite returns the "file:line" of the caller skip frames up the stack.
+func callSite(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
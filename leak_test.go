@@ -0,0 +1,38 @@
+package eventify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_LeakReport(t *testing.T) {
+	e := New()
+	handleRequest := func() {
+		e.Register("session.*", NewListener(nil))
+	}
+	for i := 0; i < leakGrowth; i++ {
+		handleRequest()
+	}
+	for i := 0; i < leakGrowth; i++ {
+		e.Register("user.*", NewNamedListener("temp", nil))
+		e.Unregister("user.*", NewNamedListener("temp", nil))
+	}
+
+	report := e.LeakReport()
+
+	require.Len(t, report, 1)
+	assert.Equal(t, "session.*", report[0].Pattern)
+	assert.Equal(t, leakGrowth, report[0].Listeners)
+	assert.Equal(t, leakGrowth, report[0].Growth)
+	require.Len(t, report[0].CallSites, 1)
+	for site, count := range report[0].CallSites {
+		assert.True(t, strings.Contains(site, "leak_test.go:"), site)
+		assert.Equal(t, leakGrowth, count)
+	}
+
+	e.Unregister("session.*")
+	assert.Empty(t, e.LeakReport())
+}
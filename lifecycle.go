@@ -0,0 +1,144 @@
+package eventify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrListenerInit is returned, wrapping the cause, for the deliveries to a listener whose Init failed.
+var ErrListenerInit = errors.New("eventify: listener init")
+
+// Initializable is an interface that can be implemented by listeners holding resources, such as
+// connections or caches, to initialize them before their first event.
+// Init receives a context carrying the resources provided to the bus.
+// It is called lazily before the first delivery, or eagerly by WarmUp and Run, and again before
+// the next delivery as long as it fails. Deliveries fail with ErrListenerInit while it does.
+// Only comparable listeners are initialized.
+type Initializable interface {
+	Init(ctx context.Context) error
+}
+
+// Closable is an interface that can be implemented by listeners to release their resources
+// once they are no longer registered for any pattern. Close is called asynchronously,
+// after the deliveries under way have finished. A listener registered again is initialized again.
+// Only comparable listeners are closed.
+type Closable interface {
+	Close(ctx context.Context) error
+}
+
+// listenerInit is the initialization state of a listener.
+type listenerInit struct {
+	mutex sync.Mutex
+	done  bool
+}
+
+// WarmUp initializes the registered listeners implementing Initializable that are not initialized yet,
+// so their first events don't pay for it. It returns the joined errors of the failed ones,
+// which are initialized again before their next delivery.
+// This method is thread-safe.
+func (e *Eventify) WarmUp(ctx context.Context) error {
+	var errs []error
+	for _, listener := range e._Comparable() {
+		errs = append(errs, e._Init(e.ResourceContext(ctx), listener))
+	}
+	return errors.Join(errs...)
+}
+
+// _CloseAll closes the registered listeners implementing Closable, once the deliveries under way have
+// finished, and returns the joined errors of the failed ones. The listeners stay registered.
+func (e *Eventify) _CloseAll(ctx context.Context) error {
+	if err := e.Drain(ctx); err != nil {
+		return err
+	}
+	var errs []error
+	for _, listener := range e._Comparable() {
+		closable, ok := listener.(Closable)
+		if !ok {
+			continue
+		}
+		if err := closable.Close(ctx); err != nil {
+			withEventFields(e.log, nil, listener).Debug("eventify listener close failed", "error", err)
+			errs = append(errs, err)
+		}
+		e.inits.Delete(statsKey(listener))
+	}
+	return errors.Join(errs...)
+}
+
+// _Comparable returns the registered comparable listeners, once each.
+func (e *Eventify) _Comparable() []Listener {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	listeners := []Listener{}
+	seen := map[any]bool{}
+	e.listeners.Range(func(_, value any) bool {
+		for _, listener := range value.([]Listener) {
+			if key := statsKey(listener); key != nil && !seen[key] {
+				seen[key] = true
+				listeners = append(listeners, listener)
+			}
+		}
+		return true
+	})
+	return listeners
+}
+
+// _Init initializes the listener if it implements Initializable and isn't initialized yet.
+func (e *Eventify) _Init(ctx context.Context, listener Listener) error {
+	initializable, ok := listener.(Initializable)
+	if !ok {
+		return nil
+	}
+	key := statsKey(listener)
+	if key == nil {
+		return nil
+	}
+	state, _ := e.inits.LoadOrStore(key, &listenerInit{})
+	init := state.(*listenerInit)
+	init.mutex.Lock()
+	defer init.mutex.Unlock()
+	if init.done {
+		return nil
+	}
+	if err := initializable.Init(ctx); err != nil {
+		withEventFields(e.log, nil, listener).Debug("eventify listener init failed", "error", err)
+		return fmt.Errorf("%w: %w", ErrListenerInit, err)
+	}
+	init.done = true
+	return nil
+}
+
+// _Close closes the removed listeners implementing Closable that are no longer registered for any pattern,
+// once their deliveries under way have finished. A listener is closed once even if it was removed several
+// times. The caller must hold the write lock.
+func (e *Eventify) _Close(listeners []Listener) {
+	for _, listener := range listeners {
+		closable, ok := listener.(Closable)
+		key := statsKey(listener)
+		if !ok || key == nil || e._Registered(listener) {
+			continue
+		}
+		if _, closing := e.closing.LoadOrStore(key, true); closing {
+			continue
+		}
+		// The close isn't scheduled until the deliveries are done, so it never blocks the scheduler.
+		e.inflight.OnIdle(listener, func() {
+			e.scheduler.Go(func() {
+				defer e.closing.Delete(key)
+				// The listener may have been registered again since it was removed.
+				e.mutex.RLock()
+				registered := e._Registered(listener)
+				e.mutex.RUnlock()
+				if registered {
+					return
+				}
+				if err := closable.Close(context.Background()); err != nil {
+					withEventFields(e.log, nil, listener).Debug("eventify listener close failed", "error", err)
+				}
+				e.inits.Delete(key)
+			})
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lifecycleListener struct {
+	initErr error
+	inits   int
+	closes  int
+	handled int
+}
+
+func (l *lifecycleListener) Name() string { return "lifecycle" }
+
+func (l *lifecycleListener) Init(context.Context) error {
+	l.inits++
+	return l.initErr
+}
+
+func (l *lifecycleListener) Close(context.Context) error {
+	l.closes++
+	return nil
+}
+
+func (l *lifecycleListener) Handle(Event) error {
+	l.handled++
+	return nil
+}
+
+func TestEventify_ListenerLifecycle(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	listener := &lifecycleListener{}
+	e.Register("order.created", listener)
+	e.Register("order.paid", listener)
+	assert.Equal(t, 0, listener.inits)
+
+	e.EmitBy("order.created", nil)
+	e.EmitBy("order.paid", nil)
+	assert.Equal(t, 1, listener.inits)
+	assert.Equal(t, 2, listener.handled)
+
+	e.Unregister("order.created", listener)
+	sim.Run()
+	assert.Equal(t, 0, listener.closes)
+
+	e.Unregister("order.paid", listener)
+	sim.Run()
+	assert.Equal(t, 1, listener.closes)
+
+	e.Register("order.created", listener)
+	e.EmitBy("order.created", nil)
+	assert.Equal(t, 2, listener.inits)
+
+	e.Unregister("order.created", listener)
+	e.Register("order.created", listener)
+	sim.Run()
+	assert.Equal(t, 1, listener.closes, "registered again before the close ran")
+}
+
+func TestEventify_ListenerInitFailure(t *testing.T) {
+	e := NewEventify()
+	listener := &lifecycleListener{initErr: assert.AnError}
+	e.Register("order.created", listener)
+
+	err := e.WarmUp(context.Background())
+	assert.ErrorIs(t, err, ErrListenerInit)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	errChan := make(chan error, 1)
+	e.Register("error.event", listener)
+	e.Emit(&mockErrorEvent{errChan: errChan})
+	assert.ErrorIs(t, <-errChan, ErrListenerInit)
+	assert.Equal(t, 0, listener.handled)
+
+	listener.initErr = nil
+	assert.NoError(t, e.WarmUp(context.Background()))
+	assert.NoError(t, e.WarmUp(context.Background()))
+	assert.Equal(t, 3, listener.inits)
+
+	e.EmitBy("order.created", nil)
+	assert.Equal(t, 1, listener.handled)
+}
+
+func TestEventify_RunWarmUpFailure(t *testing.T) {
+	e := NewEventify()
+	e.Register("order.created", &lifecycleListener{initErr: assert.AnError})
+	started := false
+	e.Mount(ModuleFunc(func(context.Context) error {
+		started = true
+		return nil
+	}))
+
+	err := e.Run(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, started)
+}
+
+func TestEventify_RunClosesListeners(t *testing.T) {
+	e := NewEventify()
+	listener := &lifecycleListener{}
+	e.Register("order.created", listener)
+	ctx, cancel := context.WithCancel(context.Background())
+	e.Mount(ModuleFunc(func(context.Context) error {
+		e.EmitBy("order.created", nil)
+		cancel()
+		return nil
+	}))
+
+	assert.NoError(t, e.Run(ctx))
+	assert.Equal(t, 1, listener.handled)
+	assert.Equal(t, 1, listener.closes)
+}
+
+type asyncLifecycleListener struct {
+	IAmAsync
+	*lifecycleListener
+}
+
+func TestEventify_ListenerClosedOnce(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	listener := &lifecycleListener{}
+	e.Register("order.created", listener)
+	e.Register("order.created", listener)
+	e.Register("order.paid", listener)
+
+	e.Unregister("order.created", listener)
+	e.Unregister("order.paid", listener)
+	e.Register("order.paid", listener)
+	e.Unregister("order.paid", listener)
+	sim.Run()
+	assert.Equal(t, 1, listener.closes)
+}
+
+func TestEventify_ListenerClosedAfterAsyncDelivery(t *testing.T) {
+	for seed := range uint64(10) {
+		sim := NewSimulation(seed)
+		e := NewEventify(WithScheduler(sim))
+		listener := &asyncLifecycleListener{lifecycleListener: &lifecycleListener{}}
+		e.Register("order.created", listener)
+
+		e.EmitBy("order.created", nil)
+		e.Unregister("order.created", listener)
+		sim.Run()
+		assert.Equal(t, 1, listener.handled, "seed %d", seed)
+		assert.Equal(t, 1, listener.closes, "seed %d", seed)
+	}
+}
@@ -1,5 +1,10 @@
 package eventify
 
+import (
+	"context"
+	"errors"
+)
+
 // Namable is an interface that can be used to name a listener
 // Only listeners that implement this interface will be unregistered
 type Namable interface {
@@ -52,3 +57,126 @@ func (l *namedListener) Name() string {
 func (l *namedListener) Handle(event Event) error {
 	return l.handle(event)
 }
+
+// NewAdaptedListener creates a listener that transforms every event before passing it to the inner listener,
+// so handlers expecting an old payload shape keep working while producers move to a new one.
+// If the transform returns an error, it is returned instead; if it returns a nil event, the event is skipped.
+// A nil transform passes the events through unchanged.
+// The adapted listener keeps the name, async and validator markers of the inner listener, passes it the context
+// of the bus, and initializes and closes it with itself.
+func NewAdaptedListener(transform func(Event) (Event, error), inner Listener) Listener {
+	if transform == nil {
+		transform = func(event Event) (Event, error) { return event, nil }
+	}
+	return wrap(&wrapper{inner: inner, initInner: true, handle: func(ctx context.Context, event Event) error {
+		adapted, err := transform(event)
+		if err != nil || adapted == nil {
+			return err
+		}
+		return handleCtx(ctx, inner, adapted)
+	}})
+}
+
+// handleCtx calls the listener, with the context if it is a ContextListener.
+func handleCtx(ctx context.Context, listener Listener, event Event) error {
+	if contextListener, ok := listener.(ContextListener); ok {
+		return contextListener.HandleCtx(ctx, event)
+	}
+	return listener.Handle(event)
+}
+
+// wrapListener creates a listener that handles events with the handle function
+// and keeps the name, async and validator markers of the inner listener. Closing it closes the inner listener.
+func wrapListener(inner Listener, handle func(Event) error) Listener {
+	return wrap(&wrapper{inner: inner, handle: func(_ context.Context, event Event) error {
+		return handle(event)
+	}})
+}
+
+// wrap returns the wrapper as a listener with the name, async and validator markers of its inner listener.
+// Validators run synchronously, so the validator marker takes precedence over the async one.
+func wrap(w *wrapper) Listener {
+	namable, named := w.inner.(Namable)
+	_, async := w.inner.(IsAsync)
+	_, validator := w.inner.(IsValidator)
+	switch {
+	case named && validator:
+		return &namedValidatorWrapper{namedWrapper: namedWrapper{wrapper: w, name: namable.Name()}}
+	case validator:
+		return &validatorWrapper{wrapper: w}
+	case named && async:
+		return &namedAsyncWrapper{namedWrapper: namedWrapper{wrapper: w, name: namable.Name()}}
+	case named:
+		return &namedWrapper{wrapper: w, name: namable.Name()}
+	case async:
+		return &asyncWrapper{wrapper: w}
+	default:
+		return w
+	}
+}
+
+// wrapper is a listener handling events on behalf of an inner listener.
+type wrapper struct {
+	inner  Listener
+	handle func(ctx context.Context, event Event) error
+	// initInner is set when the wrapper calls the inner listener itself, rather than through the bus
+	// which initializes it, so Init initializes it.
+	initInner bool
+	// close, if set, releases the resources of the wrapper before the inner listener is closed.
+	close func(ctx context.Context) error
+}
+
+func (l *wrapper) Handle(event Event) error {
+	return l.handle(context.Background(), event)
+}
+
+func (l *wrapper) HandleCtx(ctx context.Context, event Event) error {
+	return l.handle(ctx, event)
+}
+
+func (l *wrapper) Init(ctx context.Context) error {
+	if initializable, ok := l.inner.(Initializable); ok && l.initInner {
+		return initializable.Init(ctx)
+	}
+	return nil
+}
+
+func (l *wrapper) Close(ctx context.Context) error {
+	var errs []error
+	if l.close != nil {
+		errs = append(errs, l.close(ctx))
+	}
+	if closable, ok := l.inner.(Closable); ok {
+		errs = append(errs, closable.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+type namedWrapper struct {
+	*wrapper
+	name string
+}
+
+func (l *namedWrapper) Name() string {
+	return l.name
+}
+
+type asyncWrapper struct {
+	IAmAsync
+	*wrapper
+}
+
+type namedAsyncWrapper struct {
+	IAmAsync
+	namedWrapper
+}
+
+type validatorWrapper struct {
+	IAmValidator
+	*wrapper
+}
+
+type namedValidatorWrapper struct {
+	IAmValidator
+	namedWrapper
+}
@@ -0,0 +1,104 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptedListener(t *testing.T) {
+	var got string
+	legacy := NewNamedListener("legacy", func(event Event) error {
+		got = string(event.Payload())
+		return nil
+	})
+	adapted := NewAdaptedListener(func(event Event) (Event, error) {
+		if string(event.Payload()) == "skip" {
+			return nil, nil
+		}
+		if string(event.Payload()) == "bad" {
+			return nil, assert.AnError
+		}
+		return NewEvent(event.Type(), append([]byte("v1:"), event.Payload()...)), nil
+	}, legacy)
+
+	require.NoError(t, adapted.Handle(NewEvent("user.created", []byte("bob"))))
+	assert.Equal(t, "v1:bob", got)
+
+	require.NoError(t, adapted.Handle(NewEvent("user.created", []byte("skip"))))
+	assert.Equal(t, "v1:bob", got)
+
+	assert.ErrorIs(t, adapted.Handle(NewEvent("user.created", []byte("bad"))), assert.AnError)
+
+	namable, ok := adapted.(Namable)
+	require.True(t, ok, "adapted listener should keep the inner name")
+	assert.Equal(t, "legacy", namable.Name())
+
+	passthrough := NewAdaptedListener(nil, legacy)
+	require.NoError(t, passthrough.Handle(NewEvent("user.created", []byte("alice"))))
+	assert.Equal(t, "alice", got)
+}
+
+func TestAdaptedListener_ForwardsContextAndLifecycle(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	e.Provide("resource")
+	var resolved string
+	inner := &contextLifecycleListener{lifecycleListener: &lifecycleListener{}, handle: func(ctx context.Context, _ Event) error {
+		resolved, _ = Resolve[string](ctx)
+		return nil
+	}}
+	adapted := NewAdaptedListener(nil, inner)
+	e.Register("user.created", adapted)
+
+	e.EmitBy("user.created", nil)
+	assert.Equal(t, "resource", resolved, "the inner listener should get the context of the bus")
+	assert.Equal(t, 1, inner.inits)
+
+	e.Unregister("user.created", adapted)
+	sim.Run()
+	assert.Equal(t, 1, inner.closes)
+}
+
+type contextLifecycleListener struct {
+	*lifecycleListener
+	handle func(ctx context.Context, event Event) error
+}
+
+func (l *contextLifecycleListener) HandleCtx(ctx context.Context, event Event) error {
+	return l.handle(ctx, event)
+}
+
+func TestWrapListener(t *testing.T) {
+	handle := func(Event) error { return nil }
+	tests := []struct {
+		name          string
+		inner         Listener
+		wantName      string
+		wantAsync     bool
+		wantValidator bool
+	}{
+		{name: "plain", inner: NewListener(nil)},
+		{name: "named", inner: NewNamedListener("n", nil), wantName: "n"},
+		{name: "async", inner: &asyncListener{handle: handle}, wantAsync: true},
+		{name: "named async", inner: &namedAsyncListener{name: "na", handle: handle}, wantName: "na", wantAsync: true},
+		{name: "validator", inner: NewValidator(handle), wantValidator: true},
+		{name: "named validator", inner: &namedValidator{namedListener: namedListener{name: "nv", handle: handle}}, wantName: "nv", wantValidator: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := wrapListener(tt.inner, handle)
+			namable, named := wrapped.(Namable)
+			assert.Equal(t, tt.wantName != "", named)
+			if named {
+				assert.Equal(t, tt.wantName, namable.Name())
+			}
+			_, async := wrapped.(IsAsync)
+			assert.Equal(t, tt.wantAsync, async)
+			_, validator := wrapped.(IsValidator)
+			assert.Equal(t, tt.wantValidator, validator)
+		})
+	}
+}
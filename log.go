@@ -1,5 +1,11 @@
 package eventify
 
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
 // Log is an interface that represents a logger.
 type Log interface {
 	Debug(msg string, kvs ...any)
@@ -10,3 +16,46 @@ type NoLog struct{}
 
 // Debug does nothing.
 func (*NoLog) Debug(msg string, kvs ...any) {}
+
+// NewSlogLog creates a logger writing to the slog logger at the debug level.
+func NewSlogLog(logger *slog.Logger) Log {
+	return &slogLog{logger: logger}
+}
+
+type slogLog struct {
+	logger *slog.Logger
+}
+
+func (l *slogLog) Debug(msg string, kvs ...any) {
+	l.logger.Log(context.Background(), slog.LevelDebug, msg, kvs...)
+}
+
+// fieldsLog is a logger that appends a fixed set of key-values to every call.
+type fieldsLog struct {
+	log Log
+	kvs []any
+}
+
+func (l *fieldsLog) Debug(msg string, kvs ...any) {
+	l.log.Debug(msg, slices.Concat(kvs, l.kvs)...)
+}
+
+// withEventFields returns a logger that carries the event ID, correlation ID and
+// listener name (when available) on every call, so logs from a single event flow
+// can be grepped together.
+func withEventFields(log Log, event Event, listener Listener) Log {
+	kvs := make([]any, 0, 6)
+	if identifiable, ok := event.(Identifiable); ok && identifiable.ID() != "" {
+		kvs = append(kvs, "event_id", identifiable.ID())
+	}
+	if correlatable, ok := event.(Correlatable); ok && correlatable.CorrelationID() != "" {
+		kvs = append(kvs, "correlation_id", correlatable.CorrelationID())
+	}
+	if namable, ok := listener.(Namable); ok {
+		kvs = append(kvs, "listener", namable.Name())
+	}
+	if len(kvs) == 0 {
+		return log
+	}
+	return &fieldsLog{log: log, kvs: kvs}
+}
@@ -0,0 +1,22 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithEventFields_DoesNotShareCallerSlice(t *testing.T) {
+	log := &recordLog{}
+	fields := withEventFields(log, &tracedEvent{Event: NewEvent("order.created", nil), id: "e1"}, nil)
+	kvs := make([]any, 2, 8)
+	kvs[0], kvs[1] = "a", 1
+
+	fields.Debug("first", kvs...)
+	fields.Debug("second", append(kvs, "b", 2)...)
+
+	first, _ := log.find("first")
+	assert.Equal(t, []any{"a", 1, "event_id", "e1"}, first.kvs)
+	second, _ := log.find("second")
+	assert.Equal(t, []any{"a", 1, "b", 2, "event_id", "e1"}, second.kvs)
+}
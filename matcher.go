@@ -1,11 +1,20 @@
 package eventify
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPattern is returned, wrapping the reason, for glob patterns that can't be compiled.
+var ErrInvalidPattern = errors.New("eventify: invalid pattern")
 
 // Matcher is a struct that represents a string matcher.
 type Matcher struct {
-	pattern   string // The pattern without wildcards
-	matchType int    // 0: exact, 1: prefix, 2: suffix, 3: wildcard
+	pattern   string         // The pattern without wildcards
+	matchType int            // 0: exact, 1: prefix, 2: suffix, 3: contains, 4: wildcard, 5: glob
+	re        *regexp.Regexp // The compiled glob, for matchGlob, nil if it is invalid
 }
 
 const (
@@ -14,6 +23,7 @@ const (
 	matchSuffix          // *suffix
 	matchContains        // *middle*
 	matchWildcard        // *
+	matchGlob            // any other glob, compiled
 )
 
 // NewMatcher creates a new matcher with the specified pattern.
@@ -21,18 +31,37 @@ const (
 // - "*" matches any string
 // - "prefix*" matches strings starting with "prefix"
 // - "*suffix" matches strings ending with "suffix"
+// - "*middle*" matches strings containing "middle"
 // - "exact" matches exactly "exact"
+// - any other glob, compiled once, where:
+//   - "*" matches any sequence of characters within a dot-separated segment
+//   - "**" matches any sequence of characters, dots included
+//   - "?" matches any single character
+//   - "[abc]", "[a-z]" and "[!abc]" match a character in, or not in, the class
+//   - "{a,b}" matches any of the comma-separated alternatives, which can be globs themselves
+//   - "\" escapes the next character
+//
+// For example "order.*.v?" matches "order.created.v2" but not "order.eu.created.v2", "order.**.v?" matches both,
+// and "user.{created,deleted}" matches "user.deleted".
+// A glob that can't be compiled, such as "x[z-a]", matches nothing; ValidatePattern reports why.
+//
+// The "*" of the simple forms matches across dots, as it always has, while the "*" of a glob stops at them:
+// "order.*" matches "order.eu.fr.created", but "order.*.created" doesn't, and neither does "order.*.created*"
+// match "order.eu.created.v2", since its trailing "*" is part of the glob. Use "**" in a glob to match across dots.
 func NewMatcher(s string) *Matcher {
 	m := &Matcher{}
 
 	switch {
 	case s == "*":
 		m.matchType = matchWildcard
+	case isGlob(s):
+		m.matchType = matchGlob
+		m.pattern = s
+		m.re, _ = compileGlob(s)
 	case len(s) > 1 && s[0] == '*' && s[len(s)-1] == '*':
-		// Handle patterns like *middle* if needed in the future
-		m.matchType = matchContains // Not currently supported, fallback to exact
+		m.matchType = matchContains
 		m.pattern = s[1 : len(s)-1]
-	case s[0] == '*':
+	case len(s) > 0 && s[0] == '*':
 		m.matchType = matchSuffix
 		m.pattern = s[1:]
 	case len(s) > 0 && s[len(s)-1] == '*':
@@ -57,7 +86,141 @@ func (m *Matcher) Match(target string) bool {
 		return len(target) >= len(m.pattern) && target[len(target)-len(m.pattern):] == m.pattern
 	case matchContains:
 		return strings.Contains(target, m.pattern)
+	case matchGlob:
+		return m.re != nil && m.re.MatchString(target)
 	default: // matchExact
 		return target == m.pattern
 	}
 }
+
+// isGlob reports whether the pattern needs the glob engine rather than one of the simple forms.
+func isGlob(s string) bool {
+	if strings.ContainsAny(s, "?[{\\") {
+		return true
+	}
+	if len(s) < 3 {
+		return false
+	}
+	return strings.Contains(strings.Trim(s, "*"), "*") || strings.HasPrefix(s, "**") || strings.HasSuffix(s, "**")
+}
+
+// ValidatePattern returns an error wrapping ErrInvalidPattern if the pattern is a glob that can't be compiled.
+// Patterns coming from remote clients should be validated before they are subscribed to.
+func ValidatePattern(pattern string) error {
+	if !isGlob(pattern) {
+		return nil
+	}
+	_, err := compileGlob(pattern)
+	return err
+}
+
+// compileGlob compiles the glob into an anchored regular expression.
+func compileGlob(s string) (*regexp.Regexp, error) {
+	expr, _ := globToRegexp(s, 0, false)
+	re, err := regexp.Compile("^(?s:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidPattern, s, err)
+	}
+	return re, nil
+}
+
+// globToRegexp translates the glob from index i until its end, or until the closing "}"
+// or a "," of the enclosing alternation when inAlternation is set.
+// It returns the regular expression and the index where it stopped.
+func globToRegexp(s string, i int, inAlternation bool) (string, int) {
+	var b strings.Builder
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '*':
+			stars := 0
+			for i < len(s) && s[i] == '*' {
+				i++
+				stars++
+			}
+			if stars > 1 {
+				b.WriteString(".*")
+			} else {
+				b.WriteString(`[^.]*`)
+			}
+			continue
+		case c == '?':
+			b.WriteString(".")
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteString(regexp.QuoteMeta(s[i : i+1]))
+		case c == '[':
+			if class, end, ok := globClass(s, i); ok {
+				b.WriteString(class)
+				i = end
+			} else {
+				b.WriteString(`\[`)
+			}
+		case c == '{':
+			if alternation, end, ok := globAlternation(s, i); ok {
+				b.WriteString(alternation)
+				i = end
+			} else {
+				b.WriteString(`\{`)
+			}
+		case inAlternation && (c == ',' || c == '}'):
+			return b.String(), i
+		default:
+			b.WriteString(regexp.QuoteMeta(s[i : i+1]))
+		}
+		i++
+	}
+	return b.String(), i
+}
+
+// globClass translates the character class starting at s[i] == '['.
+// It returns the class, the index of its closing "]" and whether the class is terminated.
+func globClass(s string, i int) (string, int, bool) {
+	j := i + 1
+	negate := j < len(s) && (s[j] == '!' || s[j] == '^')
+	if negate {
+		j++
+	}
+	start := j
+	if j < len(s) && s[j] == ']' {
+		j++
+	}
+	for j < len(s) && s[j] != ']' {
+		j++
+	}
+	if j >= len(s) {
+		return "", 0, false
+	}
+	var b strings.Builder
+	b.WriteString("[")
+	if negate {
+		b.WriteString("^")
+	}
+	for _, r := range s[start:j] {
+		switch r {
+		case '\\', '[', ']', '^':
+			b.WriteString(`\`)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString("]")
+	return b.String(), j, true
+}
+
+// globAlternation translates the alternation starting at s[i] == '{'.
+// It returns the group, the index of its closing "}" and whether the alternation is terminated.
+func globAlternation(s string, i int) (string, int, bool) {
+	alternatives := []string{}
+	j := i + 1
+	for {
+		expr, end := globToRegexp(s, j, true)
+		if end >= len(s) {
+			return "", 0, false
+		}
+		alternatives = append(alternatives, expr)
+		if s[end] == '}' {
+			return "(?:" + strings.Join(alternatives, "|") + ")", end, true
+		}
+		j = end + 1
+	}
+}
@@ -1,6 +1,9 @@
 package eventify
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestNewMatcher(t *testing.T) {
 	tests := []struct {
@@ -164,3 +167,94 @@ func TestMatcher_Match(t *testing.T) {
 		})
 	}
 }
+
+func TestMatcher_Glob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		want    bool
+	}{
+		{"order.*.v?", "order.created.v2", true},
+		{"order.*.v?", "order.created.v10", false},
+		{"order.*.v?", "order.v2", false},
+		{"user.{created,deleted}", "user.created", true},
+		{"user.{created,deleted}", "user.deleted", true},
+		{"user.{created,deleted}", "user.updated", false},
+		{"user.{created,de*}", "user.deactivated", true},
+		{"user.{a,{b,c}}", "user.c", true},
+		{"**", "a.b.c", true},
+		{"a.**", "a.b.c", true},
+		{"**.c", "a.b.c", true},
+		{"a.*.c", "a.b.x.c", false},
+		{"a.**.c", "a.b.x.c", true},
+		{"a.*.c", "a.b.c", true},
+		{"a.*.c", "a.c", false},
+		{"v[0-9]", "v7", true},
+		{"v[0-9]", "vx", false},
+		{"v[!0-9]", "vx", true},
+		{"v[!0-9]", "v7", false},
+		{"literal\\*star", "literal*star", true},
+		{"literal\\*star", "literalXstar", false},
+		{"open[bracket", "open[bracket", true},
+		{"open{brace", "open{brace", true},
+		{"dots.are.literal?", "dotsXare.literal!", false},
+		{"x[z-a]", "xz", false},
+		{"x[z-a]", "x[z-a]", false},
+		{"", "", true},
+		// The "*" of the simple forms crosses dots, the "*" of a glob doesn't.
+		{"order.*", "order.eu.fr.created", true},
+		{"*.created", "order.eu.created", true},
+		{"*.eu.*", "order.eu.created", true},
+		{"order.*.created", "order.eu.created", true},
+		{"order.*.created", "order.eu.fr.created", false},
+		{"order.*.created*", "order.eu.created_v2", true},
+		{"order.*.created*", "order.eu.created.v2", false},
+		{"order.**.created", "order.eu.fr.created", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.input, func(t *testing.T) {
+			if got := NewMatcher(tt.pattern).Match(tt.input); got != tt.want {
+				t.Errorf("NewMatcher(%q).Match(%q) = %v, want %v", tt.pattern, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{"order.*", false},
+		{"order.*.v[0-9]", false},
+		{"x[z-a]", true},
+		{"user.{a,[z-a]}", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			err := ValidatePattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePattern(%q) = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPattern) {
+				t.Errorf("ValidatePattern(%q) = %v, want ErrInvalidPattern", tt.pattern, err)
+			}
+		})
+	}
+}
+
+func TestEmit_InvalidPattern(t *testing.T) {
+	bus := New()
+	var received int
+	bus.Register("x[z-a]", NewListener(func(Event) error { return nil }))
+	bus.Register("order.created", NewListener(func(Event) error {
+		received++
+		return nil
+	}))
+
+	bus.Emit(NewEvent("order.created", nil))
+
+	if received != 1 {
+		t.Errorf("received = %d, want 1", received)
+	}
+}
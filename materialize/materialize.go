@@ -0,0 +1,312 @@
+// Package materialize maintains read models from event streams: mapping functions turn events
+// into upserts and deletes against a Sink, such as a SQL table, a search index or a Redis hash,
+// and the position of the last applied event is checkpointed so the model catches up after a restart
+// and can be rebuilt from scratch by replaying the history:
+//
+//	m := materialize.New("users", sink, materialize.WithHistory(history))
+//	m.On("user.*", func(event eventify.Event) ([]materialize.Op, error) {
+//		user, err := eventify.DecodedPayload[User](event)
+//		if err != nil {
+//			return nil, err
+//		}
+//		if event.Type() == "user.deleted" {
+//			return []materialize.Op{materialize.Delete(user.ID)}, nil
+//		}
+//		return []materialize.Op{materialize.Upsert(user.ID, user)}, nil
+//	})
+//	bus.Mount(m.Module(bus))
+package materialize
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// ErrNoHistory is returned by Rebuild when the Materializer has no History to replay.
+var ErrNoHistory = errors.New("materialize: no history")
+
+// Op is an operation on the read model.
+type Op struct {
+	// Delete reports whether the document is deleted instead of upserted.
+	Delete bool
+	// Key identifies the document.
+	Key string
+	// Doc is the document upserted.
+	Doc any
+}
+
+// Upsert returns the operation inserting or replacing the document with the key.
+func Upsert(key string, doc any) Op {
+	return Op{Key: key, Doc: doc}
+}
+
+// Delete returns the operation deleting the document with the key.
+func Delete(key string) Op {
+	return Op{Delete: true, Key: key}
+}
+
+// Mapper maps an event to the operations applying it to the read model; none skips the event.
+// Events may be applied more than once around a catch-up, so operations must be idempotent.
+type Mapper func(event eventify.Event) ([]Op, error)
+
+// Sink stores the documents of a read model.
+type Sink interface {
+	Upsert(ctx context.Context, key string, doc any) error
+	Delete(ctx context.Context, key string) error
+	// Truncate deletes all the documents, before a rebuild.
+	Truncate(ctx context.Context) error
+}
+
+// Checkpoints stores the ID of the last event applied to every read model, by name.
+type Checkpoints interface {
+	// Load returns the checkpoint of the read model, or "" if there is none.
+	Load(ctx context.Context, name string) (string, error)
+	Save(ctx context.Context, name string, id string) error
+}
+
+// History replays past events in order, such as from a log table or a topic.
+type History interface {
+	// Replay calls apply with every event after the one with the ID, or from the beginning for "",
+	// and stops at the first error.
+	Replay(ctx context.Context, after string, apply func(eventify.Event) error) error
+}
+
+// HistoryFunc is a function implementing History.
+type HistoryFunc func(ctx context.Context, after string, apply func(eventify.Event) error) error
+
+// Replay calls f.
+func (f HistoryFunc) Replay(ctx context.Context, after string, apply func(eventify.Event) error) error {
+	return f(ctx, after, apply)
+}
+
+// Materializer applies events to a read model.
+type Materializer struct {
+	name        string
+	sink        Sink
+	checkpoints Checkpoints
+	history     History
+	mutex       sync.Mutex
+	routes      []route
+}
+
+type route struct {
+	matcher *eventify.Matcher
+	mapper  Mapper
+}
+
+// OptionFunc is a function that configures a Materializer.
+type OptionFunc func(*Materializer)
+
+// WithCheckpoints stores the checkpoints in the store, in memory by default.
+func WithCheckpoints(checkpoints Checkpoints) OptionFunc {
+	return func(m *Materializer) {
+		m.checkpoints = checkpoints
+	}
+}
+
+// WithHistory sets the history replayed to catch up and rebuild.
+func WithHistory(history History) OptionFunc {
+	return func(m *Materializer) {
+		m.history = history
+	}
+}
+
+// New creates a new Materializer of the read model with the name, storing its documents in the sink.
+func New(name string, sink Sink, opts ...OptionFunc) *Materializer {
+	m := &Materializer{
+		name:        name,
+		sink:        sink,
+		checkpoints: NewMemoryCheckpoints(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// On maps the events whose type matches the pattern with the mapper.
+// Every matching mapper is applied, in the order they were added.
+// This method is thread-safe.
+func (m *Materializer) On(pattern string, mapper Mapper) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.routes = append(m.routes, route{matcher: eventify.NewMatcher(pattern), mapper: mapper})
+}
+
+// Apply applies the event to the read model and, if it implements eventify.Identifiable,
+// checkpoints its ID. Events are applied one at a time.
+// This method is thread-safe.
+func (m *Materializer) Apply(ctx context.Context, event eventify.Event) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.apply(ctx, event)
+}
+
+func (m *Materializer) apply(ctx context.Context, event eventify.Event) error {
+	for _, route := range m.routes {
+		if !route.matcher.Match(event.Type()) {
+			continue
+		}
+		ops, err := route.mapper(event)
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if op.Delete {
+				err = m.sink.Delete(ctx, op.Key)
+			} else {
+				err = m.sink.Upsert(ctx, op.Key, op.Doc)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if identifiable, ok := event.(eventify.Identifiable); ok && identifiable.ID() != "" {
+		return m.checkpoints.Save(ctx, m.name, identifiable.ID())
+	}
+	return nil
+}
+
+// CatchUp applies the events of the history after the checkpoint.
+// This method is thread-safe.
+func (m *Materializer) CatchUp(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.catchUp(ctx)
+}
+
+func (m *Materializer) catchUp(ctx context.Context) error {
+	if m.history == nil {
+		return nil
+	}
+	after, err := m.checkpoints.Load(ctx, m.name)
+	if err != nil {
+		return err
+	}
+	return m.history.Replay(ctx, after, func(event eventify.Event) error {
+		return m.apply(ctx, event)
+	})
+}
+
+// Rebuild truncates the read model and replays the whole history into it.
+// This method is thread-safe.
+func (m *Materializer) Rebuild(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.history == nil {
+		return ErrNoHistory
+	}
+	if err := m.sink.Truncate(ctx); err != nil {
+		return err
+	}
+	if err := m.checkpoints.Save(ctx, m.name, ""); err != nil {
+		return err
+	}
+	return m.catchUp(ctx)
+}
+
+// Listener returns a listener applying the events of the bus, named "materialize.<name>".
+func (m *Materializer) Listener() eventify.Listener {
+	return eventify.NewNamedListener("materialize."+m.name, func(event eventify.Event) error {
+		return m.Apply(context.Background(), event)
+	})
+}
+
+// Module returns a module keeping the read model in sync with the bus while it runs:
+// it registers the listener for "*", catches up with the history, blocking live events meanwhile,
+// and unregisters the listener when stopped.
+func (m *Materializer) Module(bus *eventify.Eventify) eventify.Module {
+	return eventify.ModuleFunc(func(ctx context.Context) error {
+		listener := m.Listener()
+		m.mutex.Lock()
+		bus.Register("*", listener)
+		err := m.catchUp(ctx)
+		m.mutex.Unlock()
+		defer bus.UnregisterAndDrain(context.Background(), "*", listener)
+		if err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	})
+}
+
+// MemorySink is a Sink keeping the documents in memory, for small read models and tests.
+type MemorySink struct {
+	mutex sync.RWMutex
+	docs  map[string]any
+}
+
+// NewMemorySink creates a new empty MemorySink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{docs: map[string]any{}}
+}
+
+// Upsert implements Sink.
+func (s *MemorySink) Upsert(_ context.Context, key string, doc any) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.docs[key] = doc
+	return nil
+}
+
+// Delete implements Sink.
+func (s *MemorySink) Delete(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.docs, key)
+	return nil
+}
+
+// Truncate implements Sink.
+func (s *MemorySink) Truncate(context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.docs = map[string]any{}
+	return nil
+}
+
+// Get returns the document with the key.
+func (s *MemorySink) Get(key string) (any, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	doc, ok := s.docs[key]
+	return doc, ok
+}
+
+// Len returns the number of documents.
+func (s *MemorySink) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.docs)
+}
+
+// MemoryCheckpoints is a Checkpoints store in memory.
+type MemoryCheckpoints struct {
+	mutex sync.Mutex
+	ids   map[string]string
+}
+
+// NewMemoryCheckpoints creates a new empty MemoryCheckpoints.
+func NewMemoryCheckpoints() *MemoryCheckpoints {
+	return &MemoryCheckpoints{ids: map[string]string{}}
+}
+
+// Load implements Checkpoints.
+func (c *MemoryCheckpoints) Load(_ context.Context, name string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.ids[name], nil
+}
+
+// Save implements Checkpoints.
+func (c *MemoryCheckpoints) Save(_ context.Context, name string, id string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.ids[name] = id
+	return nil
+}
@@ -0,0 +1,130 @@
+package materialize
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type userEvent struct {
+	id        string
+	eventType string
+	user      user
+}
+
+func (e *userEvent) ID() string   { return e.id }
+func (e *userEvent) Type() string { return e.eventType }
+func (e *userEvent) Payload() []byte {
+	bz, _ := json.Marshal(e.user)
+	return bz
+}
+
+func users(m *Materializer) {
+	m.On("user.*", func(event eventify.Event) ([]Op, error) {
+		var u user
+		if err := json.Unmarshal(event.Payload(), &u); err != nil {
+			return nil, err
+		}
+		if event.Type() == "user.deleted" {
+			return []Op{Delete(u.ID)}, nil
+		}
+		return []Op{Upsert(u.ID, u.Name)}, nil
+	})
+}
+
+var history = []eventify.Event{
+	&userEvent{id: "1", eventType: "user.created", user: user{ID: "a", Name: "alice"}},
+	&userEvent{id: "2", eventType: "user.created", user: user{ID: "b", Name: "bob"}},
+	&userEvent{id: "3", eventType: "order.created"},
+	&userEvent{id: "4", eventType: "user.renamed", user: user{ID: "a", Name: "alicia"}},
+	&userEvent{id: "5", eventType: "user.deleted", user: user{ID: "b"}},
+}
+
+func replay(events []eventify.Event) HistoryFunc {
+	return func(_ context.Context, after string, apply func(eventify.Event) error) error {
+		started := after == ""
+		for _, event := range events {
+			if started {
+				if err := apply(event); err != nil {
+					return err
+				}
+			}
+			started = started || event.(eventify.Identifiable).ID() == after
+		}
+		return nil
+	}
+}
+
+func TestMaterializer_Apply(t *testing.T) {
+	sink := NewMemorySink()
+	checkpoints := NewMemoryCheckpoints()
+	m := New("users", sink, WithCheckpoints(checkpoints))
+	users(m)
+
+	for _, event := range history {
+		assert.NoError(t, m.Apply(context.Background(), event))
+	}
+
+	assert.Equal(t, 1, sink.Len())
+	doc, _ := sink.Get("a")
+	assert.Equal(t, "alicia", doc)
+	id, _ := checkpoints.Load(context.Background(), "users")
+	assert.Equal(t, "5", id)
+	assert.Error(t, m.Apply(context.Background(), eventify.NewEvent("user.created", []byte("{"))))
+}
+
+func TestMaterializer_CatchUpAndRebuild(t *testing.T) {
+	sink := NewMemorySink()
+	checkpoints := NewMemoryCheckpoints()
+	var replayed []string
+	m := New("users", sink, WithCheckpoints(checkpoints), WithHistory(HistoryFunc(func(ctx context.Context, after string, apply func(eventify.Event) error) error {
+		replayed = append(replayed, after)
+		return replay(history)(ctx, after, apply)
+	})))
+	users(m)
+	assert.NoError(t, checkpoints.Save(context.Background(), "users", "2"))
+	assert.NoError(t, sink.Upsert(context.Background(), "b", "bob"))
+	assert.NoError(t, sink.Upsert(context.Background(), "stale", "x"))
+
+	assert.NoError(t, m.CatchUp(context.Background()))
+	assert.Equal(t, 2, sink.Len())
+	_, ok := sink.Get("stale")
+	assert.True(t, ok)
+
+	assert.NoError(t, m.Rebuild(context.Background()))
+	assert.Equal(t, 1, sink.Len())
+	doc, _ := sink.Get("a")
+	assert.Equal(t, "alicia", doc)
+	assert.Equal(t, []string{"2", ""}, replayed)
+
+	assert.ErrorIs(t, New("users", sink).Rebuild(context.Background()), ErrNoHistory)
+}
+
+func TestMaterializer_Module(t *testing.T) {
+	bus := eventify.NewEventify()
+	sink := NewMemorySink()
+	m := New("users", sink, WithHistory(replay(history[:2])))
+	users(m)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Module(bus).Run(ctx) }()
+
+	assert.Eventually(t, func() bool { return sink.Len() == 2 }, time.Second, time.Millisecond)
+	bus.Emit(history[4])
+	assert.Equal(t, 1, sink.Len())
+
+	cancel()
+	assert.NoError(t, <-done)
+	bus.Emit(history[3])
+	doc, _ := sink.Get("a")
+	assert.Equal(t, "alice", doc)
+}
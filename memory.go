@@ -0,0 +1,71 @@
+package eventify
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	// ErrMemoryLimitExceeded is reported when an event is rejected because the memory limit is reached.
+	ErrMemoryLimitExceeded = errors.New("eventify: memory limit exceeded")
+	// ErrPayloadTooLarge is reported when an event is rejected because its payload exceeds the maximum size.
+	ErrPayloadTooLarge = errors.New("eventify: payload too large")
+)
+
+// MemoryStats are the approximate number of bytes held by the Eventify instance.
+type MemoryStats struct {
+	// AsyncBytes are held by asynchronous deliveries and redeliveries in progress.
+	AsyncBytes int64
+	// QueuedBytes are held by events waiting in ordered delivery queues.
+	QueuedBytes int64
+	// SinkBytes are held by emit records waiting to be written to the sink.
+	SinkBytes int64
+	// Limit is the memory limit, zero if none.
+	Limit int64
+}
+
+// Total returns the total number of bytes held.
+func (m MemoryStats) Total() int64 {
+	return m.AsyncBytes + m.QueuedBytes + m.SinkBytes
+}
+
+// memoryUsage counts the approximate bytes held by an Eventify instance.
+type memoryUsage struct {
+	async  atomic.Int64
+	queued atomic.Int64
+	sink   atomic.Int64
+}
+
+// eventSize returns the approximate number of bytes held by the event.
+// The value of an AnyEvent is not counted, to avoid marshaling it.
+func eventSize(event Event) int64 {
+	if _, ok := event.(AnyEvent); ok {
+		return int64(len(event.Type()))
+	}
+	return int64(len(event.Type()) + len(event.Payload()))
+}
+
+// Memory returns the approximate number of bytes held by the instance.
+func (e *Eventify) Memory() MemoryStats {
+	return MemoryStats{
+		AsyncBytes:  e.memory.async.Load(),
+		QueuedBytes: e.memory.queued.Load(),
+		SinkBytes:   e.memory.sink.Load(),
+		Limit:       e.memoryLimit,
+	}
+}
+
+// _OverMemoryLimit reports whether the memory limit is reached.
+func (e *Eventify) _OverMemoryLimit() bool {
+	return e.memoryLimit > 0 && e.Memory().Total() >= e.memoryLimit
+}
+
+// _Reject reports an event that is not dispatched to the logger and the event's ErrorHandler.
+func (e *Eventify) _Reject(event Event, err error) {
+	status := StatusOf(err)
+	withEventFields(e.log, event, nil).Debug("eventify emit rejected", "event", event.Type(), "status", status, "error", err)
+	if errHandler, ok := event.(ErrorHandler); ok {
+		errHandler.ErrorHandler(event, err)
+	}
+	e.ReportDelivery(event, nil, status, err)
+}
@@ -0,0 +1,54 @@
+package eventify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type asyncListener struct {
+	IAmAsync
+	handle func(Event) error
+}
+
+func (l *asyncListener) Handle(event Event) error { return l.handle(event) }
+
+func TestEventify_MemoryLimit(t *testing.T) {
+	e := NewEventify(WithMemoryLimit(10))
+	release := make(chan struct{})
+	e.Register("big.event", &asyncListener{handle: func(Event) error {
+		<-release
+		return nil
+	}})
+
+	e.EmitBy("big.event", "0123456789")
+	assert.Equal(t, int64(len("big.event")+10), e.Memory().AsyncBytes)
+
+	errChan := make(chan error, 1)
+	e.Emit(&mockErrorEvent{errChan: errChan})
+	select {
+	case err := <-errChan:
+		assert.ErrorIs(t, err, ErrMemoryLimitExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("rejection not reported")
+	}
+
+	close(release)
+	require.Eventually(t, func() bool { return e.Memory().Total() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestEventify_MaxPayloadSize(t *testing.T) {
+	e := NewEventify(WithMaxPayloadSize(4))
+	var received []string
+	e.Register("*", NewListener(func(event Event) error {
+		received = append(received, string(event.Payload()))
+		return nil
+	}))
+
+	e.EmitBy("small", "1234")
+	e.EmitBy("large", "12345")
+
+	assert.Equal(t, []string{"1234"}, received)
+}
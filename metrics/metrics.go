@@ -0,0 +1,244 @@
+// Package metrics derives metrics from events with declarative rules, so business KPIs such as
+// orders per minute or revenue surface in Prometheus without bespoke listeners:
+//
+//	deriver := metrics.New(
+//		metrics.CountEvents("orders_total", "order.*"),
+//		metrics.SumField("revenue_total", "order.paid", "amount"),
+//		metrics.ObserveField("order_amount", "order.paid", "amount", []float64{10, 100, 1000}),
+//	)
+//	bus.Register("*", deriver)
+//	http.Handle("/metrics", deriver)
+//
+// The Deriver serves the metrics in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Kind is the kind of a metric.
+type Kind int
+
+const (
+	// Counter counts the events, or sums a field of their payload.
+	Counter Kind = iota
+	// Histogram observes a field of the payload of the events.
+	Histogram
+)
+
+// Rule declares a metric derived from the events whose type matches the pattern.
+type Rule struct {
+	// Name is the name of the metric, such as "orders_total".
+	Name string
+	// Help is the description of the metric.
+	Help string
+	Kind Kind
+	// Pattern selects the events, see eventify.NewMatcher.
+	Pattern string
+	// Field is the dotted path of the number in the JSON payload that a Counter adds instead of 1
+	// and a Histogram observes, such as "order.amount". Events without it are ignored, and so are
+	// the events with a negative value for a Counter, which can only increase.
+	Field string
+	// Buckets are the upper bounds of the buckets of a Histogram.
+	Buckets []float64
+	// ByType labels the series with the event type.
+	ByType bool
+	// MaxSeries caps the number of event types a ByType rule keeps series for, since event types may
+	// come from clients; zero means 100. The events of the types beyond it are counted in the series
+	// labeled with OtherType.
+	MaxSeries int
+}
+
+// OtherType is the type label of the series counting the events of the types beyond Rule.MaxSeries.
+const OtherType = "__other__"
+
+const defaultMaxSeries = 100
+
+// CountEvents counts the events per type.
+func CountEvents(name, pattern string) Rule {
+	return Rule{Name: name, Help: "Number of " + pattern + " events.", Kind: Counter, Pattern: pattern, ByType: true}
+}
+
+// SumField sums the field of the events, ignoring the negative values.
+func SumField(name, pattern, field string) Rule {
+	return Rule{Name: name, Help: "Sum of " + field + " of " + pattern + " events.", Kind: Counter, Pattern: pattern, Field: field}
+}
+
+// ObserveField observes the field of the events in a histogram with the buckets.
+func ObserveField(name, pattern, field string, buckets []float64) Rule {
+	return Rule{Name: name, Help: "Distribution of " + field + " of " + pattern + " events.", Kind: Histogram, Pattern: pattern, Field: field, Buckets: buckets}
+}
+
+// Deriver is a listener deriving metrics from events, and the http.Handler serving them.
+type Deriver struct {
+	mutex sync.Mutex
+	rules []*rule
+}
+
+type rule struct {
+	Rule
+	matcher *eventify.Matcher
+	series  map[string]*series
+}
+
+type series struct {
+	value   float64
+	count   uint64
+	buckets []uint64
+}
+
+// New creates a new Deriver with the rules.
+func New(rules ...Rule) *Deriver {
+	d := &Deriver{}
+	for _, r := range rules {
+		r.Buckets = slices.Sorted(slices.Values(r.Buckets))
+		if r.MaxSeries <= 0 {
+			r.MaxSeries = defaultMaxSeries
+		}
+		d.rules = append(d.rules, &rule{Rule: r, matcher: eventify.NewMatcher(r.Pattern), series: map[string]*series{}})
+	}
+	return d
+}
+
+// Name implements eventify.Namable.
+func (d *Deriver) Name() string {
+	return "metrics.deriver"
+}
+
+// Handle updates the metrics of the rules matching the event.
+func (d *Deriver) Handle(event eventify.Event) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, r := range d.rules {
+		if !r.matcher.Match(event.Type()) {
+			continue
+		}
+		value := 1.0
+		if r.Field != "" {
+			v, ok := field(event, r.Field)
+			if !ok || (r.Kind == Counter && v < 0) {
+				continue
+			}
+			value = v
+		}
+		key := ""
+		if r.ByType {
+			key = event.Type()
+			if _, ok := r.series[key]; !ok && len(r.series) >= r.MaxSeries {
+				key = OtherType
+			}
+		}
+		s, ok := r.series[key]
+		if !ok {
+			s = &series{buckets: make([]uint64, len(r.Buckets))}
+			r.series[key] = s
+		}
+		s.count++
+		s.value += value
+		for i, bound := range r.Buckets {
+			if value <= bound {
+				s.buckets[i]++
+			}
+		}
+	}
+	return nil
+}
+
+// field returns the number at the dotted path of the JSON payload of the event.
+func field(event eventify.Event, path string) (float64, bool) {
+	payload, err := eventify.DecodedPayload[map[string]any](event)
+	if err != nil {
+		return 0, false
+	}
+	var value any = payload
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return 0, false
+		}
+		value = object[key]
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (d *Deriver) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	d.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (d *Deriver) WriteTo(w io.Writer) (int64, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	b := &strings.Builder{}
+	for _, r := range d.rules {
+		kind := "counter"
+		if r.Kind == Histogram {
+			kind = "histogram"
+		}
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", r.Name, helpEscaper.Replace(r.Help), r.Name, kind)
+		for _, key := range slices.Sorted(maps.Keys(r.series)) {
+			s := r.series[key]
+			labels := []string{}
+			if r.ByType {
+				labels = append(labels, "type="+labelValue(key))
+			}
+			if r.Kind == Counter {
+				fmt.Fprintf(b, "%s%s %s\n", r.Name, labelSet(labels), number(s.value))
+				continue
+			}
+			for i, bound := range r.Buckets {
+				fmt.Fprintf(b, "%s_bucket%s %d\n", r.Name, labelSet(append(labels, "le="+labelValue(number(bound)))), s.buckets[i])
+			}
+			fmt.Fprintf(b, "%s_bucket%s %d\n", r.Name, labelSet(append(labels, `le="+Inf"`)), s.count)
+			fmt.Fprintf(b, "%s_sum%s %s\n", r.Name, labelSet(labels), number(s.value))
+			fmt.Fprintf(b, "%s_count%s %d\n", r.Name, labelSet(labels), s.count)
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// labelValue quotes the label value, escaping only the backslashes, double quotes and line feeds
+// as the exposition format does, unlike Go string literals.
+func labelValue(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func labelSet(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+func number(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriver(t *testing.T) {
+	bus := eventify.NewEventify()
+	deriver := New(
+		CountEvents("orders_total", "order.*"),
+		SumField("revenue_total", "order.paid", "payment.amount"),
+		ObserveField("order_amount", "order.paid", "payment.amount", []float64{100, 10}),
+	)
+	bus.Register("*", deriver)
+
+	bus.EmitBy("order.created", map[string]any{"id": 1})
+	bus.EmitBy("order.paid", map[string]any{"payment": map[string]any{"amount": 5}})
+	bus.EmitBy("order.paid", map[string]any{"payment": map[string]any{"amount": "50.5"}})
+	bus.EmitBy("order.paid", map[string]any{"payment": "invalid"})
+	bus.EmitBy("user.created", nil)
+
+	rec := httptest.NewRecorder()
+	deriver.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, `# HELP orders_total Number of order.* events.
+# TYPE orders_total counter
+orders_total{type="order.created"} 1
+orders_total{type="order.paid"} 3
+# HELP revenue_total Sum of payment.amount of order.paid events.
+# TYPE revenue_total counter
+revenue_total 55.5
+# HELP order_amount Distribution of payment.amount of order.paid events.
+# TYPE order_amount histogram
+order_amount_bucket{le="10"} 1
+order_amount_bucket{le="100"} 2
+order_amount_bucket{le="+Inf"} 2
+order_amount_sum 55.5
+order_amount_count 2
+`, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+}
+
+func TestDeriver_UntrustedEvents(t *testing.T) {
+	deriver := New(
+		Rule{Name: "events_total", Help: "Events\nby \\type.", Kind: Counter, Pattern: "*", ByType: true, MaxSeries: 2},
+		SumField("refunds_total", "refund", "amount"),
+	)
+
+	deriver.Handle(eventify.NewEvent("a\"b\\c\nd", nil))
+	deriver.Handle(eventify.NewEvent("b", nil))
+	deriver.Handle(eventify.NewEvent("c", nil))
+	deriver.Handle(eventify.NewEvent("d", nil))
+	deriver.Handle(eventify.NewEvent("refund", []byte(`{"amount":-5}`)))
+	deriver.Handle(eventify.NewEvent("refund", []byte(`{"amount":3}`)))
+
+	rec := httptest.NewRecorder()
+	deriver.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, `# HELP events_total Events\nby \\type.
+# TYPE events_total counter
+events_total{type="__other__"} 4
+events_total{type="a\"b\\c\nd"} 1
+events_total{type="b"} 1
+# HELP refunds_total Sum of amount of refund events.
+# TYPE refunds_total counter
+refunds_total 3
+`, rec.Body.String())
+}
@@ -0,0 +1,16 @@
+package eventify
+
+import "strings"
+
+// NormalizeType is the default type normalization: it trims surrounding whitespace and lowercases the type.
+func NormalizeType(eventType string) string {
+	return strings.ToLower(strings.TrimSpace(eventType))
+}
+
+// _Normalize applies the type normalization, if any, to an event type or pattern.
+func (e *Eventify) _Normalize(eventType string) string {
+	if e.normalize == nil {
+		return eventType
+	}
+	return e.normalize(eventType)
+}
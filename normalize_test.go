@@ -0,0 +1,38 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_TypeNormalization(t *testing.T) {
+	e := NewEventify(WithTypeNormalization(nil))
+	var received []string
+	e.Register(" User.* ", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+
+	e.EmitBy("USER.Created", nil)
+	e.Emit(NewEvent("user.Deleted ", nil))
+
+	assert.Equal(t, []string{"user.created", "user.Deleted "}, received)
+
+	e.Unregister("USER.*")
+	e.EmitBy("user.created", nil)
+	assert.Len(t, received, 2)
+}
+
+func TestEventify_NoTypeNormalizationByDefault(t *testing.T) {
+	e := New()
+	called := false
+	e.Register("user.created", NewListener(func(event Event) error {
+		called = true
+		return nil
+	}))
+
+	e.EmitBy("User.Created", nil)
+
+	assert.False(t, called)
+}
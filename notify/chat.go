@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// ChatConfig configures a chat webhook listener.
+type ChatConfig struct {
+	// URL is the incoming webhook URL of the channel.
+	URL string
+	// Text is rendered from the event into the message text.
+	Text *template.Template
+	// Thread is rendered from the event into a thread identifier, e.g. "{{.CorrelationID}}",
+	// so related events are posted to the same thread. Empty means no thread.
+	// Teams webhooks have no threads and ignore it.
+	Thread *template.Template
+	// RateLimit is the maximum number of messages per RatePeriod; zero disables rate limiting.
+	// Messages over the limit are dropped with ErrRateLimited.
+	RateLimit  int
+	RatePeriod time.Duration
+	// Client is the HTTP client, one timing out after 10 seconds by default.
+	Client *http.Client
+}
+
+// NewSlackListener creates a listener that posts every event to a Slack webhook.
+func NewSlackListener(cfg ChatConfig) eventify.Listener {
+	return newChatListener(cfg, func(text, thread string) (string, any) {
+		msg := map[string]string{"text": text}
+		if thread != "" {
+			msg["thread_ts"] = thread
+		}
+		return cfg.URL, msg
+	})
+}
+
+// NewDiscordListener creates a listener that posts every event to a Discord webhook.
+func NewDiscordListener(cfg ChatConfig) eventify.Listener {
+	return newChatListener(cfg, func(text, thread string) (string, any) {
+		target := cfg.URL
+		if thread != "" {
+			if u, err := url.Parse(cfg.URL); err == nil {
+				q := u.Query()
+				q.Set("thread_id", thread)
+				u.RawQuery = q.Encode()
+				target = u.String()
+			}
+		}
+		return target, map[string]string{"content": text}
+	})
+}
+
+// NewTeamsListener creates a listener that posts every event to a Microsoft Teams webhook.
+func NewTeamsListener(cfg ChatConfig) eventify.Listener {
+	return newChatListener(cfg, func(text, _ string) (string, any) {
+		return cfg.URL, map[string]string{"text": text}
+	})
+}
+
+func newChatListener(cfg ChatConfig, build func(text, thread string) (string, any)) eventify.Listener {
+	limit := newLimiter(cfg.RateLimit, cfg.RatePeriod)
+	return eventify.NewListener(func(event eventify.Event) error {
+		if !limit.Allow() {
+			return ErrRateLimited
+		}
+		data := NewData(event)
+		text, err := render(cfg.Text, data)
+		if err != nil {
+			return err
+		}
+		thread, err := render(cfg.Thread, data)
+		if err != nil {
+			return err
+		}
+		target, msg := build(text, thread)
+		body, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return do(cfg.Client, req)
+	})
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type correlatedEvent struct {
+	eventify.Event
+	correlationID string
+}
+
+func (c *correlatedEvent) CorrelationID() string { return c.correlationID }
+
+func TestChatListeners(t *testing.T) {
+	var (
+		gotQuery string
+		gotBody  map[string]string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("thread_id")
+		gotBody = map[string]string{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+	}))
+	defer server.Close()
+
+	cfg := ChatConfig{
+		URL:    server.URL,
+		Text:   template.Must(template.New("text").Parse("{{.Type}} {{.Payload}}")),
+		Thread: template.Must(template.New("thread").Parse("{{.CorrelationID}}")),
+	}
+	event := &correlatedEvent{Event: eventify.NewEvent("deploy.finished", []byte("v1.2")), correlationID: "42"}
+
+	require.NoError(t, NewSlackListener(cfg).Handle(event))
+	assert.Equal(t, map[string]string{"text": "deploy.finished v1.2", "thread_ts": "42"}, gotBody)
+
+	require.NoError(t, NewDiscordListener(cfg).Handle(event))
+	assert.Equal(t, map[string]string{"content": "deploy.finished v1.2"}, gotBody)
+	assert.Equal(t, "42", gotQuery)
+
+	require.NoError(t, NewTeamsListener(cfg).Handle(event))
+	assert.Equal(t, map[string]string{"text": "deploy.finished v1.2"}, gotBody)
+}
+
+func TestChatListener_RateLimit(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	listener := NewSlackListener(ChatConfig{
+		URL:        server.URL,
+		RateLimit:  2,
+		RatePeriod: time.Hour,
+	})
+
+	event := eventify.NewEvent("alert.fired", nil)
+	require.NoError(t, listener.Handle(event))
+	require.NoError(t, listener.Handle(event))
+	assert.ErrorIs(t, listener.Handle(event), ErrRateLimited)
+	assert.Equal(t, 2, calls)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestChatListener_Client(t *testing.T) {
+	var called bool
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})}
+	listener := NewSlackListener(ChatConfig{
+		URL:    "https://hooks.example.com/services/T1",
+		Text:   template.Must(template.New("text").Parse("{{.Type}}")),
+		Client: client,
+	})
+
+	require.NoError(t, listener.Handle(eventify.NewEvent("deploy.finished", nil)))
+	assert.True(t, called, "the configured client should be used")
+}
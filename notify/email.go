@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"text/template"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// ErrInvalidAddress is returned for email addresses containing line breaks, which would inject headers.
+var ErrInvalidAddress = errors.New("notify: invalid email address")
+
+// EmailConfig configures an email listener.
+type EmailConfig struct {
+	// Addr is the address of the SMTP server, e.g. "smtp.example.com:587".
+	Addr string
+	// Auth is the SMTP authentication, nil for none.
+	Auth smtp.Auth
+	From string
+	To   []string
+	// Subject and Body are rendered from the event.
+	Subject *template.Template
+	Body    *template.Template
+	// SendMail sends the message, smtp.SendMail by default.
+	SendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailListener creates a listener that sends an email for every event.
+// Line breaks in the rendered subject are replaced with spaces, and the subject is encoded as UTF-8.
+// Addresses containing line breaks are rejected with ErrInvalidAddress.
+func NewEmailListener(cfg EmailConfig) eventify.Listener {
+	if cfg.SendMail == nil {
+		cfg.SendMail = smtp.SendMail
+	}
+	return eventify.NewListener(func(event eventify.Event) error {
+		for _, address := range append([]string{cfg.From}, cfg.To...) {
+			if strings.ContainsAny(address, "\r\n") {
+				return fmt.Errorf("%w: %q", ErrInvalidAddress, address)
+			}
+		}
+		data := NewData(event)
+		subject, err := render(cfg.Subject, data)
+		if err != nil {
+			return err
+		}
+		subject = mime.QEncoding.Encode("utf-8", strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(subject))
+		body, err := render(cfg.Body, data)
+		if err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+			cfg.From, strings.Join(cfg.To, ", "), subject, body)
+		return cfg.SendMail(cfg.Addr, cfg.Auth, cfg.From, cfg.To, []byte(msg))
+	})
+}
@@ -0,0 +1,56 @@
+// Package notify provides ready-made listeners that deliver events as notifications.
+//
+// Every listener renders its message from templates executed with a Data value built
+// from the event, so alerting flows need no bespoke handler code:
+//
+//	body := template.Must(template.New("body").Parse("Order {{.JSON.id}} failed"))
+//	bus.Register("order.failed", notify.NewPushListener(notify.PushConfig{URL: url, Body: body}))
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Data is the value templates are executed with.
+type Data = eventify.TemplateData
+
+// NewData creates the template data for the event.
+func NewData(event eventify.Event) Data {
+	return eventify.NewTemplateData(event)
+}
+
+func render(tmpl *template.Template, data Data) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// defaultClient is the HTTP client of the listeners configured without one.
+// Unlike http.DefaultClient, it times out, so a stalled endpoint can't hold a delivery forever.
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+func do(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: %s %s: unexpected status %s", req.Method, req.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailListener(t *testing.T) {
+	var sent string
+	listener := NewEmailListener(EmailConfig{
+		Addr:    "smtp.example.com:25",
+		From:    "bus@example.com",
+		To:      []string{"ops@example.com"},
+		Subject: template.Must(template.New("subject").Parse("{{.Type}}")),
+		Body:    template.Must(template.New("body").Parse("user {{.JSON.name}}")),
+		SendMail: func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+			sent = string(msg)
+			return nil
+		},
+	})
+
+	require.NoError(t, listener.Handle(eventify.NewEvent("user.created", []byte(`{"name":"bob"}`))))
+
+	assert.Contains(t, sent, "Subject: user.created\r\n")
+	assert.Contains(t, sent, "\r\n\r\nuser bob")
+
+	require.NoError(t, listener.Handle(eventify.NewEvent("user.created\r\nBcc: victim@example.com", []byte(`{"name":"eve"}`))))
+	assert.Contains(t, sent, "Subject: user.created Bcc: victim@example.com\r\n")
+	assert.NotContains(t, sent, "\r\nBcc:")
+
+	require.NoError(t, listener.Handle(eventify.NewEvent("café", []byte(`{"name":"eve"}`))))
+	assert.Contains(t, sent, "Subject: =?utf-8?q?caf=C3=A9?=\r\n")
+}
+
+func TestEmailListener_InvalidAddress(t *testing.T) {
+	listener := NewEmailListener(EmailConfig{
+		To:       []string{"ops@example.com\r\nBcc: victim@example.com"},
+		Subject:  template.Must(template.New("subject").Parse("{{.Type}}")),
+		Body:     template.Must(template.New("body").Parse("")),
+		SendMail: func(string, smtp.Auth, string, []string, []byte) error { return nil },
+	})
+
+	assert.ErrorIs(t, listener.Handle(eventify.NewEvent("user.created", nil)), ErrInvalidAddress)
+}
+
+func TestTwilioListener(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC1/Messages.json", r.URL.Path)
+		user, _, _ := r.BasicAuth()
+		assert.Equal(t, "AC1", user)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "alert: disk", r.Form.Get("Body"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	listener := NewTwilioListener(TwilioConfig{
+		AccountSID: "AC1",
+		AuthToken:  "token",
+		From:       "+100",
+		To:         "+200",
+		Body:       template.Must(template.New("body").Parse("alert: {{.Payload}}")),
+		BaseURL:    server.URL,
+	})
+
+	require.NoError(t, listener.Handle(eventify.NewEvent("alert.fired", []byte("disk"))))
+}
+
+func TestPushListener(t *testing.T) {
+	var body string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bz, _ := io.ReadAll(r.Body)
+		body = string(bz)
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	listener := NewPushListener(PushConfig{
+		URL:    server.URL,
+		Header: http.Header{"X-Token": []string{"secret"}},
+		Body:   template.Must(template.New("body").Parse(`{"title":"{{.Type}}"}`)),
+	})
+
+	require.NoError(t, listener.Handle(eventify.NewEvent("deploy.finished", nil)))
+	assert.JSONEq(t, `{"title":"deploy.finished"}`, body)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, listener.Handle(eventify.NewEvent("deploy.finished", nil)))
+}
+
+func TestPushListener_DefaultClientTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	defer func(client *http.Client) { defaultClient = client }(defaultClient)
+	defaultClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+	listener := NewPushListener(PushConfig{URL: server.URL})
+
+	err := listener.Handle(eventify.NewEvent("order.failed", nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Client.Timeout")
+}
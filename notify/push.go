@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// PushConfig configures a generic push listener that posts to a webhook.
+type PushConfig struct {
+	URL string
+	// Header is added to every request.
+	Header http.Header
+	// Body is rendered from the event, usually into JSON.
+	Body *template.Template
+	// ContentType is the content type of the body, "application/json" by default.
+	ContentType string
+	// Client is the HTTP client, one timing out after 10 seconds by default.
+	Client *http.Client
+}
+
+// NewPushListener creates a listener that posts the rendered body to the URL for every event.
+func NewPushListener(cfg PushConfig) eventify.Listener {
+	if cfg.ContentType == "" {
+		cfg.ContentType = "application/json"
+	}
+	return eventify.NewListener(func(event eventify.Event) error {
+		body, err := render(cfg.Body, NewData(event))
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest(http.MethodPost, cfg.URL, strings.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for key, values := range cfg.Header {
+			req.Header[key] = values
+		}
+		req.Header.Set("Content-Type", cfg.ContentType)
+		return do(cfg.Client, req)
+	})
+}
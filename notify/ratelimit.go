@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by listeners when a notification is dropped by the rate limit.
+var ErrRateLimited = errors.New("notify: rate limited")
+
+// limiter allows at most limit notifications per period.
+type limiter struct {
+	mutex  sync.Mutex
+	limit  int
+	period time.Duration
+	start  time.Time
+	count  int
+}
+
+func newLimiter(limit int, period time.Duration) *limiter {
+	if limit <= 0 || period <= 0 {
+		return nil
+	}
+	return &limiter{limit: limit, period: period}
+}
+
+// Allow reports whether another notification may be sent now.
+// A nil limiter allows everything.
+func (l *limiter) Allow() bool {
+	if l == nil {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	if now.Sub(l.start) >= l.period {
+		l.start = now
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/payme50rmb/eventify"
+)
+
+const twilioBaseURL = "https://api.twilio.com"
+
+// TwilioConfig configures a Twilio SMS listener.
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	To         string
+	// Body is rendered from the event.
+	Body *template.Template
+	// BaseURL is the Twilio API URL, "https://api.twilio.com" by default.
+	BaseURL string
+	// Client is the HTTP client, one timing out after 10 seconds by default.
+	Client *http.Client
+}
+
+// NewTwilioListener creates a listener that sends an SMS through Twilio for every event.
+func NewTwilioListener(cfg TwilioConfig) eventify.Listener {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = twilioBaseURL
+	}
+	endpoint := cfg.BaseURL + "/2010-04-01/Accounts/" + url.PathEscape(cfg.AccountSID) + "/Messages.json"
+	return eventify.NewListener(func(event eventify.Event) error {
+		body, err := render(cfg.Body, NewData(event))
+		if err != nil {
+			return err
+		}
+		form := url.Values{}
+		form.Set("From", cfg.From)
+		form.Set("To", cfg.To)
+		form.Set("Body", body)
+		req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(cfg.AccountSID, cfg.AuthToken)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return do(cfg.Client, req)
+	})
+}
@@ -1,8 +1,36 @@
 package eventify
 
+import (
+	"time"
+
+	"github.com/payme50rmb/eventify/backoff"
+)
+
 // Option is a struct that represents an option for the Eventify instance.
 type Option struct {
-	log Log
+	log            Log
+	sink           Sink
+	sinkSampleRate float64
+	deliveries     []deliveryRule
+	producerQuotas map[string]producerQuota
+	fairWorkers    int
+	syncDispatch   bool
+	recoverPanics  bool
+	traceCapacity  int
+	memoryLimit    int64
+	profilerLabels bool
+	maxPayloadSize int
+	payloadGuard   bool
+	normalize      func(string) string
+	catalog        []string
+	marshalPolicy  MarshalErrorPolicy
+	compensations  map[string]string
+	outcomes       []string
+	retryBackoff   func() backoff.Backoff
+	scheduler      Scheduler
+	asyncTypes     []string
+	reorders       []reorderRule
+	schemaPatterns []string
 }
 
 // OptionFunc is a function that configures an Option.
@@ -15,10 +43,215 @@ func WithLogger(log Log) OptionFunc {
 	}
 }
 
+// WithEventSink writes a structured record of emits to the sink.
+// The sample rate is the fraction of emits recorded, between 0 and 1; 1 records every emit.
+// Records are written asynchronously and dropped when the sink can't keep up,
+// so a slow sink never blocks the emit path. Run writes the queued records before returning.
+func WithEventSink(sink Sink, sampleRate float64) OptionFunc {
+	return func(o *Option) {
+		o.sink = sink
+		o.sinkSampleRate = sampleRate
+	}
+}
+
+// WithDelivery sets the delivery guarantee of the events whose type matches the pattern,
+// so one instance can serve both telemetry and critical events.
+// When several patterns match an event type, the first one configured wins.
+func WithDelivery(pattern string, guarantee Guarantee) OptionFunc {
+	return func(o *Option) {
+		o.deliveries = append(o.deliveries, deliveryRule{pattern: pattern, guarantee: guarantee})
+	}
+}
+
+// WithProducerQuota limits the producer with the name to rate events per second on average,
+// with bursts of up to burst events. Emits over the quota are rejected with ErrQuotaExceeded.
+func WithProducerQuota(name string, rate float64, burst int) OptionFunc {
+	return func(o *Option) {
+		o.producerQuotas[name] = producerQuota{rate: rate, burst: burst}
+	}
+}
+
+// WithFairDispatch runs the asynchronous deliveries with the number of workers, taking them round-robin
+// across producers, see Eventify.Producer, instead of starting one task per delivery. A producer flooding
+// the bus then only delays its own deliveries: the others' wait for at most one delivery per producer.
+// Events emitted without a producer share one queue. Deliveries held for retries or ordered per key
+// are not affected.
+func WithFairDispatch(workers int) OptionFunc {
+	return func(o *Option) {
+		o.fairWorkers = workers
+	}
+}
+
+// WithSyncDispatch delivers every event synchronously, as if it were emitted with WithSync, so the listeners
+// have run when Emit returns. Emits with WithAsync and the delivery guarantees of WithDelivery still apply.
+func WithSyncDispatch() OptionFunc {
+	return func(o *Option) {
+		o.syncDispatch = true
+	}
+}
+
+// WithPanicRecovery recovers the panics of the listeners, which then fail with an error wrapping
+// ErrListenerPanic, logged with the stack trace, instead of crashing the process.
+func WithPanicRecovery() OptionFunc {
+	return func(o *Option) {
+		o.recoverPanics = true
+	}
+}
+
+// WithTracing records the timeline of the most recent capacity events for Trace.
+func WithTracing(capacity int) OptionFunc {
+	return func(o *Option) {
+		o.traceCapacity = capacity
+	}
+}
+
+// WithMemoryLimit rejects emits with ErrMemoryLimitExceeded while the approximate number of bytes
+// held by asynchronous deliveries, ordered queues and the sink buffer is at or above the limit.
+// Rejected events are reported to their ErrorHandler.
+func WithMemoryLimit(bytes int64) OptionFunc {
+	return func(o *Option) {
+		o.memoryLimit = bytes
+	}
+}
+
+// WithProfilerLabels runs every listener under the pprof labels "eventify_event" and "eventify_listener",
+// so CPU profiles attribute time to the events and listeners that caused it.
+func WithProfilerLabels() OptionFunc {
+	return func(o *Option) {
+		o.profilerLabels = true
+	}
+}
+
+// WithMaxPayloadSize rejects events whose payload is larger than size bytes with ErrPayloadTooLarge,
+// so a single oversized payload can't be copied into every asynchronous delivery.
+// Rejected events are reported to their ErrorHandler.
+func WithMaxPayloadSize(size int) OptionFunc {
+	return func(o *Option) {
+		o.maxPayloadSize = size
+	}
+}
+
+// WithPayloadGuard is a debug mode that fingerprints the payload around every listener invocation
+// and fails the invocation with ErrPayloadMutated when the shared payload was modified.
+// It costs a hash of the payload per invocation and is meant for development and tests.
+func WithPayloadGuard() OptionFunc {
+	return func(o *Option) {
+		o.payloadGuard = true
+	}
+}
+
+// WithTypeNormalization normalizes event types and patterns with the function at Register, Unregister and Emit,
+// so mixed-case or whitespace-tainted types don't silently miss their listeners.
+// A nil function uses NormalizeType, which trims and lowercases.
+// Events are matched on their normalized type; events created by EmitBy also carry it.
+func WithTypeNormalization(normalize func(string) string) OptionFunc {
+	return func(o *Option) {
+		if normalize == nil {
+			normalize = NormalizeType
+		}
+		o.normalize = normalize
+	}
+}
+
+// WithStrictTypes rejects emits of event types that are not in the catalog with ErrUnknownEventType,
+// so typos in event types are caught instead of being silently unmatched.
+// Rejected events are reported to their ErrorHandler.
+func WithStrictTypes(catalog ...string) OptionFunc {
+	return func(o *Option) {
+		o.catalog = append(o.catalog, catalog...)
+	}
+}
+
+// WithMarshalErrorPolicy sets what EmitBy does when the payload can't be marshaled.
+// The default, also used for a nil policy, is MarshalNilPayload.
+// TryEmitBy and Producer.EmitBy always return the error instead.
+func WithMarshalErrorPolicy(policy MarshalErrorPolicy) OptionFunc {
+	return func(o *Option) {
+		if policy == nil {
+			policy = MarshalNilPayload
+		}
+		o.marshalPolicy = policy
+	}
+}
+
+// WithCompensation pairs the action event type with the event type undoing it,
+// which a failed Flow emits for every action it performed.
+func WithCompensation(action, compensation string) OptionFunc {
+	return func(o *Option) {
+		o.compensations[action] = compensation
+	}
+}
+
+// WithOutcomeEvents emits an outcome event once all the listeners of an event whose type matches
+// the pattern are done: X.succeeded if none failed, X.failed otherwise, with an Outcome payload
+// aggregating the errors, so downstream steps can chain off results.
+// Outcome events carry the correlation ID of the event and its ID as their causation ID.
+func WithOutcomeEvents(pattern string) OptionFunc {
+	return func(o *Option) {
+		o.outcomes = append(o.outcomes, pattern)
+	}
+}
+
+// WithRetryBackoff sets the delays between AtLeastOnce redeliveries. The function is called
+// for every delivery to get a fresh Backoff. By default, also for a nil function,
+// the delay doubles from 10ms up to 5s.
+func WithRetryBackoff(newBackoff func() backoff.Backoff) OptionFunc {
+	return func(o *Option) {
+		if newBackoff == nil {
+			newBackoff = defaultRetryBackoff
+		}
+		o.retryBackoff = newBackoff
+	}
+}
+
+// WithScheduler runs the asynchronous deliveries and redeliveries with the scheduler instead of
+// goroutines and timers, e.g. with a Simulation to make them deterministic in tests.
+func WithScheduler(scheduler Scheduler) OptionFunc {
+	return func(o *Option) {
+		o.scheduler = scheduler
+	}
+}
+
+// WithAsyncTypes dispatches the events whose type matches any of the patterns asynchronously,
+// as if they implemented IsAsync, so whole namespaces such as "analytics.*" don't block the emitter.
+func WithAsyncTypes(patterns ...string) OptionFunc {
+	return func(o *Option) {
+		o.asyncTypes = append(o.asyncTypes, patterns...)
+	}
+}
+
+// WithReorderWindow holds the events whose type matches the pattern for the window before delivering them
+// in order: by Sequence if they implement Sequenced, otherwise by Timestamp if they implement Timestamped,
+// otherwise as emitted. This restores the order of events bridged from several partitions that arrive
+// up to the window apart. A stream should use a single kind of order.
+// Up to 4096 events are held per pattern; beyond that, the first one in order is delivered early.
+// When several patterns match an event type, the first one configured wins.
+func WithReorderWindow(pattern string, window time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.reorders = append(o.reorders, reorderRule{pattern: pattern, window: window})
+	}
+}
+
+// WithSchemaInference infers the schema of the JSON object payloads of the event types matching any
+// of the patterns from the emitted events, and emits a SchemaDriftType event when a payload has fields
+// or field types that were never seen for its type, so producer changes are noticed before consumers break.
+// The first payload of a type sets its schema without drift. Missing fields and null values are not drift.
+// Each new field or type is reported once. Payloads that are not JSON objects are ignored.
+func WithSchemaInference(patterns ...string) OptionFunc {
+	return func(o *Option) {
+		o.schemaPatterns = append(o.schemaPatterns, patterns...)
+	}
+}
+
 // NewOption creates a new Option with the specified options.
 func NewOption(opts ...OptionFunc) *Option {
 	o := &Option{
-		log: &NoLog{},
+		log:            &NoLog{},
+		producerQuotas: map[string]producerQuota{},
+		marshalPolicy:  MarshalNilPayload,
+		compensations:  map[string]string{},
+		retryBackoff:   defaultRetryBackoff,
+		scheduler:      goScheduler{},
 	}
 	for _, opt := range opts {
 		opt(o)
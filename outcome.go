@@ -0,0 +1,103 @@
+package eventify
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// Suffixes of the outcome event types.
+const (
+	SucceededSuffix = ".succeeded"
+	FailedSuffix    = ".failed"
+)
+
+// Outcome is the payload of an outcome event.
+type Outcome struct {
+	// Type is the type of the event the outcome is about.
+	Type string `json:"type"`
+	// Listeners is the number of listeners the event was delivered to.
+	Listeners int `json:"listeners"`
+	// Errors are the errors returned by the listeners that failed.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// _TrackOutcome returns the function to call with the result of every listener of the event
+// if outcome events are enabled for its type, or nil.
+func (e *Eventify) _TrackOutcome(event Event, eventType string, listeners int) func(error) {
+	if !e._OutcomeEnabled(eventType) {
+		return nil
+	}
+	t := &outcomeTracker{eventify: e, event: event, outcome: Outcome{Type: eventType, Listeners: listeners}, remaining: listeners}
+	if listeners == 0 {
+		t.Emit()
+		return nil
+	}
+	return t.Done
+}
+
+// _OutcomeEnabled reports whether outcome events are enabled for the event type.
+// Outcome events never have outcome events themselves.
+func (e *Eventify) _OutcomeEnabled(eventType string) bool {
+	if len(e.outcomes) == 0 || strings.HasSuffix(eventType, SucceededSuffix) || strings.HasSuffix(eventType, FailedSuffix) {
+		return false
+	}
+	for _, matcher := range e.outcomes {
+		if matcher.Match(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// outcomeTracker collects the results of the listeners of an event and emits its outcome after the last one.
+type outcomeTracker struct {
+	eventify  *Eventify
+	event     Event
+	mutex     sync.Mutex
+	outcome   Outcome
+	remaining int
+}
+
+func (t *outcomeTracker) Done(err error) {
+	t.mutex.Lock()
+	if err != nil {
+		t.outcome.Errors = append(t.outcome.Errors, err.Error())
+	}
+	t.remaining--
+	last := t.remaining == 0
+	t.mutex.Unlock()
+	if last {
+		t.Emit()
+	}
+}
+
+func (t *outcomeTracker) Emit() {
+	eventType := t.outcome.Type + SucceededSuffix
+	if len(t.outcome.Errors) > 0 {
+		eventType = t.outcome.Type + FailedSuffix
+	}
+	payload, _ := json.Marshal(t.outcome)
+	outcome := &outcomeEvent{event: event{eventType: eventType, payload: payload}}
+	if correlatable, ok := t.event.(Correlatable); ok {
+		outcome.correlationID = correlatable.CorrelationID()
+	}
+	if identifiable, ok := t.event.(Identifiable); ok {
+		outcome.causationID = identifiable.ID()
+	}
+	t.eventify.Emit(outcome)
+}
+
+type outcomeEvent struct {
+	event
+	correlationID string
+	causationID   string
+}
+
+func (e *outcomeEvent) CorrelationID() string {
+	return e.correlationID
+}
+
+func (e *outcomeEvent) CausationID() string {
+	return e.causationID
+}
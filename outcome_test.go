@@ -0,0 +1,69 @@
+package eventify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_OutcomeEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		listeners []Listener
+		wantType  string
+		want      Outcome
+	}{
+		{
+			name:      "succeeded",
+			listeners: []Listener{NewListener(nil), &asyncListener{handle: func(Event) error { return nil }}},
+			wantType:  "order.created.succeeded",
+			want:      Outcome{Type: "order.created", Listeners: 2},
+		},
+		{
+			name: "failed",
+			listeners: []Listener{
+				NewListener(func(Event) error { return assert.AnError }),
+				&asyncListener{handle: func(Event) error { return nil }},
+			},
+			wantType: "order.created.failed",
+			want:     Outcome{Type: "order.created", Listeners: 2, Errors: []string{assert.AnError.Error()}},
+		},
+		{
+			name:     "no listeners",
+			wantType: "order.created.succeeded",
+			want:     Outcome{Type: "order.created"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEventify(WithOutcomeEvents("order.*"))
+			for _, listener := range tt.listeners {
+				e.Register("order.created", listener)
+			}
+			outcomes := make(chan Event, 4)
+			e.Register("order.created.*", NewListener(func(event Event) error {
+				outcomes <- event
+				return nil
+			}))
+
+			e.EmitBy("order.created", nil)
+
+			select {
+			case event := <-outcomes:
+				assert.Equal(t, tt.wantType, event.Type())
+				got, err := DecodedPayload[Outcome](event)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			case <-time.After(time.Second):
+				t.Fatal("outcome not emitted")
+			}
+			select {
+			case event := <-outcomes:
+				t.Fatalf("unexpected outcome %s", event.Type())
+			case <-time.After(10 * time.Millisecond):
+			}
+		})
+	}
+}
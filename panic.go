@@ -0,0 +1,37 @@
+package eventify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrListenerPanic is returned, wrapping the panic value, by the listeners that panicked, see WithPanicRecovery.
+var ErrListenerPanic = errors.New("eventify: listener panicked")
+
+// AppPanic is the type of the events emitted by CapturePanics.
+const AppPanic = "app.panic"
+
+// Panic is the payload of an AppPanic event.
+type Panic struct {
+	// Value is the value the code panicked with, formatted with %v.
+	Value string `json:"value"`
+	Stack string `json:"stack"`
+}
+
+// CapturePanics recovers a panic and emits it as an AppPanic event with its stack trace,
+// so alerting listeners handle panics uniformly. It must be deferred directly:
+//
+//	go func() {
+//		defer eventify.CapturePanics(e)
+//		work()
+//	}()
+//
+// The panic is not propagated; recovery middleware should write its error response after it.
+func CapturePanics(e *Eventify) {
+	if p := recover(); p != nil {
+		payload, _ := json.Marshal(Panic{Value: fmt.Sprint(p), Stack: string(debug.Stack())})
+		e.Emit(NewEvent(AppPanic, payload))
+	}
+}
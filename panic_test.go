@@ -0,0 +1,44 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapturePanics(t *testing.T) {
+	e := New()
+	panics := make(chan Panic, 1)
+	e.Register(AppPanic, NewListener(func(event Event) error {
+		p, err := DecodedPayload[Panic](event)
+		panics <- p
+		return err
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer CapturePanics(e)
+		panic("boom")
+	}()
+	<-done
+
+	p := <-panics
+	assert.Equal(t, "boom", p.Value)
+	assert.Contains(t, p.Stack, "panic_test.go")
+}
+
+func TestCapturePanics_NoPanic(t *testing.T) {
+	e := New()
+	called := false
+	e.Register(AppPanic, NewListener(func(Event) error {
+		called = true
+		return nil
+	}))
+
+	require.NotPanics(t, func() {
+		defer CapturePanics(e)
+	})
+	assert.False(t, called)
+}
@@ -0,0 +1,16 @@
+package eventify
+
+import (
+	"errors"
+	"hash/maphash"
+)
+
+// ErrPayloadMutated is reported by the payload guard when a listener modified the shared payload.
+var ErrPayloadMutated = errors.New("eventify: payload mutated")
+
+var payloadSeed = maphash.MakeSeed()
+
+// payloadFingerprint returns a hash of the payload used by the payload guard to detect mutations.
+func payloadFingerprint(payload []byte) uint64 {
+	return maphash.Bytes(payloadSeed, payload)
+}
@@ -0,0 +1,44 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayloadCopy(t *testing.T) {
+	event := NewEvent("doc.saved", []byte("original"))
+
+	cp := PayloadCopy(event)
+	cp[0] = 'O'
+
+	assert.Equal(t, "original", string(event.Payload()))
+}
+
+func TestEventify_PayloadGuard(t *testing.T) {
+	e := NewEventify(WithPayloadGuard())
+	e.Register("doc.saved", NewNamedListener("reader", func(event Event) error {
+		return nil
+	}))
+	e.Register("doc.saved", NewNamedListener("mutator", func(event Event) error {
+		event.Payload()[0] = 'X'
+		return nil
+	}))
+	errChan := make(chan error, 2)
+
+	e.Emit(&guardedEvent{Event: NewEvent("doc.saved", []byte("payload")), errChan: errChan})
+
+	err := <-errChan
+	assert.ErrorIs(t, err, ErrPayloadMutated)
+	assert.Contains(t, err.Error(), "mutator")
+	assert.Empty(t, errChan, "only the mutating listener should be flagged")
+}
+
+type guardedEvent struct {
+	Event
+	errChan chan error
+}
+
+func (g *guardedEvent) ErrorHandler(_ Event, err error) {
+	g.errChan <- err
+}
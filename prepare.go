@@ -0,0 +1,19 @@
+package eventify
+
+import "sync"
+
+// Prepare stages the event during a business transaction: commit releases it to the listeners,
+// like Emit, once the transaction has committed, and abort discards it if it rolled back.
+// Only the first call of either function has an effect.
+func (e *Eventify) Prepare(event Event) (commit func(), abort func()) {
+	var once sync.Once
+	commit = func() {
+		once.Do(func() { e._Emit(event) })
+	}
+	abort = func() {
+		once.Do(func() {
+			withEventFields(e.log, event, nil).Debug("eventify prepared event aborted", "event", event.Type())
+		})
+	}
+	return commit, abort
+}
@@ -0,0 +1,28 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_Prepare(t *testing.T) {
+	e := New()
+	var received []string
+	e.Register("order.*", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+
+	commit, abort := e.Prepare(NewEvent("order.created", nil))
+	assert.Empty(t, received, "prepared events wait for the commit")
+	commit()
+	commit()
+	abort()
+	assert.Equal(t, []string{"order.created"}, received)
+
+	commit, abort = e.Prepare(NewEvent("order.deleted", nil))
+	abort()
+	commit()
+	assert.Equal(t, []string{"order.created"}, received)
+}
@@ -0,0 +1,48 @@
+package eventify
+
+import (
+	"log/slog"
+	"os"
+)
+
+// Limits set by ProdDefaults.
+const (
+	prodMaxPayloadSize = 1 << 20
+	prodMemoryLimit    = 256 << 20
+	prodWorkers        = 256
+	devTraceCapacity   = 1024
+)
+
+// DevDefaults returns the recommended options for development and tests, which surface bugs early:
+// events are delivered synchronously, every log line is written to stderr, payloads are guarded,
+// payloads that can't be marshaled panic, and the last 1024 events are traced. With a catalog,
+// the types outside of it are rejected, see WithStrictTypes.
+// Options passed after it override it, e.g. NewEventify(DevDefaults(), WithLogger(log)).
+func DevDefaults(catalog ...string) OptionFunc {
+	return func(o *Option) {
+		WithSyncDispatch()(o)
+		WithLogger(NewSlogLog(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))))(o)
+		WithPayloadGuard()(o)
+		WithMarshalErrorPolicy(MarshalPanic)(o)
+		WithTracing(devTraceCapacity)(o)
+		if len(catalog) > 0 {
+			WithStrictTypes(catalog...)(o)
+		}
+	}
+}
+
+// ProdDefaults returns the recommended options for production, which protect the process:
+// listener panics are recovered, asynchronous deliveries run on a pool of 256 workers shared
+// fairly between producers, payloads are limited to 1MiB, emits are shed above 256MiB held by the instance,
+// and events whose payload can't be marshaled are dropped instead of emitted empty.
+// The delivery counts and listener statistics, see DeliveryCounts and Stats, are always kept.
+// Options passed after it override it.
+func ProdDefaults() OptionFunc {
+	return func(o *Option) {
+		WithPanicRecovery()(o)
+		WithFairDispatch(prodWorkers)(o)
+		WithMaxPayloadSize(prodMaxPayloadSize)(o)
+		WithMemoryLimit(prodMemoryLimit)(o)
+		WithMarshalErrorPolicy(MarshalDrop)(o)
+	}
+}
@@ -0,0 +1,50 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	dev := NewOption(DevDefaults())
+	assert.True(t, dev.payloadGuard)
+	assert.True(t, dev.syncDispatch)
+	assert.Equal(t, devTraceCapacity, dev.traceCapacity)
+	assert.Empty(t, dev.catalog)
+	assert.Panics(t, func() { NewEventify(DevDefaults(), WithLogger(&NoLog{})).EmitBy("bad", make(chan int)) })
+	strict := NewEventify(DevDefaults("order.created"), WithLogger(&NoLog{}))
+	assert.ErrorIs(t, strict.TryEmitBy("order.typo", nil), ErrUnknownEventType)
+
+	prod := NewOption(ProdDefaults(), WithMaxPayloadSize(10))
+	assert.Equal(t, 10, prod.maxPayloadSize, "later options should override the preset")
+	assert.Equal(t, int64(prodMemoryLimit), prod.memoryLimit)
+	assert.True(t, prod.recoverPanics)
+	assert.Equal(t, prodWorkers, prod.fairWorkers)
+	assert.NotPanics(t, func() { NewEventify(ProdDefaults()).EmitBy("bad", make(chan int)) })
+}
+
+func TestEventify_WithSyncDispatch(t *testing.T) {
+	e := NewEventify(WithSyncDispatch(), WithAsyncTypes("order.*"))
+	handled := false
+	e.Register("order.created", &asyncListener{handle: func(Event) error {
+		handled = true
+		return nil
+	}})
+
+	e.EmitBy("order.created", nil)
+	assert.True(t, handled)
+}
+
+func TestEventify_WithPanicRecovery(t *testing.T) {
+	e := NewEventify(WithPanicRecovery())
+	var reports []DeliveryReport
+	e.OnDelivery(func(report DeliveryReport) { reports = append(reports, report) })
+	e.Register("order.created", NewListener(func(Event) error { panic("boom") }))
+
+	assert.NotPanics(t, func() { e.EmitBy("order.created", nil) })
+	if assert.Len(t, reports, 1) {
+		assert.ErrorIs(t, reports[0].Err, ErrListenerPanic)
+		assert.ErrorContains(t, reports[0].Err, "boom")
+	}
+}
@@ -0,0 +1,144 @@
+package eventify
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a producer emits more events than its quota allows.
+var ErrQuotaExceeded = errors.New("eventify: producer quota exceeded")
+
+// Producer is a named source of events.
+// Producers let one instance enforce per-module quotas, so a chatty module can't starve the others.
+// With WithFairDispatch, the asynchronous deliveries of the producers are also run round-robin.
+type Producer struct {
+	name     string
+	eventify *Eventify
+	bucket   atomic.Pointer[tokenBucket]
+	emitted  atomic.Uint64
+	rejected atomic.Uint64
+}
+
+// ProducerStats are the counters of a producer.
+type ProducerStats struct {
+	Emitted  uint64
+	Rejected uint64
+	// Queued is the number of asynchronous deliveries waiting for a worker, see WithFairDispatch.
+	Queued int
+}
+
+// Producer returns the producer with the name, creating it on first use.
+// Its quota is set with WithProducerQuota or SetProducerQuota.
+func (e *Eventify) Producer(name string) *Producer {
+	if p, ok := e.producers.Load(name); ok {
+		return p.(*Producer)
+	}
+	p := &Producer{name: name, eventify: e}
+	if quota, ok := e.producerQuotas[name]; ok {
+		p.bucket.Store(newTokenBucket(quota.rate, quota.burst))
+	}
+	actual, _ := e.producers.LoadOrStore(name, p)
+	return actual.(*Producer)
+}
+
+// SetProducerQuota changes the quota of the producer with the name, like WithProducerQuota,
+// at runtime. The new quota starts with a full burst. A rate of 0 or less removes the quota.
+// This method is thread-safe.
+func (e *Eventify) SetProducerQuota(name string, rate float64, burst int) {
+	p := e.Producer(name)
+	if rate <= 0 {
+		p.bucket.Store(nil)
+	} else {
+		p.bucket.Store(newTokenBucket(rate, burst))
+	}
+	e.log.Debug("eventify producer quota set", "producer", name, "rate", rate, "burst", burst)
+}
+
+// ProducerStats returns the counters of every producer by name.
+func (e *Eventify) ProducerStats() map[string]ProducerStats {
+	stats := map[string]ProducerStats{}
+	e.producers.Range(func(key, value any) bool {
+		p := value.(*Producer)
+		s := ProducerStats{Emitted: p.emitted.Load(), Rejected: p.rejected.Load()}
+		if e.fair != nil {
+			s.Queued = e.fair.Len(p.name)
+		}
+		stats[key.(string)] = s
+		return true
+	})
+	return stats
+}
+
+// Name returns the name of the producer.
+func (p *Producer) Name() string {
+	return p.name
+}
+
+// Emit emits the event unless the producer is over its quota.
+// It returns ErrQuotaExceeded or the error the event was rejected with.
+func (p *Producer) Emit(event Event) error {
+	if !p.bucket.Load().Take() {
+		p.rejected.Add(1)
+		p.eventify.log.Debug("eventify producer quota exceeded", "producer", p.name, "event", event.Type(), "status", StatusDroppedOverflow)
+		p.eventify.ReportDelivery(event, nil, StatusDroppedOverflow, ErrQuotaExceeded)
+		return ErrQuotaExceeded
+	}
+	p.emitted.Add(1)
+	return p.eventify._Emit(event, func(c *emitConfig) { c.producer = p.name })
+}
+
+// EmitBy creates and emits a new event unless the producer is over its quota.
+// A payload that can't be marshaled returns an error wrapping ErrMarshalPayload.
+func (p *Producer) EmitBy(eventType string, payload any) error {
+	if event, ok := payload.(Event); ok {
+		return p.Emit(event)
+	}
+	bz, err := p.eventify._AnyToBytes(payload)
+	if err != nil {
+		return err
+	}
+	return p.Emit(NewEvent(p.eventify._Normalize(eventType), bz))
+}
+
+type producerQuota struct {
+	rate  float64
+	burst int
+}
+
+// tokenBucket allows rate events per second on average with bursts of up to burst events.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Take takes a token and reports whether one was available.
+// A nil bucket always has tokens.
+func (b *tokenBucket) Take() bool {
+	if b == nil {
+		return true
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,79 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProducer_Quota(t *testing.T) {
+	e := NewEventify(WithProducerQuota("chatty", 0.001, 2))
+	var received int
+	e.Register("*", NewListener(func(event Event) error {
+		received++
+		return nil
+	}))
+
+	chatty := e.Producer("chatty")
+	require.NoError(t, chatty.EmitBy("log.line", "1"))
+	require.NoError(t, chatty.EmitBy("log.line", "2"))
+	assert.ErrorIs(t, chatty.EmitBy("log.line", "3"), ErrQuotaExceeded)
+
+	quiet := e.Producer("quiet")
+	for i := 0; i < 5; i++ {
+		require.NoError(t, quiet.EmitBy("order.created", nil))
+	}
+
+	assert.Equal(t, 7, received)
+	assert.Same(t, chatty, e.Producer("chatty"))
+	assert.Equal(t, map[string]ProducerStats{
+		"chatty": {Emitted: 2, Rejected: 1},
+		"quiet":  {Emitted: 5},
+	}, e.ProducerStats())
+}
+
+func TestEventify_SetProducerQuota(t *testing.T) {
+	e := NewEventify(WithProducerQuota("chatty", 0.001, 1))
+	chatty := e.Producer("chatty")
+	require.NoError(t, chatty.EmitBy("log.line", "1"))
+	assert.ErrorIs(t, chatty.EmitBy("log.line", "2"), ErrQuotaExceeded)
+
+	e.SetProducerQuota("chatty", 0.001, 2)
+	require.NoError(t, chatty.EmitBy("log.line", "3"))
+	require.NoError(t, chatty.EmitBy("log.line", "4"))
+	assert.ErrorIs(t, chatty.EmitBy("log.line", "5"), ErrQuotaExceeded)
+
+	e.SetProducerQuota("chatty", 0, 0)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, chatty.EmitBy("log.line", i))
+	}
+
+	e.SetProducerQuota("new", 0.001, 1)
+	require.NoError(t, e.Producer("new").EmitBy("log.line", "1"))
+	assert.ErrorIs(t, e.Producer("new").EmitBy("log.line", "2"), ErrQuotaExceeded)
+}
+
+func TestEventify_WithFairDispatch(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim), WithAsyncTypes("job.*"), WithFairDispatch(1))
+	var received []string
+	e.Register("job.*", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+
+	chatty, quiet := e.Producer("chatty"), e.Producer("quiet")
+	for range 3 {
+		require.NoError(t, chatty.EmitBy("job.chatty", nil))
+	}
+	require.NoError(t, quiet.EmitBy("job.quiet", nil))
+	e.EmitBy("job.other", nil)
+	assert.Equal(t, 3, e.ProducerStats()["chatty"].Queued)
+
+	sim.Run()
+	assert.Equal(t, []string{"job.chatty", "job.quiet", "job.other", "job.chatty", "job.chatty"}, received)
+	assert.Zero(t, e.ProducerStats()["chatty"].Queued)
+	assert.NoError(t, e.Drain(context.Background()))
+}
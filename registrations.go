@@ -0,0 +1,48 @@
+package eventify
+
+import (
+	"slices"
+	"sort"
+)
+
+// Registration describes a listener registered for a pattern.
+type Registration struct {
+	Pattern string
+	// Listener is the name of the listener if it implements Namable, otherwise its type.
+	Listener string
+	// Labels are the labels attached to the listener with WithLabels.
+	Labels []string
+	// Paused reports whether one of the labels is paused with PauseByLabel.
+	Paused bool
+}
+
+// Registrations returns the registered listeners, sorted by pattern then listener,
+// including those never invoked, unlike Stats.
+// This method is thread-safe.
+func (e *Eventify) Registrations() []Registration {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	registrations := []Registration{}
+	e.listeners.Range(func(key, value any) bool {
+		for _, listener := range value.([]Listener) {
+			var labels []string
+			if k := statsKey(listener); k != nil {
+				labels = slices.Clone(e.labels[k])
+			}
+			registrations = append(registrations, Registration{
+				Pattern:  key.(string),
+				Listener: listenerLabel(listener),
+				Labels:   labels,
+				Paused:   e._Paused(listener),
+			})
+		}
+		return true
+	})
+	sort.SliceStable(registrations, func(i, j int) bool {
+		if registrations[i].Pattern != registrations[j].Pattern {
+			return registrations[i].Pattern < registrations[j].Pattern
+		}
+		return registrations[i].Listener < registrations[j].Listener
+	})
+	return registrations
+}
@@ -0,0 +1,23 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_Registrations(t *testing.T) {
+	e := New()
+	assert.Empty(t, e.Registrations())
+
+	e.Register("order.*", NewNamedListener("billing", nil), WithLabels("team=payments"))
+	e.Register("order.*", NewNamedListener("audit", nil))
+	e.Register("user.created", NewNamedListener("welcome", nil), WithLabels("team=growth"))
+	e.PauseByLabel("team=growth")
+
+	assert.Equal(t, []Registration{
+		{Pattern: "order.*", Listener: "audit"},
+		{Pattern: "order.*", Listener: "billing", Labels: []string{"team=payments"}},
+		{Pattern: "user.created", Listener: "welcome", Labels: []string{"team=growth"}, Paused: true},
+	}, e.Registrations())
+}
@@ -0,0 +1,118 @@
+package eventify
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// reorderCapacity is the maximum number of events held by a reordering window.
+const reorderCapacity = 4096
+
+// Timestamped is an interface that can be implemented by events to expose when they occurred,
+// which can differ from when they are emitted, e.g. when bridged from several partitions.
+type Timestamped interface {
+	Timestamp() time.Time
+}
+
+type reorderRule struct {
+	pattern string
+	window  time.Duration
+}
+
+// reorderer holds the events of a pattern for its window and delivers them in order.
+type reorderer struct {
+	eventify *Eventify
+	matcher  *Matcher
+	window   time.Duration
+	mutex    sync.Mutex
+	held     []*heldEvent
+	arrivals uint64
+	// delivering serializes the deliveries, so released events reach the listeners in order.
+	delivering sync.Mutex
+}
+
+// heldEvent is an event held by a reorderer with the function delivering it.
+type heldEvent struct {
+	deliver func()
+	size    int64
+	// kind is 0 for events ordered by sequence number and 1 for events ordered by time.
+	kind    int
+	seq     uint64
+	at      time.Time
+	arrival uint64
+	expiry  time.Time
+}
+
+func (h *heldEvent) before(other *heldEvent) bool {
+	if h.kind != other.kind {
+		return h.kind < other.kind
+	}
+	if h.kind == 0 && h.seq != other.seq {
+		return h.seq < other.seq
+	}
+	if h.kind == 1 && !h.at.Equal(other.at) {
+		return h.at.Before(other.at)
+	}
+	return h.arrival < other.arrival
+}
+
+// _Reorderer returns the reorderer of the first reordering window matching the event type, or nil.
+func (e *Eventify) _Reorderer(eventType string) *reorderer {
+	for _, r := range e.reorderers {
+		if r.matcher.Match(eventType) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Hold holds the event until its window has passed and the events before it in order were delivered.
+// When the reorderer is full, the first event in order is delivered early.
+func (r *reorderer) Hold(event Event, deliver func()) {
+	now := r.eventify._Now()
+	h := &heldEvent{deliver: deliver, size: eventSize(event), kind: 1, at: now, expiry: now.Add(r.window)}
+	if sequenced, ok := event.(Sequenced); ok {
+		h.kind = 0
+		h.seq = sequenced.Sequence()
+	} else if timestamped, ok := event.(Timestamped); ok {
+		h.at = timestamped.Timestamp()
+	}
+	r.eventify.memory.queued.Add(h.size)
+
+	r.mutex.Lock()
+	r.arrivals++
+	h.arrival = r.arrivals
+	i := sort.Search(len(r.held), func(i int) bool { return h.before(r.held[i]) })
+	r.held = append(r.held, nil)
+	copy(r.held[i+1:], r.held[i:])
+	r.held[i] = h
+	overflow := len(r.held) > reorderCapacity
+	r.mutex.Unlock()
+
+	if overflow {
+		r.eventify.scheduler.Go(func() { r._Release(true) })
+	}
+	r.eventify.scheduler.AfterFunc(r.window, func() { r._Release(false) })
+}
+
+// _Release delivers, in order, the first held events whose window has passed,
+// and the first one regardless if forced.
+func (r *reorderer) _Release(force bool) {
+	r.delivering.Lock()
+	defer r.delivering.Unlock()
+	r.mutex.Lock()
+	now := r.eventify._Now()
+	var released []*heldEvent
+	for len(r.held) > 0 && (force || !r.held[0].expiry.After(now)) {
+		released = append(released, r.held[0])
+		r.held = r.held[1:]
+		force = false
+	}
+	r.mutex.Unlock()
+
+	for _, h := range released {
+		r.eventify.memory.queued.Add(-h.size)
+		h.deliver()
+	}
+}
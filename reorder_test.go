@@ -0,0 +1,68 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timestampedEvent struct {
+	Event
+	at time.Time
+}
+
+func (t *timestampedEvent) Timestamp() time.Time { return t.at }
+
+func TestEventify_WithReorderWindow(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim), WithReorderWindow("trade.*", 2*time.Second))
+	var got []string
+	e.Register("*", NewListener(func(event Event) error {
+		switch ev := event.(type) {
+		case *sequencedEvent:
+			got = append(got, ev.key)
+		case *timestampedEvent:
+			got = append(got, ev.at.Format("15:04"))
+		default:
+			got = append(got, event.Type())
+		}
+		return nil
+	}))
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, n := range []uint64{3, 1, 2} {
+		e.Emit(&sequencedEvent{Event: NewEvent("trade.executed", nil), key: string(rune('a' + n - 1)), sequence: n})
+	}
+	e.Emit(&timestampedEvent{Event: NewEvent("trade.quoted", nil), at: base.Add(10 * time.Minute)})
+	e.Emit(&timestampedEvent{Event: NewEvent("trade.quoted", nil), at: base.Add(5 * time.Minute)})
+	sim.AfterFunc(time.Second, func() {
+		e.Emit(&timestampedEvent{Event: NewEvent("trade.quoted", nil), at: base.Add(7 * time.Minute)})
+	})
+	e.EmitBy("user.created", nil)
+	assert.Equal(t, []string{"user.created"}, got)
+
+	sim.Run()
+	assert.Equal(t, []string{"user.created", "a", "b", "c", "12:05", "12:07", "12:10"}, got)
+	assert.Equal(t, int64(0), e.memory.queued.Load())
+}
+
+func TestEventify_DrainWaitsForHeldEvents(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim), WithReorderWindow("trade.*", 2*time.Second))
+	delivered := 0
+	e.Register("trade.*", NewListener(func(Event) error {
+		delivered++
+		return nil
+	}))
+
+	e.EmitBy("trade.executed", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, e.Drain(ctx), context.DeadlineExceeded, "the held event should be waited for")
+
+	sim.Run()
+	assert.Equal(t, 1, delivered)
+	assert.NoError(t, e.Drain(context.Background()))
+}
@@ -0,0 +1,80 @@
+package eventify
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Resources holds the shared dependencies of the listeners of a bus, such as database pools or clients,
+// keyed by their type.
+type Resources struct {
+	values sync.Map
+}
+
+type resourcesKey struct{}
+
+// ContextListener is a listener receiving a context with the resources provided to the bus,
+// which Resolve looks up. The bus calls HandleCtx instead of Handle.
+type ContextListener interface {
+	Listener
+	HandleCtx(ctx context.Context, event Event) error
+}
+
+// NewContextListener creates a new ContextListener with the specified handle function.
+// Called directly, its Handle method runs the function with a background context.
+func NewContextListener(handle func(ctx context.Context, event Event) error) Listener {
+	if handle == nil {
+		handle = func(context.Context, Event) error { return nil }
+	}
+	return &contextListener{handle: handle}
+}
+
+type contextListener struct {
+	handle func(ctx context.Context, event Event) error
+}
+
+func (l *contextListener) Handle(event Event) error {
+	return l.handle(context.Background(), event)
+}
+
+func (l *contextListener) HandleCtx(ctx context.Context, event Event) error {
+	return l.handle(ctx, event)
+}
+
+// Provide adds the values to the resources of the bus, keyed by their dynamic type,
+// replacing the values of the same type provided before.
+// This method is thread-safe.
+func (e *Eventify) Provide(values ...any) {
+	for _, value := range values {
+		e.resources.values.Store(reflect.TypeOf(value), value)
+	}
+}
+
+// ResourceContext returns a copy of the context carrying the resources of the bus, for code outside listeners.
+func (e *Eventify) ResourceContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resourcesKey{}, &e.resources)
+}
+
+// Resolve returns the resource of type T carried by the context, as passed to ContextListener.HandleCtx
+// and Initializable.Init. A T that is an interface resolves to one of the provided values implementing it.
+// It reports false if there is none.
+func Resolve[T any](ctx context.Context) (T, bool) {
+	var zero T
+	resources, ok := ctx.Value(resourcesKey{}).(*Resources)
+	if !ok {
+		return zero, false
+	}
+	if value, ok := resources.values.Load(reflect.TypeFor[T]()); ok {
+		return value.(T), true
+	}
+	if reflect.TypeFor[T]().Kind() != reflect.Interface {
+		return zero, false
+	}
+	found := zero
+	resources.values.Range(func(_, value any) bool {
+		found, ok = value.(T)
+		return !ok
+	})
+	return found, ok
+}
@@ -0,0 +1,51 @@
+package eventify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPool struct{ dsn string }
+
+func (p *testPool) String() string { return p.dsn }
+
+func TestResolve(t *testing.T) {
+	e := NewEventify()
+	e.Provide(&testPool{dsn: "old"}, &testPool{dsn: "postgres://"}, 42)
+	ctx := e.ResourceContext(context.Background())
+
+	pool, ok := Resolve[*testPool](ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "postgres://", pool.dsn)
+
+	stringer, ok := Resolve[fmt.Stringer](ctx)
+	assert.True(t, ok)
+	assert.Equal(t, pool, stringer)
+
+	number, ok := Resolve[int](ctx)
+	assert.True(t, ok)
+	assert.Equal(t, 42, number)
+
+	_, ok = Resolve[string](ctx)
+	assert.False(t, ok)
+	_, ok = Resolve[*testPool](context.Background())
+	assert.False(t, ok)
+}
+
+func TestEventify_ContextListener(t *testing.T) {
+	e := NewEventify(WithProfilerLabels())
+	pool := &testPool{dsn: "postgres://"}
+	e.Provide(pool)
+	var resolved *testPool
+	e.Register("user.created", NewContextListener(func(ctx context.Context, event Event) error {
+		resolved, _ = Resolve[*testPool](ctx)
+		return nil
+	}))
+
+	e.EmitBy("user.created", nil)
+
+	assert.Same(t, pool, resolved)
+}
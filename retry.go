@@ -0,0 +1,61 @@
+package eventify
+
+import "errors"
+
+// Terminal marks a listener error as not worth retrying, such as a validation failure:
+// AtLeastOnce delivery reports it to the ErrorHandler right away instead of redelivering.
+// A nil error stays nil.
+func Terminal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &terminalError{err: err}
+}
+
+// Retryable marks a listener error as transient, such as a network error, worth redelivering.
+// Unmarked errors are retried too; the mark states the intent and overrides a Terminal error it wraps.
+// A nil error stays nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err}
+}
+
+// IsTerminal reports whether the error is marked Terminal, and not re-marked Retryable on top.
+func IsTerminal(err error) bool {
+	for err != nil {
+		switch err.(type) {
+		case *terminalError:
+			return true
+		case *retryableError:
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+type terminalError struct {
+	err error
+}
+
+func (e *terminalError) Error() string {
+	return e.err.Error()
+}
+
+func (e *terminalError) Unwrap() error {
+	return e.err
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
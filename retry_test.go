@@ -0,0 +1,75 @@
+package eventify
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTerminal(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"unmarked", assert.AnError, false},
+		{"terminal", Terminal(assert.AnError), true},
+		{"wrapped terminal", fmt.Errorf("validate: %w", Terminal(assert.AnError)), true},
+		{"retryable", Retryable(assert.AnError), false},
+		{"retryable over terminal", Retryable(Terminal(assert.AnError)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTerminal(tt.err))
+		})
+	}
+	assert.NoError(t, Terminal(nil))
+	assert.NoError(t, Retryable(nil))
+	assert.ErrorIs(t, Terminal(assert.AnError), assert.AnError)
+}
+
+func TestEventify_DeliveryAtLeastOnceTerminal(t *testing.T) {
+	e := NewEventify(WithDelivery("error.*", AtLeastOnce))
+	var attempts atomic.Int32
+	e.Register("error.event", NewListener(func(event Event) error {
+		attempts.Add(1)
+		return Terminal(assert.AnError)
+	}))
+	errChan := make(chan error, 1)
+
+	e.Emit(&mockErrorEvent{errChan: errChan})
+
+	select {
+	case err := <-errChan:
+		assert.ErrorIs(t, err, assert.AnError)
+	case <-time.After(time.Second):
+		t.Fatal("error not reported")
+	}
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestEventify_WithRetryBackoff(t *testing.T) {
+	e := NewEventify(WithDelivery("error.*", AtLeastOnce), WithRetryBackoff(func() backoff.Backoff {
+		return backoff.Constant(0)
+	}))
+	var attempts atomic.Int32
+	e.Register("error.event", NewListener(func(event Event) error {
+		attempts.Add(1)
+		return assert.AnError
+	}))
+	errChan := make(chan error, 1)
+
+	e.Emit(&mockErrorEvent{errChan: errChan})
+
+	select {
+	case <-errChan:
+		assert.Equal(t, int32(atLeastOnceAttempts), attempts.Load())
+	case <-time.After(time.Second):
+		t.Fatal("error not reported")
+	}
+}
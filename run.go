@@ -0,0 +1,77 @@
+package eventify
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Module is a long-running part of an application built around the bus, such as a transport,
+// a scheduler or a projection, started by Run.
+type Module interface {
+	// Run runs the module until the context is done or the module fails.
+	// It returns nil or the context's error when stopped by the context.
+	Run(ctx context.Context) error
+}
+
+// ModuleFunc is a function implementing Module.
+type ModuleFunc func(ctx context.Context) error
+
+// Run calls f.
+func (f ModuleFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Mount adds modules to be started by Run.
+// This method is thread-safe, but modules mounted after Run started are not run.
+func (e *Eventify) Mount(modules ...Module) {
+	e.modulesMutex.Lock()
+	defer e.modulesMutex.Unlock()
+	e.modules = append(e.modules, modules...)
+}
+
+// Run warms up the listeners with WarmUp, returning its error if any failed,
+// then starts all the mounted modules and blocks until the context is done or a module fails,
+// then stops the other modules and waits for them. It returns the first module error,
+// or nil when stopped by the context, so it slots into an errgroup.Group or a server's lifecycle.
+// Once the modules are stopped, so is the bus: the events emitted from then on are rejected with ErrStopped.
+// Run then waits for the deliveries under way, closes the registered listeners implementing Closable,
+// logging their errors, and writes the records queued for the sink, see WithEventSink, before returning.
+func (e *Eventify) Run(ctx context.Context) error {
+	if err := e.WarmUp(ctx); err != nil {
+		return err
+	}
+	e.modulesMutex.Lock()
+	modules := append([]Module{}, e.modules...)
+	e.modulesMutex.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	var once sync.Once
+	var first error
+	for _, module := range modules {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := module.Run(ctx)
+			if err != nil && !(ctx.Err() != nil && errors.Is(err, ctx.Err())) {
+				once.Do(func() { first = err })
+				e.log.Debug("eventify module failed", "error", err)
+			}
+			cancel()
+		}()
+	}
+	<-ctx.Done()
+	wg.Wait()
+	// The bus is stopped first, so no event reaches the listeners once they are closed.
+	e.stopped.Store(true)
+	shutdown := context.WithoutCancel(ctx)
+	if err := e._CloseAll(shutdown); err != nil {
+		e.log.Debug("eventify listeners close failed", "error", err)
+	}
+	if e.sink != nil {
+		e.sink.Close(shutdown)
+	}
+	return first
+}
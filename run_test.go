@@ -0,0 +1,66 @@
+package eventify
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_Run(t *testing.T) {
+	e := New()
+	var stopped atomic.Int32
+	waitForStop := ModuleFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped.Add(1)
+		return ctx.Err()
+	})
+	e.Mount(waitForStop, waitForStop)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- e.Run(ctx) }()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), stopped.Load())
+	case <-time.After(time.Second):
+		t.Fatal("run not stopped")
+	}
+}
+
+func TestEventify_RunModuleFails(t *testing.T) {
+	e := New()
+	var stopped atomic.Bool
+	e.Mount(
+		ModuleFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			stopped.Store(true)
+			return nil
+		}),
+		ModuleFunc(func(ctx context.Context) error {
+			return assert.AnError
+		}),
+	)
+
+	err := e.Run(context.Background())
+
+	assert.Equal(t, assert.AnError, err)
+	assert.True(t, stopped.Load(), "the other modules should be stopped")
+}
+
+func TestEventify_RunDeadline(t *testing.T) {
+	e := New()
+	e.Mount(ModuleFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, e.Run(ctx))
+}
@@ -0,0 +1,109 @@
+package eventify
+
+import (
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Scheduler runs the asynchronous work of an Eventify instance.
+type Scheduler interface {
+	// Go runs the task asynchronously.
+	Go(task func())
+	// AfterFunc runs the task asynchronously once the duration has passed.
+	AfterFunc(d time.Duration, task func())
+}
+
+// goScheduler is the default Scheduler, running tasks in goroutines.
+type goScheduler struct{}
+
+func (goScheduler) Go(task func()) {
+	go task()
+}
+
+func (goScheduler) AfterFunc(d time.Duration, task func()) {
+	time.AfterFunc(d, task)
+}
+
+// Simulation is a deterministic Scheduler for property tests of asynchronous flows such as retries and sagas.
+// Tasks only run when the test calls Step or Run, one at a time on the test's goroutine, in an order
+// chosen by a random source seeded with the seed, and timers fire on a virtual clock, so a failure
+// is reproduced exactly by running again with the same seed.
+type Simulation struct {
+	mutex  sync.Mutex
+	rand   *rand.Rand
+	now    time.Time
+	tasks  []func()
+	timers []simulatedTimer
+	seq    uint64
+}
+
+type simulatedTimer struct {
+	at   time.Time
+	seq  uint64
+	task func()
+}
+
+// NewSimulation creates a new Simulation with the seed. Its virtual clock starts at the Unix epoch.
+func NewSimulation(seed uint64) *Simulation {
+	return &Simulation{rand: rand.New(rand.NewPCG(seed, seed)), now: time.Unix(0, 0)}
+}
+
+// Go queues the task.
+func (s *Simulation) Go(task func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tasks = append(s.tasks, task)
+}
+
+// AfterFunc queues the task once the virtual clock has advanced by the duration.
+func (s *Simulation) AfterFunc(d time.Duration, task func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.seq++
+	s.timers = append(s.timers, simulatedTimer{at: s.now.Add(d), seq: s.seq, task: task})
+}
+
+// Now returns the virtual time.
+func (s *Simulation) Now() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.now
+}
+
+// Step runs one queued task, picked at random. If none is queued, it advances the virtual clock
+// to the earliest timer and queues its task instead. It reports whether there was anything to do.
+func (s *Simulation) Step() bool {
+	s.mutex.Lock()
+	if len(s.tasks) == 0 {
+		if len(s.timers) == 0 {
+			s.mutex.Unlock()
+			return false
+		}
+		sort.Slice(s.timers, func(i, j int) bool {
+			if !s.timers[i].at.Equal(s.timers[j].at) {
+				return s.timers[i].at.Before(s.timers[j].at)
+			}
+			return s.timers[i].seq < s.timers[j].seq
+		})
+		timer := s.timers[0]
+		s.timers = s.timers[1:]
+		s.now = timer.at
+		s.tasks = append(s.tasks, timer.task)
+		s.mutex.Unlock()
+		return true
+	}
+	i := s.rand.IntN(len(s.tasks))
+	task := s.tasks[i]
+	s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
+	s.mutex.Unlock()
+	task()
+	return true
+}
+
+// Run steps until there are no tasks or timers left.
+func (s *Simulation) Run() {
+	for s.Step() {
+	}
+}
@@ -0,0 +1,57 @@
+package eventify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func simulatedOrder(seed uint64) []string {
+	sim := NewSimulation(seed)
+	e := NewEventify(WithScheduler(sim))
+	order := []string{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		e.Register("test.event", &asyncListener{handle: func(event Event) error {
+			order = append(order, name)
+			return nil
+		}})
+	}
+	e.Emit(NewEvent("test.event", nil))
+	sim.Run()
+	return order
+}
+
+func TestSimulation_Seed(t *testing.T) {
+	first := simulatedOrder(42)
+	assert.Len(t, first, 5)
+	assert.Equal(t, first, simulatedOrder(42))
+
+	differs := false
+	for seed := uint64(0); seed < 32 && !differs; seed++ {
+		differs = !assert.ObjectsAreEqual(first, simulatedOrder(seed))
+	}
+	assert.True(t, differs)
+}
+
+func TestSimulation_VirtualTime(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithDelivery("error.*", AtLeastOnce), WithScheduler(sim))
+	attempts := 0
+	e.Register("error.event", NewListener(func(event Event) error {
+		attempts++
+		return assert.AnError
+	}))
+	errChan := make(chan error, 1)
+
+	start := time.Now()
+	e.Emit(&mockErrorEvent{errChan: errChan})
+	assert.Equal(t, 0, attempts)
+	sim.Run()
+
+	assert.Equal(t, atLeastOnceAttempts, attempts)
+	assert.ErrorIs(t, <-errChan, assert.AnError)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, sim.Now().After(time.Unix(0, 0)))
+	assert.False(t, sim.Step())
+}
@@ -0,0 +1,156 @@
+package eventify
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// SchemaDriftType is the type of the events emitted by WithSchemaInference when a payload schema drifts.
+const SchemaDriftType = "eventify.schema.drift"
+
+// maxSchemaFields is the number of fields inferred per event type, so payloads keyed by IDs
+// can't grow a schema without bound. Fields beyond it are ignored.
+const maxSchemaFields = 1024
+
+// Schema is the inferred schema of the payloads of an event type: the JSON types seen for every field path,
+// such as "customer.id" or "items[].sku", sorted.
+type Schema map[string][]string
+
+// SchemaDrift is the payload of a SchemaDriftType event.
+type SchemaDrift struct {
+	// Type is the event type whose payload drifted.
+	Type string `json:"type"`
+	// Added are the fields seen for the first time.
+	Added []SchemaField `json:"added,omitempty"`
+	// Changed are the known fields seen with a new type.
+	Changed []SchemaField `json:"changed,omitempty"`
+}
+
+// SchemaField is a field of a SchemaDrift.
+type SchemaField struct {
+	Path string `json:"path"`
+	// Type is the JSON type seen: "string", "number", "boolean", "object" or "array".
+	Type string `json:"type"`
+	// Known are the types seen before for a changed field.
+	Known []string `json:"known,omitempty"`
+}
+
+// Schemas returns the schemas inferred by WithSchemaInference by event type.
+// This method is thread-safe.
+func (e *Eventify) Schemas() map[string]Schema {
+	schemas := map[string]Schema{}
+	if e.schemas == nil {
+		return schemas
+	}
+	e.schemas.mutex.Lock()
+	defer e.schemas.mutex.Unlock()
+	for eventType, schema := range e.schemas.schemas {
+		copied := Schema{}
+		for path, types := range schema {
+			copied[path] = slices.Clone(types)
+		}
+		schemas[eventType] = copied
+	}
+	return schemas
+}
+
+// schemaRegistry holds the inferred schemas.
+type schemaRegistry struct {
+	matchers []*Matcher
+	mutex    sync.Mutex
+	schemas  map[string]Schema
+}
+
+func newSchemaRegistry(matchers []*Matcher) *schemaRegistry {
+	return &schemaRegistry{matchers: matchers, schemas: map[string]Schema{}}
+}
+
+// Observe merges the fields of the payload into the schema of the event type and returns its drift, if any.
+func (r *schemaRegistry) Observe(eventType string, payload []byte) (SchemaDrift, bool) {
+	if eventType == SchemaDriftType || !slices.ContainsFunc(r.matchers, func(m *Matcher) bool { return m.Match(eventType) }) {
+		return SchemaDrift{}, false
+	}
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return SchemaDrift{}, false
+	}
+	if _, ok := value.(map[string]any); !ok {
+		return SchemaDrift{}, false
+	}
+	fields := map[string]string{}
+	collectFields(fields, "", value)
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	schema, known := r.schemas[eventType]
+	if !known {
+		schema = Schema{}
+		r.schemas[eventType] = schema
+	}
+	drift := SchemaDrift{Type: eventType}
+	for _, path := range paths {
+		fieldType := fields[path]
+		types, ok := schema[path]
+		switch {
+		case ok && slices.Contains(types, fieldType):
+			continue
+		case ok:
+			drift.Changed = append(drift.Changed, SchemaField{Path: path, Type: fieldType, Known: slices.Clone(types)})
+		case len(schema) >= maxSchemaFields:
+			continue
+		default:
+			drift.Added = append(drift.Added, SchemaField{Path: path, Type: fieldType})
+		}
+		types = append(types, fieldType)
+		sort.Strings(types)
+		schema[path] = types
+	}
+	return drift, known && (len(drift.Added) > 0 || len(drift.Changed) > 0)
+}
+
+// collectFields records the JSON type of every non-null field of the value by path.
+// Elements of arrays share the path of the array with a "[]" suffix.
+func collectFields(fields map[string]string, path string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if path != "" {
+			fields[path] = "object"
+			path += "."
+		}
+		for key, child := range v {
+			collectFields(fields, path+key, child)
+		}
+	case []any:
+		fields[path] = "array"
+		for _, child := range v {
+			collectFields(fields, path+"[]", child)
+		}
+	case string:
+		fields[path] = "string"
+	case float64:
+		fields[path] = "number"
+	case bool:
+		fields[path] = "boolean"
+	}
+}
+
+// _InferSchema observes the payload of the event and emits its drift, if any.
+func (e *Eventify) _InferSchema(eventType string, event Event) {
+	if e.schemas == nil {
+		return
+	}
+	drift, ok := e.schemas.Observe(eventType, event.Payload())
+	if !ok {
+		return
+	}
+	withEventFields(e.log, event, nil).Debug("eventify schema drift", "event", eventType, "added", len(drift.Added), "changed", len(drift.Changed))
+	payload, _ := json.Marshal(drift)
+	e.Emit(NewEvent(SchemaDriftType, payload))
+}
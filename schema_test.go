@@ -0,0 +1,85 @@
+package eventify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_WithSchemaInference(t *testing.T) {
+	tests := []struct {
+		name     string
+		payloads []string
+		want     []SchemaDrift
+	}{
+		{
+			name:     "same schema",
+			payloads: []string{`{"id":"o-1","total":10}`, `{"id":"o-2","total":12.5}`},
+		},
+		{
+			name:     "missing and null fields",
+			payloads: []string{`{"id":"o-1","note":"gift"}`, `{"id":"o-2"}`, `{"id":"o-3","note":null}`},
+		},
+		{
+			name:     "added field",
+			payloads: []string{`{"id":"o-1"}`, `{"id":"o-2","customer":{"email":"a@b.c"}}`, `{"id":"o-3","customer":{"email":"d@e.f"}}`},
+			want: []SchemaDrift{{
+				Type:  "order.created",
+				Added: []SchemaField{{Path: "customer", Type: "object"}, {Path: "customer.email", Type: "string"}},
+			}},
+		},
+		{
+			name:     "changed type",
+			payloads: []string{`{"id":"o-1","items":[{"qty":1}]}`, `{"id":2,"items":[{"qty":"1"}]}`, `{"id":"o-3","items":[]}`},
+			want: []SchemaDrift{{
+				Type: "order.created",
+				Changed: []SchemaField{
+					{Path: "id", Type: "number", Known: []string{"string"}},
+					{Path: "items[].qty", Type: "string", Known: []string{"number"}},
+				},
+			}},
+		},
+		{
+			name:     "not an object",
+			payloads: []string{`{"id":"o-1"}`, `["o-2"]`, `not json`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEventify(WithSchemaInference("order.*"))
+			var drifts []SchemaDrift
+			e.Register(SchemaDriftType, NewListener(func(event Event) error {
+				var drift SchemaDrift
+				assert.NoError(t, json.Unmarshal(event.Payload(), &drift))
+				drifts = append(drifts, drift)
+				return nil
+			}))
+
+			for _, payload := range tt.payloads {
+				e.Emit(NewEvent("order.created", []byte(payload)))
+				e.Emit(NewEvent("user.created", []byte(`{"id":1}`)))
+			}
+
+			assert.Equal(t, tt.want, drifts)
+		})
+	}
+}
+
+func TestEventify_Schemas(t *testing.T) {
+	e := NewEventify(WithSchemaInference("order.*"))
+	e.EmitBy("order.created", map[string]any{"id": "o-1", "items": []any{map[string]any{"sku": "a"}}})
+	e.EmitBy("order.created", map[string]any{"id": 2})
+	e.EmitBy("user.created", map[string]any{"id": 1})
+
+	schemas := e.Schemas()
+	require.Len(t, schemas, 1)
+	assert.Equal(t, Schema{
+		"id":          {"number", "string"},
+		"items":       {"array"},
+		"items[]":     {"object"},
+		"items[].sku": {"string"},
+	}, schemas["order.created"])
+	assert.Empty(t, New().Schemas())
+}
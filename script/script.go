@@ -0,0 +1,101 @@
+// Package script defines lightweight listeners as expressions.
+//
+// Expressions are written in expr-lang (https://expr-lang.org) and can be loaded from
+// configuration and reloaded at runtime without recompiling the binary. They run with
+// the following variables:
+//   - type: the event type
+//   - payload: the payload decoded as JSON, or the payload string if it is not JSON
+//   - raw: the payload string
+//   - emit(type, payload): emits a new event on the bus and returns true
+//
+// A router and a transformer look like:
+//
+//	payload.amount > 100 && emit("order.large", payload)
+//	emit("user.v2.created", {"id": payload.user_id, "name": payload.name})
+package script
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/payme50rmb/eventify"
+)
+
+type env struct {
+	Type    string                                   `expr:"type"`
+	Payload any                                      `expr:"payload"`
+	Raw     string                                   `expr:"raw"`
+	Emit    func(eventType string, payload any) bool `expr:"emit"`
+}
+
+// Listener is a listener whose logic is an expression.
+type Listener struct {
+	bus     *eventify.Eventify
+	mutex   sync.RWMutex
+	source  string
+	program *vm.Program
+}
+
+// NewListener compiles the source and creates a listener that evaluates it for every event.
+// Events emitted by the expression are emitted on the bus.
+func NewListener(bus *eventify.Eventify, source string) (*Listener, error) {
+	l := &Listener{bus: bus}
+	if err := l.Reload(source); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Reload compiles the source and swaps it in for subsequent events.
+// The current expression is kept if the source fails to compile.
+func (l *Listener) Reload(source string) error {
+	program, err := expr.Compile(source, expr.Env(env{}))
+	if err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.source = source
+	l.program = program
+	return nil
+}
+
+// Source returns the source of the current expression.
+func (l *Listener) Source() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.source
+}
+
+// Handle evaluates the expression for the event.
+// If the expression evaluates to an error, it is returned.
+func (l *Listener) Handle(event eventify.Event) error {
+	l.mutex.RLock()
+	program := l.program
+	l.mutex.RUnlock()
+
+	raw := string(event.Payload())
+	var payload any = raw
+	var decoded any
+	if err := json.Unmarshal(event.Payload(), &decoded); err == nil {
+		payload = decoded
+	}
+	out, err := expr.Run(program, env{
+		Type:    event.Type(),
+		Payload: payload,
+		Raw:     raw,
+		Emit: func(eventType string, payload any) bool {
+			l.bus.EmitBy(eventType, payload)
+			return true
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package script
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener(t *testing.T) {
+	bus := eventify.New()
+	routed := []string{}
+	bus.Register("order.large", eventify.NewListener(func(event eventify.Event) error {
+		routed = append(routed, string(event.Payload()))
+		return nil
+	}))
+
+	listener, err := NewListener(bus, `payload.amount > 100 && emit("order.large", payload)`)
+	require.NoError(t, err)
+	bus.Register("order.created", listener)
+
+	bus.EmitBy("order.created", map[string]any{"amount": 50})
+	bus.EmitBy("order.created", map[string]any{"amount": 150})
+
+	require.Len(t, routed, 1)
+	assert.JSONEq(t, `{"amount":150}`, routed[0])
+}
+
+func TestListener_Reload(t *testing.T) {
+	bus := eventify.New()
+	var got map[string]any
+	bus.Register("user.v2.created", eventify.NewListener(func(event eventify.Event) error {
+		return json.Unmarshal(event.Payload(), &got)
+	}))
+
+	listener, err := NewListener(bus, `false`)
+	require.NoError(t, err)
+
+	assert.Error(t, listener.Reload(`emit(`), "invalid source should not compile")
+	assert.Equal(t, `false`, listener.Source())
+
+	require.NoError(t, listener.Reload(`emit("user.v2.created", {"id": payload.user_id, "type": type})`))
+	require.NoError(t, listener.Handle(eventify.NewEvent("user.created", []byte(`{"user_id":7}`))))
+
+	assert.Equal(t, map[string]any{"id": float64(7), "type": "user.created"}, got)
+}
@@ -0,0 +1,100 @@
+package eventify
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrSealed is the panic value of registry changes made while the instance is sealed,
+// and the error of TryRegister, Session.Subscribe and Session.Unsubscribe.
+var ErrSealed = errors.New("eventify: registry sealed")
+
+// maxCachedMatches is the number of event type combinations whose matched listeners are cached while sealed,
+// so emits with arbitrary types can't grow the cache without bound. Other combinations are matched every time.
+const maxCachedMatches = 4096
+
+// Seal freezes the registry once the application has booted: until Unseal, Register, RegisterWithContext,
+// Unregister, Replace and UnregisterByLabel panic with ErrSealed, which catches listeners accidentally registered
+// at runtime, e.g. inside request handlers, and TryRegister, Session.Subscribe and Session.Unsubscribe return it.
+// Registrations with a declared lifetime still end while sealed: those made with WithExpiry, WithOwner and
+// RegisterWithContext, and the subscriptions of closed sessions. While sealed, the listeners matched by
+// the event types are computed once and cached.
+// This method is thread-safe.
+func (e *Eventify) Seal() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.sealed.Store(true)
+	e.matches.Clear()
+	e.log.Debug("eventify sealed")
+}
+
+// Unseal allows changes to the registry again.
+// This method is thread-safe.
+func (e *Eventify) Unseal() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.sealed.Store(false)
+	e.matches.Clear()
+	e.log.Debug("eventify unsealed")
+}
+
+// Sealed reports whether the registry is sealed.
+func (e *Eventify) Sealed() bool {
+	return e.sealed.Load()
+}
+
+// _CheckSealed panics with ErrSealed if the registry is sealed, for the public registry changes.
+// The caller must hold the write lock.
+func (e *Eventify) _CheckSealed() {
+	if e.sealed.Load() {
+		panic(ErrSealed)
+	}
+}
+
+// matchesKey returns the key of the cached listeners matched by the event types.
+func matchesKey(eventTypes []string) string {
+	return strings.Join(eventTypes, "\x00")
+}
+
+// matchCache caches the listeners matched by the event types while the registry is sealed, up to maxCachedMatches.
+type matchCache struct {
+	mutex   sync.RWMutex
+	entries map[string][]Listener
+}
+
+func (c *matchCache) Load(key string) ([]Listener, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	listeners, ok := c.entries[key]
+	return listeners, ok
+}
+
+// LoadOrStore returns the cached listeners of the key if any, otherwise caches the listeners if there is room
+// and returns them.
+func (c *matchCache) LoadOrStore(key string, listeners []Listener) []Listener {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if cached, ok := c.entries[key]; ok {
+		return cached
+	}
+	if c.entries == nil {
+		c.entries = map[string][]Listener{}
+	}
+	if len(c.entries) < maxCachedMatches {
+		c.entries[key] = listeners
+	}
+	return listeners
+}
+
+func (c *matchCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	clear(c.entries)
+}
+
+func (c *matchCache) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.entries)
+}
@@ -0,0 +1,70 @@
+package eventify
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_Seal(t *testing.T) {
+	e := New()
+	calls := 0
+	e.Register("user.*", NewNamedListener("counter", func(Event) error {
+		calls++
+		return nil
+	}), WithLabels("counter"))
+
+	e.Seal()
+	assert.True(t, e.Sealed())
+	assert.PanicsWithValue(t, ErrSealed, func() { e.Register("user.*", NewListener(nil)) })
+	assert.PanicsWithValue(t, ErrSealed, func() { e.Unregister("user.*") })
+	assert.PanicsWithValue(t, ErrSealed, func() { e.Replace("user.*", "counter", NewListener(nil)) })
+	assert.PanicsWithValue(t, ErrSealed, func() { e.UnregisterByLabel("counter") })
+
+	e.EmitBy("user.created", nil)
+	e.EmitBy("user.created", nil)
+	assert.Equal(t, 2, calls)
+
+	e.PauseByLabel("counter")
+	e.EmitBy("user.created", nil)
+	assert.Equal(t, 2, calls, "pausing should invalidate the cached matches")
+
+	e.ResumeByLabel("counter")
+	e.Unseal()
+	e.Unregister("user.*")
+	e.EmitBy("user.created", nil)
+	assert.Equal(t, 2, calls)
+}
+
+func TestEventify_SealErrors(t *testing.T) {
+	e := New()
+	s := e.NewSession()
+	require.NoError(t, s.Subscribe("order.*"))
+
+	e.Seal()
+	assert.ErrorIs(t, e.TryRegister("user.*", NewListener(nil)), ErrSealed)
+	assert.ErrorIs(t, s.Subscribe("user.*"), ErrSealed)
+	assert.ErrorIs(t, s.Unsubscribe("order.*"), ErrSealed)
+	assert.Len(t, loadAllListeners(e)["order.*"], 1)
+
+	s.Close()
+	assert.Empty(t, loadAllListeners(e)["order.*"], "closing a session is allowed while sealed")
+
+	e.Unseal()
+	assert.ErrorIs(t, e.TryRegister("x[z-a]", NewListener(nil)), ErrInvalidPattern)
+	assert.NoError(t, e.TryRegister("user.*", NewListener(nil)))
+}
+
+func TestEventify_SealedMatchesBounded(t *testing.T) {
+	e := New()
+	e.Register("*", NewListener(func(Event) error { return nil }))
+	e.Seal()
+
+	for i := range maxCachedMatches + 10 {
+		e.EmitBy(fmt.Sprintf("random.%d", i), nil)
+	}
+
+	assert.Equal(t, maxCachedMatches, e.matches.Len())
+}
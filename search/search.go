@@ -0,0 +1,316 @@
+// Package search keeps an Elasticsearch or OpenSearch index in sync with the events of a bus.
+//
+// An Indexer is a listener buffering the event payloads and writing them with the bulk API,
+// in batches, retrying the documents rejected with a transient status:
+//
+//	indexer := search.NewIndexer(search.Config{URL: "http://localhost:9200", Index: search.IndexPerTypeAndDay("events")})
+//	bus.Register("order.*", indexer)
+//	bus.Mount(indexer)
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/payme50rmb/eventify/backoff"
+)
+
+// ErrRejected is returned, wrapping the reason, for documents the cluster rejected permanently
+// or that were still rejected after the last attempt.
+var ErrRejected = errors.New("search: document rejected")
+
+// IndexFunc returns the index of the event, indexed at the time.
+type IndexFunc func(event eventify.Event, at time.Time) string
+
+// IndexPerType indexes the events in one index per type, such as "events-order.created".
+func IndexPerType(prefix string) IndexFunc {
+	return func(event eventify.Event, _ time.Time) string {
+		return prefix + "-" + strings.ToLower(event.Type())
+	}
+}
+
+// IndexPerDay indexes the events in one index per day, such as "events-2024.05.01".
+func IndexPerDay(prefix string) IndexFunc {
+	return func(_ eventify.Event, at time.Time) string {
+		return prefix + "-" + at.UTC().Format("2006.01.02")
+	}
+}
+
+// IndexPerTypeAndDay indexes the events in one index per type and day, such as "events-order.created-2024.05.01".
+func IndexPerTypeAndDay(prefix string) IndexFunc {
+	return func(event eventify.Event, at time.Time) string {
+		return prefix + "-" + strings.ToLower(event.Type()) + "-" + at.UTC().Format("2006.01.02")
+	}
+}
+
+// Config configures an Indexer.
+type Config struct {
+	// URL is the base URL of the cluster, such as "http://localhost:9200".
+	URL string
+	// Header is added to every request, e.g. for authentication.
+	Header http.Header
+	// Client is the HTTP client, http.DefaultClient by default.
+	Client *http.Client
+	// Index names the index of every event, IndexPerDay("events") by default.
+	Index IndexFunc
+	// Document returns the document indexed for the event. By default it is the payload
+	// if it is a JSON object, or {"payload": "<payload>"} otherwise.
+	Document func(event eventify.Event) ([]byte, error)
+	// BatchSize is the number of documents written per bulk request, 500 by default.
+	BatchSize int
+	// FlushInterval is how often Run writes the buffered documents, 1s by default.
+	FlushInterval time.Duration
+	// MaxAttempts is the number of attempts to write a document rejected with a transient status
+	// (429 or 5xx) or failing with a network error, 5 by default.
+	MaxAttempts int
+	// Backoff returns the delays between the attempts, doubling from 100ms up to 10s by default.
+	Backoff func() backoff.Backoff
+	// ErrorHandler is called with the errors of the flushes made by Run and by Handle once a batch is full.
+	// They are dropped by default.
+	ErrorHandler func(err error)
+	// Now returns the time events are indexed at, time.Now by default.
+	Now func() time.Time
+}
+
+// Indexer is a listener indexing the events, and a module writing them periodically.
+type Indexer struct {
+	cfg     Config
+	mutex   sync.Mutex
+	pending []document
+	flush   sync.Mutex
+}
+
+type document struct {
+	index string
+	id    string
+	body  []byte
+}
+
+// NewIndexer creates a new Indexer.
+func NewIndexer(cfg Config) *Indexer {
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Index == nil {
+		cfg.Index = IndexPerDay("events")
+	}
+	if cfg.Document == nil {
+		cfg.Document = Document
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = func() backoff.Backoff {
+			return backoff.Capped(backoff.Exponential(100*time.Millisecond), 10*time.Second)
+		}
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	return &Indexer{cfg: cfg}
+}
+
+// Document returns the payload of the event if it is a JSON object, or {"payload": "<payload>"} otherwise.
+func Document(event eventify.Event) ([]byte, error) {
+	payload := bytes.TrimSpace(event.Payload())
+	if len(payload) > 0 && payload[0] == '{' && json.Valid(payload) {
+		return payload, nil
+	}
+	return json.Marshal(map[string]string{"payload": string(event.Payload())})
+}
+
+// Name implements eventify.Namable.
+func (i *Indexer) Name() string {
+	return "search.indexer"
+}
+
+// Handle buffers the document of the event, compacted to a single line. Events implementing
+// eventify.Identifiable are indexed with their ID, so redeliveries overwrite the document.
+// Once a batch is full it is written, and the errors of the write go to the ErrorHandler:
+// they concern the whole batch, not only the event that filled it. It only returns the errors of the document.
+func (i *Indexer) Handle(event eventify.Event) error {
+	body, err := i.cfg.Document(event)
+	if err != nil {
+		return err
+	}
+	// The bulk API separates documents with newlines, so a pretty-printed document would break the request.
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, body); err != nil {
+		return fmt.Errorf("search: document of %s: %w", event.Type(), err)
+	}
+	doc := document{index: i.cfg.Index(event, i.cfg.Now()), body: compacted.Bytes()}
+	if identifiable, ok := event.(eventify.Identifiable); ok {
+		doc.id = identifiable.ID()
+	}
+	i.mutex.Lock()
+	i.pending = append(i.pending, doc)
+	full := len(i.pending) >= i.cfg.BatchSize
+	i.mutex.Unlock()
+	if full {
+		i.report(i.Flush(context.Background()))
+	}
+	return nil
+}
+
+// Pending returns the number of buffered documents.
+func (i *Indexer) Pending() int {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return len(i.pending)
+}
+
+// Run writes the buffered documents every flush interval until the context is done,
+// then writes the remaining ones.
+func (i *Indexer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(i.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			i.report(i.Flush(context.WithoutCancel(ctx)))
+			return nil
+		case <-ticker.C:
+			i.report(i.Flush(ctx))
+		}
+	}
+}
+
+func (i *Indexer) report(err error) {
+	if err != nil && i.cfg.ErrorHandler != nil {
+		i.cfg.ErrorHandler(err)
+	}
+}
+
+// Flush writes the buffered documents in batches, retrying the transient failures.
+// It returns the joined errors of the documents that could not be written.
+func (i *Indexer) Flush(ctx context.Context) error {
+	i.flush.Lock()
+	defer i.flush.Unlock()
+	i.mutex.Lock()
+	docs := i.pending
+	i.pending = nil
+	i.mutex.Unlock()
+
+	var errs []error
+	for start := 0; start < len(docs); start += i.cfg.BatchSize {
+		end := min(start+i.cfg.BatchSize, len(docs))
+		errs = append(errs, i.write(ctx, docs[start:end]))
+	}
+	return errors.Join(errs...)
+}
+
+// write writes the documents with the bulk API, retrying the transient failures.
+func (i *Indexer) write(ctx context.Context, docs []document) error {
+	delays := i.cfg.Backoff()
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		retry, rejected, reason := i.bulk(ctx, docs)
+		errs = append(errs, rejected...)
+		if len(retry) == 0 {
+			break
+		}
+		if attempt == i.cfg.MaxAttempts {
+			for _, doc := range retry {
+				errs = append(errs, fmt.Errorf("%w: %s: %w after %d attempts", ErrRejected, doc.index, reason, attempt))
+			}
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(append(errs, ctx.Err())...)
+		case <-time.After(delays.Next()):
+		}
+		docs = retry
+	}
+	return errors.Join(errs...)
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// bulk sends one bulk request and returns the documents to retry, the errors of the rejected ones
+// and the reason of the retry.
+func (i *Indexer) bulk(ctx context.Context, docs []document) ([]document, []error, error) {
+	body := &bytes.Buffer{}
+	for _, doc := range docs {
+		action := map[string]map[string]string{"index": {"_index": doc.index}}
+		if doc.id != "" {
+			action["index"]["_id"] = doc.id
+		}
+		line, _ := json.Marshal(action)
+		body.Write(line)
+		body.WriteByte('\n')
+		body.Write(doc.body)
+		body.WriteByte('\n')
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.cfg.URL+"/_bulk", body)
+	if err != nil {
+		return nil, []error{err}, nil
+	}
+	for key, values := range i.cfg.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := i.cfg.Client.Do(req)
+	if err != nil {
+		return docs, nil, err
+	}
+	defer resp.Body.Close()
+	if transient(resp.StatusCode) {
+		return docs, nil, fmt.Errorf("status %s", resp.Status)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, []error{fmt.Errorf("search: bulk: unexpected status %s", resp.Status)}, nil
+	}
+	var result bulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, []error{fmt.Errorf("search: bulk: %w", err)}, nil
+	}
+	if !result.Errors {
+		return nil, nil, nil
+	}
+	var retry []document
+	var reason error
+	var errs []error
+	for n, item := range result.Items {
+		if n >= len(docs) {
+			break
+		}
+		for _, status := range item {
+			switch {
+			case status.Status < 300:
+			case transient(status.Status):
+				retry = append(retry, docs[n])
+				reason = fmt.Errorf("status %d %s", status.Status, status.Error)
+			default:
+				errs = append(errs, fmt.Errorf("%w: %s: %d %s", ErrRejected, docs[n].index, status.Status, status.Error))
+			}
+		}
+	}
+	return retry, errs, reason
+}
+
+func transient(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
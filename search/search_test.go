@@ -0,0 +1,161 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/payme50rmb/eventify/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+type idEvent struct {
+	eventify.Event
+	id string
+}
+
+func (e *idEvent) ID() string { return e.id }
+
+// cluster is a fake bulk API rejecting every document with the status of its "status" field once.
+type cluster struct {
+	mutex    sync.Mutex
+	requests int
+	indexed  map[string][]string
+	rejected map[string]bool
+}
+
+func (c *cluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.requests++
+	scanner := bufio.NewScanner(r.Body)
+	items := []map[string]map[string]int{}
+	errors := false
+	for scanner.Scan() {
+		var action map[string]map[string]string
+		_ = json.Unmarshal(scanner.Bytes(), &action)
+		scanner.Scan()
+		var doc map[string]any
+		_ = json.Unmarshal(scanner.Bytes(), &doc)
+		status := 201
+		if s, ok := doc["status"].(float64); ok && !c.rejected[scanner.Text()] {
+			c.rejected[scanner.Text()] = true
+			status = int(s)
+			errors = true
+		} else {
+			index := action["index"]["_index"]
+			c.indexed[index] = append(c.indexed[index], action["index"]["_id"]+scanner.Text())
+		}
+		items = append(items, map[string]map[string]int{"index": {"status": status}})
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": errors, "items": items})
+}
+
+func TestIndexer(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		event     eventify.Event
+		wantIndex string
+		wantDoc   string
+		wantErr   error
+	}{
+		{"json object", eventify.NewEvent("Order.Created", []byte(`{"id":1}`)), "events-order.created-2024.05.01", `{"id":1}`, nil},
+		{"identified", &idEvent{Event: eventify.NewEvent("order.paid", []byte(`{"id":2}`)), id: "evt-2"}, "events-order.paid-2024.05.01", `evt-2{"id":2}`, nil},
+		{"pretty-printed", eventify.NewEvent("order.shipped", []byte("{\n  \"id\": 3\n}")), "events-order.shipped-2024.05.01", `{"id":3}`, nil},
+		{"raw payload", eventify.NewEvent("order.note", []byte("hello")), "events-order.note-2024.05.01", `{"payload":"hello"}`, nil},
+		{"transient rejection", eventify.NewEvent("order.retried", []byte(`{"status":429}`)), "events-order.retried-2024.05.01", `{"status":429}`, nil},
+		{"permanent rejection", eventify.NewEvent("order.bad", []byte(`{"status":400}`)), "", "", ErrRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cluster{indexed: map[string][]string{}, rejected: map[string]bool{}}
+			server := httptest.NewServer(c)
+			defer server.Close()
+			indexer := NewIndexer(Config{
+				URL:     server.URL + "/",
+				Index:   IndexPerTypeAndDay("events"),
+				Backoff: func() backoff.Backoff { return backoff.Constant(0) },
+				Now:     func() time.Time { return now },
+			})
+
+			assert.NoError(t, indexer.Handle(tt.event))
+			assert.Equal(t, 1, indexer.Pending())
+			err := indexer.Flush(context.Background())
+
+			assert.Equal(t, 0, indexer.Pending())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, c.indexed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, map[string][]string{tt.wantIndex: {tt.wantDoc}}, c.indexed)
+		})
+	}
+}
+
+func TestIndexer_Batching(t *testing.T) {
+	c := &cluster{indexed: map[string][]string{}, rejected: map[string]bool{}}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	bus := eventify.NewEventify()
+	indexer := NewIndexer(Config{URL: server.URL, BatchSize: 2, FlushInterval: time.Hour})
+	bus.Register("order.*", indexer)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- indexer.Run(ctx) }()
+
+	for n := range 5 {
+		bus.EmitBy("order.created", fmt.Sprintf(`{"n":%d}`, n))
+	}
+	assert.Equal(t, 1, indexer.Pending())
+	cancel()
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, 3, c.requests)
+	for _, docs := range c.indexed {
+		assert.Len(t, docs, 5)
+		assert.True(t, strings.HasPrefix(docs[4], `{"n":4}`))
+	}
+}
+
+func TestIndexer_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	indexer := NewIndexer(Config{URL: server.URL, MaxAttempts: 3, Backoff: func() backoff.Backoff { return backoff.Constant(0) }})
+
+	assert.NoError(t, indexer.Handle(eventify.NewEvent("order.created", nil)))
+	err := indexer.Flush(context.Background())
+
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestIndexer_FullBatchErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	var errs []error
+	indexer := NewIndexer(Config{URL: server.URL, BatchSize: 2, ErrorHandler: func(err error) { errs = append(errs, err) }})
+
+	assert.NoError(t, indexer.Handle(eventify.NewEvent("order.created", nil)))
+	assert.NoError(t, indexer.Handle(eventify.NewEvent("order.created", nil)), "the batch error goes to the ErrorHandler")
+
+	assert.Len(t, errs, 1)
+	assert.Zero(t, indexer.Pending())
+
+	raw := NewIndexer(Config{URL: server.URL, Document: func(event eventify.Event) ([]byte, error) { return event.Payload(), nil }})
+	assert.Error(t, raw.Handle(eventify.NewEvent("order.created", []byte(`{"invalid"`))), "custom documents must be JSON")
+}
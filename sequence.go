@@ -0,0 +1,204 @@
+package eventify
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SequenceGapType is the type of the events emitted by gap detectors.
+const SequenceGapType = "eventify.sequence_gap"
+
+// Sequenced is an interface that can be implemented by events to expose their sequence number
+// among the events with the same key, see Keyed.
+type Sequenced interface {
+	Sequence() uint64
+}
+
+// SequenceGap is the payload of a SequenceGapType event.
+type SequenceGap struct {
+	// Key is the key of the events with the gap.
+	Key string `json:"key"`
+	// From and To are the first and last missing sequence numbers.
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// GapOption configures a gap detector.
+type GapOption func(*gapDetector)
+
+// WithGapBuffer holds the events arriving after a gap for up to the timeout, so that the inner listener
+// still receives them in order if the missing ones arrive late. The gap is reported only if it is
+// not filled in time, and the held events are then delivered.
+func WithGapBuffer(timeout time.Duration) GapOption {
+	return func(d *gapDetector) {
+		d.timeout = timeout
+	}
+}
+
+// NewGapDetector creates a listener that checks the sequence numbers of the Sequenced events per key
+// before passing them to the inner listener, and emits a SequenceGapType event with the missing range
+// when some were skipped. Events older than the last one delivered for their key are dropped as duplicates.
+// The key is the event's Key if it implements Keyed, otherwise its type; the first event of a key
+// sets its starting sequence number. Other events are passed through.
+// The inner listener is invoked like a registered listener, with the resources of the bus, and outside the
+// lock of the detector, so it may emit events to it; the events of a key are still delivered in order.
+// A delivery returns the error of its event if it delivered it itself. The errors of the other events
+// it delivers, such as the held events released by it or after a timeout, are reported to their ErrorHandler.
+// The gap detector keeps the name, async and validator markers of the inner listener, and closes it with itself.
+func (e *Eventify) NewGapDetector(inner Listener, opts ...GapOption) Listener {
+	d := &gapDetector{eventify: e, inner: inner, keys: map[string]*sequence{}}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return wrapListener(inner, d.Handle)
+}
+
+type gapDetector struct {
+	eventify *Eventify
+	inner    Listener
+	timeout  time.Duration
+	mutex    sync.Mutex
+	keys     map[string]*sequence
+}
+
+// sequence is the state of a key.
+type sequence struct {
+	next uint64
+	// held are the events after the gap, while buffering.
+	held map[uint64]Event
+	// generation identifies the current buffering, so stale timeouts are ignored.
+	generation int
+	// ready are the events to deliver in order, by the call delivering them if any.
+	ready      []*readyEvent
+	delivering bool
+}
+
+// readyEvent is an event to deliver, compared by identity since events may not be comparable.
+type readyEvent struct {
+	event Event
+}
+
+func (d *gapDetector) Handle(event Event) error {
+	sequenced, ok := event.(Sequenced)
+	if !ok {
+		return d.eventify._Handle(event, d.inner)
+	}
+	key := event.Type()
+	if keyed, ok := event.(Keyed); ok {
+		key = keyed.Key()
+	}
+	n := sequenced.Sequence()
+
+	d.mutex.Lock()
+	s, ok := d.keys[key]
+	if !ok {
+		s = &sequence{next: n}
+		d.keys[key] = s
+	}
+	var own *readyEvent
+	var gaps []SequenceGap
+	switch {
+	case n < s.next || s.held[n] != nil:
+		withEventFields(d.eventify.log, event, d.inner).Debug("eventify duplicate sequence dropped", "key", key, "sequence", n)
+	case n == s.next:
+		own = &readyEvent{event: event}
+		s.ready = append(s.ready, own)
+		s.next++
+		d._Release(s)
+	case d.timeout > 0:
+		if len(s.held) == 0 {
+			s.held = map[uint64]Event{}
+			s.generation++
+			generation := s.generation
+			d.eventify.scheduler.AfterFunc(d.timeout, func() { d._Timeout(key, generation) })
+		}
+		s.held[n] = event
+	default:
+		gaps = append(gaps, SequenceGap{Key: key, From: s.next, To: n - 1})
+		own = &readyEvent{event: event}
+		s.ready = append(s.ready, own)
+		s.next = n + 1
+	}
+	err := d._Deliver(s, own)
+
+	d._Report(gaps)
+	return err
+}
+
+// _Release queues the held events that are next in sequence. The caller must hold the lock.
+func (d *gapDetector) _Release(s *sequence) {
+	for len(s.held) > 0 {
+		event, ok := s.held[s.next]
+		if !ok {
+			break
+		}
+		delete(s.held, s.next)
+		s.ready = append(s.ready, &readyEvent{event: event})
+		s.next++
+	}
+}
+
+// _Deliver delivers the ready events of the key, unless another call is already delivering them,
+// and returns the error of its own event. The caller must hold the lock, which is released.
+func (d *gapDetector) _Deliver(s *sequence, own *readyEvent) error {
+	if s.delivering {
+		d.mutex.Unlock()
+		return nil
+	}
+	s.delivering = true
+	var ownErr error
+	for len(s.ready) > 0 {
+		ready := s.ready[0]
+		s.ready = s.ready[1:]
+		d.mutex.Unlock()
+		err := d.eventify._Handle(ready.event, d.inner)
+		if ready == own {
+			ownErr = err
+		} else if errHandler, ok := ready.event.(ErrorHandler); ok && err != nil {
+			errHandler.ErrorHandler(ready.event, err)
+		}
+		d.mutex.Lock()
+	}
+	s.delivering = false
+	d.mutex.Unlock()
+	return ownErr
+}
+
+// _Timeout reports the gaps of the key and delivers its held events, unless they were released meanwhile.
+func (d *gapDetector) _Timeout(key string, generation int) {
+	d.mutex.Lock()
+	s := d.keys[key]
+	if s.generation != generation || len(s.held) == 0 {
+		d.mutex.Unlock()
+		return
+	}
+	numbers := make([]uint64, 0, len(s.held))
+	for n := range s.held {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	var gaps []SequenceGap
+	for _, n := range numbers {
+		event := s.held[n]
+		delete(s.held, n)
+		if n > s.next {
+			gaps = append(gaps, SequenceGap{Key: key, From: s.next, To: n - 1})
+		}
+		s.ready = append(s.ready, &readyEvent{event: event})
+		s.next = n + 1
+	}
+	d._Deliver(s, nil)
+
+	d._Report(gaps)
+}
+
+// _Report emits the gaps.
+func (d *gapDetector) _Report(gaps []SequenceGap) {
+	for _, gap := range gaps {
+		withEventFields(d.eventify.log, nil, d.inner).Debug("eventify sequence gap", "key", gap.Key, "from", gap.From, "to", gap.To)
+		payload, _ := json.Marshal(gap)
+		d.eventify.Emit(NewEvent(SequenceGapType, payload))
+	}
+}
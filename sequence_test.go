@@ -0,0 +1,100 @@
+package eventify
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sequencedEvent struct {
+	Event
+	key      string
+	sequence uint64
+}
+
+func (s *sequencedEvent) Key() string      { return s.key }
+func (s *sequencedEvent) Sequence() uint64 { return s.sequence }
+
+func TestEventify_NewGapDetector(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []GapOption
+		sequences []uint64
+		want      []uint64
+		wantGaps  []SequenceGap
+	}{
+		{"in order", nil, []uint64{3, 4, 5}, []uint64{3, 4, 5}, nil},
+		{"gap", nil, []uint64{1, 2, 5, 6}, []uint64{1, 2, 5, 6}, []SequenceGap{{Key: "order-1", From: 3, To: 4}}},
+		{"duplicates", nil, []uint64{1, 2, 2, 1, 3}, []uint64{1, 2, 3}, nil},
+		{"buffered gap filled", []GapOption{WithGapBuffer(time.Second)}, []uint64{1, 4, 3, 2, 5}, []uint64{1, 2, 3, 4, 5}, nil},
+		{"buffered gap timed out", []GapOption{WithGapBuffer(time.Second)}, []uint64{1, 5, 3, 3}, []uint64{1, 3, 5}, []SequenceGap{{Key: "order-1", From: 2, To: 2}, {Key: "order-1", From: 4, To: 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulation(1)
+			e := NewEventify(WithScheduler(sim))
+			var got []uint64
+			var gaps []SequenceGap
+			e.Register("order.*", e.NewGapDetector(NewListener(func(event Event) error {
+				got = append(got, event.(Sequenced).Sequence())
+				return nil
+			}), tt.opts...))
+			e.Register(SequenceGapType, NewListener(func(event Event) error {
+				var gap SequenceGap
+				assert.NoError(t, json.Unmarshal(event.Payload(), &gap))
+				gaps = append(gaps, gap)
+				return nil
+			}))
+
+			for _, n := range tt.sequences {
+				e.Emit(&sequencedEvent{Event: NewEvent("order.updated", nil), key: "order-1", sequence: n})
+			}
+			sim.Run()
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantGaps, gaps)
+		})
+	}
+}
+
+func TestEventify_NewGapDetectorPassThrough(t *testing.T) {
+	e := NewEventify()
+	var got []string
+	inner := NewNamedListener("projection", func(event Event) error {
+		got = append(got, event.Type())
+		return fmt.Errorf("handled %s", event.Type())
+	})
+	detector := e.NewGapDetector(inner)
+
+	assert.EqualError(t, detector.Handle(NewEvent("order.created", nil)), "handled order.created")
+	assert.Equal(t, []string{"order.created"}, got)
+	assert.Equal(t, "projection", detector.(Namable).Name())
+}
+
+func TestEventify_NewGapDetectorReentrant(t *testing.T) {
+	e := NewEventify()
+	var got []string
+	e.Register("order.*", e.NewGapDetector(NewListener(func(event Event) error {
+		sequenced := event.(*sequencedEvent)
+		got = append(got, fmt.Sprintf("%s/%d", sequenced.key, sequenced.sequence))
+		if sequenced.sequence == 1 {
+			e.Emit(&sequencedEvent{Event: NewEvent("order.updated", nil), key: sequenced.key, sequence: 2})
+		}
+		return nil
+	})))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Emit(&sequencedEvent{Event: NewEvent("order.updated", nil), key: "order-1", sequence: 1})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("emitting from the inner listener deadlocked")
+	}
+	assert.Equal(t, []string{"order-1/1", "order-1/2"}, got)
+}
@@ -0,0 +1,182 @@
+package eventify
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrSessionClosed is returned when a closed session is used.
+var ErrSessionClosed = errors.New("eventify: session closed")
+
+// defaultSessionBuffer is the default number of events buffered per session.
+const defaultSessionBuffer = 1024
+
+var sessionIDs atomic.Uint64
+
+// Session bundles the subscriptions, event buffer and emit rate limit of one client of a gateway,
+// such as a WebSocket or SSE connection, and tears them down at once when the client disconnects.
+type Session struct {
+	eventify *Eventify
+	id       uint64
+	events   chan SessionEvent
+	done     chan struct{}
+	bucket   *tokenBucket
+	dropped  atomic.Uint64
+
+	mutex  sync.Mutex
+	subs   map[string]Listener
+	closed bool
+}
+
+// SessionEvent is an event delivered to a session with the pattern of the subscription it matched.
+type SessionEvent struct {
+	Pattern string
+	Event   Event
+}
+
+// SessionOption configures a Session.
+type SessionOption func(*Session)
+
+// WithSessionBuffer sets the number of events buffered for the client, 1024 by default.
+// Events arriving while the buffer is full are dropped and counted in Dropped.
+func WithSessionBuffer(size int) SessionOption {
+	return func(s *Session) {
+		s.events = make(chan SessionEvent, size)
+	}
+}
+
+// WithSessionRate limits the events emitted by the client to rate per second on average,
+// with bursts of up to burst events. Emits over the limit return ErrQuotaExceeded.
+func WithSessionRate(rate float64, burst int) SessionOption {
+	return func(s *Session) {
+		s.bucket = newTokenBucket(rate, burst)
+	}
+}
+
+// NewSession creates a new session. It must be closed when the client disconnects.
+func (e *Eventify) NewSession(opts ...SessionOption) *Session {
+	s := &Session{
+		eventify: e,
+		id:       sessionIDs.Add(1),
+		events:   make(chan SessionEvent, defaultSessionBuffer),
+		done:     make(chan struct{}),
+		subs:     map[string]Listener{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ID returns the unique ID of the session.
+func (s *Session) ID() uint64 {
+	return s.id
+}
+
+// Events returns the channel the events matching the subscriptions are delivered on.
+// It is never closed; use Done to stop reading.
+func (s *Session) Events() <-chan SessionEvent {
+	return s.events
+}
+
+// Done returns a channel closed when the session is closed.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (s *Session) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Subscribe delivers the events matching the pattern to the session. Subscribing twice to a pattern
+// has no effect. It returns ErrSessionClosed once the session is closed, ErrSealed while the registry is sealed,
+// or an error wrapping ErrInvalidPattern for a glob that can't be compiled.
+func (s *Session) Subscribe(pattern string) error {
+	if err := ValidatePattern(pattern); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return ErrSessionClosed
+	}
+	if _, ok := s.subs[pattern]; ok {
+		return nil
+	}
+	listener := NewNamedListener(fmt.Sprintf("session:%d:%s", s.id, pattern), func(event Event) error {
+		s._Deliver(SessionEvent{Pattern: pattern, Event: event})
+		return nil
+	})
+	if err := s.eventify._Register(pattern, listener, nil, callSite(2)); err != nil {
+		return err
+	}
+	s.subs[pattern] = listener
+	return nil
+}
+
+// Unsubscribe stops delivering the events matching the pattern to the session.
+// It returns ErrSealed while the registry is sealed; closing the session still removes its subscriptions.
+func (s *Session) Unsubscribe(pattern string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	listener, ok := s.subs[pattern]
+	if !ok {
+		return nil
+	}
+	e := s.eventify
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.sealed.Load() {
+		return ErrSealed
+	}
+	delete(s.subs, pattern)
+	e._RemoveLocked(e._Normalize(pattern), listener, "unsubscribed")
+	return nil
+}
+
+// Emit emits the event on behalf of the client, unless it is over its rate limit.
+// It returns ErrSessionClosed once the session is closed, ErrQuotaExceeded, or the error of TryEmit.
+func (s *Session) Emit(event Event) error {
+	select {
+	case <-s.done:
+		return ErrSessionClosed
+	default:
+	}
+	if !s.bucket.Take() {
+		return ErrQuotaExceeded
+	}
+	return s.eventify.TryEmit(event)
+}
+
+// Close removes all the subscriptions of the session at once and closes Done.
+// Closing a closed session has no effect.
+func (s *Session) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.done)
+	e := s.eventify
+	e.mutex.Lock()
+	for pattern, listener := range s.subs {
+		e._RemoveLocked(e._Normalize(pattern), listener, "session closed")
+	}
+	e.mutex.Unlock()
+	s.subs = map[string]Listener{}
+	e.log.Debug("eventify session closed", "session", s.id, "dropped", s.dropped.Load())
+}
+
+func (s *Session) _Deliver(event SessionEvent) {
+	select {
+	case <-s.done:
+	case s.events <- event:
+	default:
+		s.dropped.Add(1)
+		withEventFields(s.eventify.log, event.Event, nil).Debug("eventify session buffer full, event dropped", "session", s.id, "event", event.Event.Type())
+	}
+}
@@ -0,0 +1,41 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	e := New()
+	s := e.NewSession(WithSessionBuffer(2), WithSessionRate(0, 1))
+
+	require.NoError(t, s.Subscribe("order.*"))
+	require.NoError(t, s.Subscribe("order.*"))
+	require.NoError(t, s.Subscribe("user.*"))
+	assert.ErrorIs(t, s.Subscribe("x[z-a]"), ErrInvalidPattern)
+
+	require.NoError(t, s.Emit(NewEvent("order.created", nil)))
+	assert.ErrorIs(t, s.Emit(NewEvent("order.created", nil)), ErrQuotaExceeded)
+	e.EmitBy("user.created", nil)
+	e.EmitBy("user.deleted", nil)
+
+	got := <-s.Events()
+	assert.Equal(t, "order.*", got.Pattern)
+	assert.Equal(t, "order.created", got.Event.Type())
+	got = <-s.Events()
+	assert.Equal(t, "user.created", got.Event.Type())
+	assert.Equal(t, uint64(1), s.Dropped())
+
+	s.Unsubscribe("user.*")
+	e.EmitBy("user.created", nil)
+	assert.Empty(t, s.Events())
+
+	s.Close()
+	s.Close()
+	<-s.Done()
+	assert.Empty(t, loadAllListeners(e)["order.*"])
+	assert.ErrorIs(t, s.Subscribe("order.*"), ErrSessionClosed)
+	assert.ErrorIs(t, s.Emit(NewEvent("order.created", nil)), ErrSessionClosed)
+}
@@ -0,0 +1,95 @@
+package eventify
+
+// WithShadow registers the listener in shadow mode, to dark-launch a rewritten handler against production traffic:
+// it receives copies of the events, asynchronously, and its invocations, errors and latency are recorded in Stats,
+// but it never affects the emit. Its errors don't reach the event's ErrorHandler, outcome events, EmitTransactional
+// or delivery reports, it is never redelivered, and it doesn't count as a listener of the event.
+// The copies carry the type, a copy of the payload, and the ID, correlation ID and key of the event.
+// Shadows are kept per listener, so only comparable listeners can be shadows.
+func WithShadow() RegisterOption {
+	return func(r *registration) {
+		r.shadow = true
+	}
+}
+
+// _MarkShadow records that the listener is a shadow if the registration says so.
+func (e *Eventify) _MarkShadow(listener Listener, r *registration) {
+	key := statsKey(listener)
+	if key == nil || !r.shadow {
+		return
+	}
+	e.shadows.Store(key, r.compare)
+	e.hasShadows.Store(true)
+	if r.compare {
+		e.hasComparisons.Store(true)
+	}
+}
+
+// _SplitShadows separates the shadow listeners from the others.
+func (e *Eventify) _SplitShadows(listeners []Listener) (primaries, shadows []Listener) {
+	if !e.hasShadows.Load() {
+		return listeners, nil
+	}
+	primaries = make([]Listener, 0, len(listeners))
+	for _, listener := range listeners {
+		if key := statsKey(listener); key != nil {
+			if _, ok := e.shadows.Load(key); ok {
+				shadows = append(shadows, listener)
+				continue
+			}
+		}
+		primaries = append(primaries, listener)
+	}
+	return primaries, shadows
+}
+
+// _DeliverShadows delivers copies of the event to the shadow listeners asynchronously,
+// ignoring their results beyond the statistics and the recordings of the compared ones.
+func (e *Eventify) _DeliverShadows(event Event, shadows []Listener, recordings map[Listener]*recording) {
+	for _, listener := range shadows {
+		shadow := newShadowEvent(event)
+		size := eventSize(shadow)
+		e.memory.async.Add(size)
+		e.scheduler.Go(func() {
+			defer e.memory.async.Add(-size)
+			defer e.inflight.Release(listener)
+			if err := e._HandleRecorded(shadow, listener, recordings[listener]); err != nil {
+				withEventFields(e.log, shadow, listener).Debug("eventify shadow listener failed", "event", shadow.Type(), "error", err)
+			}
+		})
+	}
+}
+
+// shadowEvent is the copy of an event delivered to a shadow listener.
+type shadowEvent struct {
+	event
+	id            string
+	correlationID string
+	key           string
+}
+
+func newShadowEvent(source Event) *shadowEvent {
+	shadow := &shadowEvent{event: event{eventType: source.Type(), payload: PayloadCopy(source)}}
+	if identifiable, ok := source.(Identifiable); ok {
+		shadow.id = identifiable.ID()
+	}
+	if correlatable, ok := source.(Correlatable); ok {
+		shadow.correlationID = correlatable.CorrelationID()
+	}
+	if keyed, ok := source.(Keyed); ok {
+		shadow.key = keyed.Key()
+	}
+	return shadow
+}
+
+func (e *shadowEvent) ID() string {
+	return e.id
+}
+
+func (e *shadowEvent) CorrelationID() string {
+	return e.correlationID
+}
+
+func (e *shadowEvent) Key() string {
+	return e.key
+}
@@ -0,0 +1,91 @@
+package eventify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_WithShadow(t *testing.T) {
+	e := NewEventify(WithOutcomeEvents("order.*"))
+	var outcomes []string
+	e.Register("order.created.*", NewListener(func(event Event) error {
+		outcomes = append(outcomes, event.Type())
+		return nil
+	}))
+	primary := NewNamedListener("primary", func(event Event) error {
+		assert.Equal(t, []byte(`{"id":1}`), event.Payload())
+		return nil
+	})
+	received := make(chan Event, 1)
+	shadow := NewNamedListener("shadow", func(event Event) error {
+		event.Payload()[0] = 'X'
+		received <- event
+		return assert.AnError
+	})
+	e.Register("order.created", primary)
+	e.Register("order.created", shadow, WithShadow())
+	errChan := make(chan error, 1)
+
+	err := e.EmitTransactional(&tracedEvent{Event: NewEvent("order.created", []byte(`{"id":1}`)), id: "evt-1", correlationID: "corr-1"},
+		func() { errChan <- nil },
+		func(err error) { errChan <- err })
+	require.NoError(t, err)
+
+	var copied Event
+	select {
+	case copied = <-received:
+	case <-time.After(time.Second):
+		t.Fatal("shadow not called")
+	}
+	assert.NoError(t, <-errChan, "shadow errors don't fail the emit")
+	assert.Equal(t, "order.created", copied.Type())
+	assert.Equal(t, "evt-1", copied.(Identifiable).ID())
+	assert.Equal(t, "corr-1", copied.(Correlatable).CorrelationID())
+	assert.Equal(t, []string{"order.created.succeeded"}, outcomes)
+	assert.Equal(t, map[DeliveryStatus]uint64{StatusDelivered: 2}, e.DeliveryCounts(), "the primary and the outcome listener")
+	assert.Eventually(t, func() bool {
+		for _, s := range e.Stats() {
+			if s.Listener == "shadow" {
+				return s.Invocations == 1 && s.Errors == 1
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+}
+
+func TestEventify_WithShadowOnly(t *testing.T) {
+	e := New()
+	var unmatched []Event
+	e.OnUnmatched(func(event Event) { unmatched = append(unmatched, event) })
+	handled := make(chan struct{})
+	e.Register("order.created", NewNamedListener("shadow", func(Event) error {
+		close(handled)
+		return nil
+	}), WithShadow())
+
+	assert.Equal(t, 0, e.EmitCount(NewEvent("order.created", nil)))
+	<-handled
+	assert.Len(t, unmatched, 1, "shadows don't count as listeners")
+}
+
+func TestEventify_WithShadowAfterPartialUnregister(t *testing.T) {
+	e := New()
+	received := make(chan Event, 1)
+	shadow := NewNamedListener("shadow", func(event Event) error {
+		received <- event
+		return nil
+	})
+	e.Register("a.*", shadow, WithShadow())
+	e.Register("b.*", shadow, WithShadow())
+
+	e.Unregister("a.*", shadow)
+	assert.Zero(t, e.EmitCount(NewEvent("b.created", nil)), "the listener is still a shadow for b.*")
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("shadow not called")
+	}
+}
@@ -0,0 +1,120 @@
+package eventify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Locker is an interface that represents a lock shared by the instances of a cluster.
+// It can be implemented on top of Redis, etcd or SQL advisory locks.
+type Locker interface {
+	// TryLock acquires or renews the lock for the key for the ttl and reports whether it is held.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Unlock releases the lock for the key if it is held.
+	Unlock(ctx context.Context, key string) error
+}
+
+// NewSingletonListener creates a listener that only handles events on the instance holding the lock for the key,
+// so singleton listeners such as schedulers and projections run once per cluster.
+// The lock is renewed while events keep flowing; if the holder goes away, the lock expires after the ttl
+// and the next instance to see an event takes over. Closing the listener, once it is unregistered or the bus
+// shuts down, releases the lock so another instance takes over right away, then closes the inner listener.
+// The singleton listener keeps the name, async and validator markers of the inner listener, passes it the context
+// of the bus, and initializes it with itself.
+func NewSingletonListener(locker Locker, key string, ttl time.Duration, listener Listener) Listener {
+	l := &singletonListener{
+		locker:   locker,
+		key:      key,
+		ttl:      ttl,
+		listener: listener,
+	}
+	return wrap(&wrapper{inner: listener, handle: l.handle, initInner: true, close: l.Close})
+}
+
+type singletonListener struct {
+	locker   Locker
+	key      string
+	ttl      time.Duration
+	listener Listener
+
+	mutex     sync.Mutex
+	renewedAt time.Time
+}
+
+func (l *singletonListener) handle(ctx context.Context, event Event) error {
+	leader, err := l.isLeader()
+	if err != nil || !leader {
+		return err
+	}
+	return handleCtx(ctx, l.listener, event)
+}
+
+// Close releases the lock.
+func (l *singletonListener) Close(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.renewedAt = time.Time{}
+	return l.locker.Unlock(ctx, l.key)
+}
+
+func (l *singletonListener) isLeader() (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.renewedAt.IsZero() && time.Since(l.renewedAt) < l.ttl/3 {
+		return true, nil
+	}
+	held, err := l.locker.TryLock(context.Background(), l.key, l.ttl)
+	if err != nil || !held {
+		l.renewedAt = time.Time{}
+		return false, err
+	}
+	l.renewedAt = time.Now()
+	return true, nil
+}
+
+// LockTable is an in-process lock table, useful for tests and single-process deployments.
+type LockTable struct {
+	mutex sync.Mutex
+	locks map[string]lockEntry
+}
+
+type lockEntry struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// NewLockTable creates a new, empty lock table.
+func NewLockTable() *LockTable {
+	return &LockTable{locks: map[string]lockEntry{}}
+}
+
+// Locker returns a Locker that takes locks in the table on behalf of the owner.
+func (t *LockTable) Locker(owner string) Locker {
+	return &tableLocker{table: t, owner: owner}
+}
+
+type tableLocker struct {
+	table *LockTable
+	owner string
+}
+
+func (l *tableLocker) TryLock(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	l.table.mutex.Lock()
+	defer l.table.mutex.Unlock()
+	now := time.Now()
+	if entry, ok := l.table.locks[key]; ok && entry.owner != l.owner && now.Before(entry.expiresAt) {
+		return false, nil
+	}
+	l.table.locks[key] = lockEntry{owner: l.owner, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (l *tableLocker) Unlock(_ context.Context, key string) error {
+	l.table.mutex.Lock()
+	defer l.table.mutex.Unlock()
+	if entry, ok := l.table.locks[key]; ok && entry.owner == l.owner {
+		delete(l.table.locks, key)
+	}
+	return nil
+}
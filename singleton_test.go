@@ -0,0 +1,72 @@
+package eventify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingletonListener(t *testing.T) {
+	table := NewLockTable()
+	counts := map[string]int{}
+	newNode := func(name string) Listener {
+		return NewSingletonListener(table.Locker(name), "projection", 30*time.Millisecond, NewListener(func(event Event) error {
+			counts[name]++
+			return nil
+		}))
+	}
+	a, b := newNode("a"), newNode("b")
+	event := NewEvent("order.created", nil)
+
+	require.NoError(t, a.Handle(event))
+	require.NoError(t, b.Handle(event))
+	assert.Equal(t, map[string]int{"a": 1}, counts, "only the leader should handle events")
+
+	time.Sleep(40 * time.Millisecond)
+	require.NoError(t, b.Handle(event))
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, counts, "the lock should fail over once expired")
+}
+
+func TestLockTable_Unlock(t *testing.T) {
+	table := NewLockTable()
+	ctx := context.Background()
+	a, b := table.Locker("a"), table.Locker("b")
+
+	held, err := a.TryLock(ctx, "key", time.Hour)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	require.NoError(t, b.Unlock(ctx, "key"))
+	held, _ = b.TryLock(ctx, "key", time.Hour)
+	assert.False(t, held, "unlock by another owner should be ignored")
+
+	require.NoError(t, a.Unlock(ctx, "key"))
+	held, _ = b.TryLock(ctx, "key", time.Hour)
+	assert.True(t, held)
+}
+
+func TestSingletonListener_ReleasesLockOnClose(t *testing.T) {
+	table := NewLockTable()
+	inner := &lifecycleListener{}
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	singleton := NewSingletonListener(table.Locker("a"), "projection", time.Hour, inner)
+	namable, ok := singleton.(Namable)
+	require.True(t, ok, "singleton listener should keep the inner name")
+	assert.Equal(t, "lifecycle", namable.Name())
+
+	e.Register("order.created", singleton)
+	e.EmitBy("order.created", nil)
+	sim.Run()
+	held, _ := table.Locker("b").TryLock(context.Background(), "projection", time.Hour)
+	require.False(t, held, "the leader should hold the lock")
+
+	e.Unregister("order.created", singleton)
+	sim.Run()
+	assert.Equal(t, 1, inner.closes, "the inner listener should be closed")
+	held, _ = table.Locker("b").TryLock(context.Background(), "projection", time.Hour)
+	assert.True(t, held, "the lock should be released once the listener is closed")
+}
@@ -0,0 +1,146 @@
+package eventify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sinkBufferSize is the number of emit records that can be queued for the sink
+// before new records are dropped.
+const sinkBufferSize = 1024
+
+// EmitRecord is a structured record of a single emit.
+type EmitRecord struct {
+	Time          time.Time `json:"time"`
+	EventID       string    `json:"event_id,omitempty"`
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	Type          string    `json:"type"`
+	Payload       []byte    `json:"payload,omitempty"`
+	Listeners     int       `json:"listeners"`
+}
+
+// Sink is an interface that receives emit records.
+// Sinks are written asynchronously and never block the emit path.
+type Sink interface {
+	Write(record EmitRecord) error
+}
+
+// SinkFunc is a function that implements the Sink interface.
+type SinkFunc func(record EmitRecord) error
+
+// Write calls the function.
+func (f SinkFunc) Write(record EmitRecord) error {
+	return f(record)
+}
+
+// NewJSONSink creates a sink that writes every record as a JSON line to the writer.
+// It can be used with files, stdout or any other io.Writer.
+func NewJSONSink(w io.Writer) Sink {
+	return &jsonSink{enc: json.NewEncoder(w)}
+}
+
+type jsonSink struct {
+	mutex sync.Mutex
+	enc   *json.Encoder
+}
+
+func (s *jsonSink) Write(record EmitRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.enc.Encode(record)
+}
+
+// sinkWriter samples emit records and writes them to a sink in the background, until it is closed.
+type sinkWriter struct {
+	sink       Sink
+	sampleRate float64
+	records    chan EmitRecord
+	log        Log
+	bytes      *atomic.Int64
+	// mutex guards records against sends once closed, and done is closed once they are all written.
+	mutex  sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func newSinkWriter(sink Sink, sampleRate float64, log Log, bytes *atomic.Int64) *sinkWriter {
+	w := &sinkWriter{
+		sink:       sink,
+		sampleRate: sampleRate,
+		records:    make(chan EmitRecord, sinkBufferSize),
+		log:        log,
+		bytes:      bytes,
+		done:       make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *sinkWriter) run() {
+	defer close(w.done)
+	for record := range w.records {
+		w.bytes.Add(-recordSize(record))
+		if err := w.sink.Write(record); err != nil {
+			w.log.Debug("eventify sink write failed", "event", record.Type, "error", err)
+		}
+	}
+}
+
+func recordSize(record EmitRecord) int64 {
+	return int64(len(record.Type) + len(record.Payload) + len(record.EventID) + len(record.CorrelationID))
+}
+
+// Record queues a record for the event if it is sampled.
+// The record is dropped if the sink is not keeping up.
+func (w *sinkWriter) Record(event Event, listeners int) {
+	if w.sampleRate < 1 && rand.Float64() >= w.sampleRate {
+		return
+	}
+	record := EmitRecord{
+		Time:      time.Now(),
+		Type:      event.Type(),
+		Payload:   event.Payload(),
+		Listeners: listeners,
+	}
+	if identifiable, ok := event.(Identifiable); ok {
+		record.EventID = identifiable.ID()
+	}
+	if correlatable, ok := event.(Correlatable); ok {
+		record.CorrelationID = correlatable.CorrelationID()
+	}
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		w.log.Debug("eventify sink closed, record dropped", "event", record.Type)
+		return
+	}
+	size := recordSize(record)
+	w.bytes.Add(size)
+	select {
+	case w.records <- record:
+	default:
+		w.bytes.Add(-size)
+		w.log.Debug("eventify sink buffer full, record dropped", "event", record.Type)
+	}
+}
+
+// Close stops queuing records and waits until the queued ones are written or the context is done.
+func (w *sinkWriter) Close(ctx context.Context) error {
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.records)
+	}
+	w.mutex.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,85 @@
+package eventify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_EventSink(t *testing.T) {
+	t.Run("records every emit", func(t *testing.T) {
+		records := make(chan EmitRecord, 10)
+		e := NewEventify(WithEventSink(SinkFunc(func(record EmitRecord) error {
+			records <- record
+			return nil
+		}), 1))
+		e.Register("sink.event", NewListener(nil))
+
+		e.EmitBy("sink.event", "hello")
+
+		select {
+		case record := <-records:
+			assert.Equal(t, "sink.event", record.Type)
+			assert.Equal(t, []byte("hello"), record.Payload)
+			assert.Equal(t, 1, record.Listeners)
+		case <-time.After(time.Second):
+			t.Fatal("record not written")
+		}
+	})
+
+	t.Run("zero sample rate records nothing", func(t *testing.T) {
+		records := make(chan EmitRecord, 10)
+		e := NewEventify(WithEventSink(SinkFunc(func(record EmitRecord) error {
+			records <- record
+			return nil
+		}), 0))
+
+		e.EmitBy("sink.event", "hello")
+
+		select {
+		case <-records:
+			t.Fatal("record should not be written")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("run writes the queued records before returning", func(t *testing.T) {
+		var written []string
+		e := NewEventify(WithEventSink(SinkFunc(func(record EmitRecord) error {
+			time.Sleep(time.Millisecond)
+			written = append(written, record.Type)
+			return nil
+		}), 1))
+		ctx, cancel := context.WithCancel(context.Background())
+		e.Mount(ModuleFunc(func(context.Context) error {
+			for range 10 {
+				e.EmitBy("sink.event", nil)
+			}
+			cancel()
+			return nil
+		}))
+
+		require.NoError(t, e.Run(ctx))
+		assert.Len(t, written, 10)
+
+		e.EmitBy("sink.event", nil)
+		assert.Len(t, written, 10)
+	})
+}
+
+func TestJSONSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := NewJSONSink(buf)
+
+	require.NoError(t, sink.Write(EmitRecord{Type: "json.event", Listeners: 2}))
+
+	var record EmitRecord
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "json.event", record.Type)
+	assert.Equal(t, 2, record.Listeners)
+}
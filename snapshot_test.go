@@ -0,0 +1,87 @@
+package eventify
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_RegisterDuringEmit(t *testing.T) {
+	e := NewEventify()
+	var late atomic.Int32
+	lateListener := NewListener(func(Event) error {
+		late.Add(1)
+		return nil
+	})
+	var once sync.Once
+	e.Register("test.event", NewListener(func(Event) error {
+		once.Do(func() { e.Register("test.event", lateListener) })
+		return nil
+	}))
+
+	e.Emit(NewEvent("test.event", nil))
+	assert.Equal(t, int32(0), late.Load())
+
+	e.Emit(NewEvent("test.event", nil))
+	assert.Equal(t, int32(1), late.Load())
+}
+
+func TestEventify_UnregisterDuringEmit(t *testing.T) {
+	e := NewEventify()
+	var calls atomic.Int32
+	second := NewNamedListener("second", func(Event) error {
+		calls.Add(1)
+		return nil
+	})
+	e.Register("test.event", NewListener(func(Event) error {
+		e.Unregister("test.event", second)
+		return nil
+	}))
+	e.Register("test.event", second)
+
+	e.Emit(NewEvent("test.event", nil))
+	assert.Equal(t, int32(1), calls.Load())
+
+	e.Emit(NewEvent("test.event", nil))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestEventify_ConcurrentRegisterSnapshot(t *testing.T) {
+	e := NewEventify()
+	const listeners = 50
+	type seen struct{ first, count int }
+	results := make([]*seen, listeners)
+	var registered atomic.Int32
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range listeners {
+			s := &seen{first: -1}
+			results[i] = s
+			e.Register("test.event", NewListener(func(event Event) error {
+				seq := int(event.Payload()[0])
+				if s.first < 0 {
+					s.first = seq
+				}
+				s.count++
+				return nil
+			}))
+			registered.Add(1)
+		}
+	}()
+	emits := 0
+	for ; registered.Load() < listeners && emits < 255; emits++ {
+		e.Emit(NewEvent("test.event", []byte{byte(emits)}))
+	}
+	wg.Wait()
+	e.Emit(NewEvent("test.event", []byte{byte(emits)}))
+
+	for _, s := range results {
+		// A listener receives every event from the first one it saw on, never a partial set.
+		assert.GreaterOrEqual(t, s.first, 0)
+		assert.Equal(t, emits-s.first+1, s.count)
+	}
+}
@@ -0,0 +1,281 @@
+package eventify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"sync"
+)
+
+// ErrSplitListeners is returned by Split when the pattern doesn't have two listeners with the name.
+var ErrSplitListeners = errors.New("eventify: split needs two listeners with the name")
+
+// ErrSplitUnsupported is returned by Split when one of the listeners is a validator or a shadow,
+// whose roles a rollout can't keep.
+var ErrSplitUnsupported = errors.New("eventify: validators and shadows can't be split")
+
+// Types of the events emitted when a rollout is decided.
+const (
+	RolloutPromotedType   = "eventify.rollout.promoted"
+	RolloutRolledBackType = "eventify.rollout.rolled_back"
+)
+
+// RolloutStatus is the state of a rollout, and the payload of its decision events.
+type RolloutStatus struct {
+	Pattern  string `json:"pattern"`
+	Listener string `json:"listener"`
+	// Blue and Green are the invocation counts of the current and new versions.
+	Blue  RolloutCounts `json:"blue"`
+	Green RolloutCounts `json:"green"`
+	// Decided is "", "promoted" or "rolled_back".
+	Decided string `json:"decided,omitempty"`
+}
+
+// RolloutCounts are the invocation counts of a version during a rollout.
+type RolloutCounts struct {
+	Invocations uint64 `json:"invocations"`
+	Errors      uint64 `json:"errors"`
+}
+
+// ErrorRate returns the fraction of invocations that failed.
+func (c RolloutCounts) ErrorRate() float64 {
+	if c.Invocations == 0 {
+		return 0
+	}
+	return float64(c.Errors) / float64(c.Invocations)
+}
+
+// SplitOption configures a rollout started with Split.
+type SplitOption func(*Rollout)
+
+// WithSplitSamples sets how many invocations each version needs before the rollout is decided, 100 by default.
+// A value of 0 or less disables the automatic decision; use Promote and Rollback instead.
+func WithSplitSamples(n int) SplitOption {
+	return func(r *Rollout) {
+		r.samples = n
+	}
+}
+
+// WithSplitTolerance sets by how much the error rate of the new version may exceed the one
+// of the current version and still be promoted, 0.01 by default.
+func WithSplitTolerance(tolerance float64) SplitOption {
+	return func(r *Rollout) {
+		r.tolerance = tolerance
+	}
+}
+
+// Rollout routes the events of a pattern between two versions of a named listener, see Split.
+type Rollout struct {
+	eventify  *Eventify
+	pattern   string
+	blue      Listener
+	green     Listener
+	weight    int
+	total     int
+	samples   int
+	tolerance float64
+	router    Listener
+
+	mutex  sync.Mutex
+	status RolloutStatus
+}
+
+// Split starts a blue/green rollout between the two listeners with the name registered for the pattern:
+// the first one registered is the current version, blue, and the second the new one, green.
+// They are replaced by a single listener with the name, keeping the position and labels of blue,
+// which delivers every event to only one of them, picked at random by weight.
+// Once both versions have been invoked enough, see WithSplitSamples, green is promoted if its error rate
+// is within the tolerance of blue's, see WithSplitTolerance, and rolled back otherwise: the winner replaces
+// the router and a RolloutPromotedType or RolloutRolledBackType event is emitted with the RolloutStatus.
+// The router keeps the async marker of blue, and once removed, closes the versions implementing Closable
+// that are no longer registered while the rollout is undecided.
+// It returns ErrSplitListeners if the pattern doesn't have two listeners with the name,
+// and ErrSplitUnsupported if one of them is a validator or a shadow.
+// This method is thread-safe.
+func (e *Eventify) Split(eventTypePattern string, name string, blueWeight, greenWeight int, opts ...SplitOption) (*Rollout, error) {
+	eventTypePattern = e._Normalize(eventTypePattern)
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e._CheckSealed()
+	ls, _ := e.listeners.Load(eventTypePattern)
+	listeners, _ := ls.([]Listener)
+	var versions []int
+	for i, l := range listeners {
+		if namable, ok := l.(Namable); ok && namable.Name() == name {
+			versions = append(versions, i)
+		}
+	}
+	if len(versions) != 2 {
+		return nil, ErrSplitListeners
+	}
+	blue, green := listeners[versions[0]], listeners[versions[1]]
+	for _, version := range []Listener{blue, green} {
+		_, validator := version.(IsValidator)
+		_, shadow := e.shadows.Load(statsKey(version))
+		if validator || shadow {
+			return nil, ErrSplitUnsupported
+		}
+	}
+	r := &Rollout{
+		eventify:  e,
+		pattern:   eventTypePattern,
+		blue:      blue,
+		green:     green,
+		weight:    max(blueWeight, 0),
+		total:     max(blueWeight, 0) + max(greenWeight, 0),
+		samples:   100,
+		tolerance: 0.01,
+		status:    RolloutStatus{Pattern: eventTypePattern, Listener: name},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.router = &rolloutRouter{rollout: r, name: name}
+	if _, ok := blue.(IsAsync); ok {
+		r.router = &asyncRolloutRouter{rolloutRouter: rolloutRouter{rollout: r, name: name}}
+	}
+
+	routed := append([]Listener{}, listeners...)
+	routed[versions[0]] = r.router
+	routed = append(routed[:versions[1]], routed[versions[1]+1:]...)
+	e.listeners.Store(eventTypePattern, routed)
+	e.matches.Clear()
+	if key := statsKey(r.router); key != nil && e.labels[statsKey(blue)] != nil {
+		e.labels[key] = e.labels[statsKey(blue)]
+	}
+	e.log.Debug("eventify split", "event_type_pattern", eventTypePattern, "name", name, "blue_weight", blueWeight, "green_weight", greenWeight)
+	return r, nil
+}
+
+// Handle delivers the event to one of the versions and decides the rollout once both have been invoked enough.
+func (r *Rollout) Handle(event Event) error {
+	version, counts := r.blue, &r.status.Blue
+	if r.total > 0 && rand.IntN(r.total) >= r.weight {
+		version, counts = r.green, &r.status.Green
+	}
+	r.eventify.inflight.Acquire(version)
+	err := r.eventify._Handle(event, version)
+	r.eventify.inflight.Release(version)
+
+	r.mutex.Lock()
+	counts.Invocations++
+	if err != nil {
+		counts.Errors++
+	}
+	decide := r.samples > 0 && r.status.Decided == "" &&
+		r.status.Blue.Invocations >= uint64(r.samples) && r.status.Green.Invocations >= uint64(r.samples)
+	promote := r.status.Green.ErrorRate() <= r.status.Blue.ErrorRate()+r.tolerance
+	r.mutex.Unlock()
+	if decide {
+		if promote {
+			r.Promote()
+		} else {
+			r.Rollback()
+		}
+	}
+	return err
+}
+
+// Promote ends the rollout in favor of the new version. It reports whether the rollout was still undecided.
+func (r *Rollout) Promote() bool {
+	return r._Decide("promoted", RolloutPromotedType, r.green, r.blue)
+}
+
+// Rollback ends the rollout in favor of the current version. It reports whether the rollout was still undecided.
+func (r *Rollout) Rollback() bool {
+	return r._Decide("rolled_back", RolloutRolledBackType, r.blue, r.green)
+}
+
+// Status returns the state of the rollout.
+func (r *Rollout) Status() RolloutStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.status
+}
+
+// _Decide replaces the router with the winner, forgets the loser and emits the decision.
+func (r *Rollout) _Decide(decided, eventType string, winner, loser Listener) bool {
+	r.mutex.Lock()
+	if r.status.Decided != "" {
+		r.mutex.Unlock()
+		return false
+	}
+	r.status.Decided = decided
+	status := r.status
+	r.mutex.Unlock()
+
+	e := r.eventify
+	e.mutex.Lock()
+	if ls, ok := e.listeners.Load(r.pattern); ok {
+		listeners := append([]Listener{}, ls.([]Listener)...)
+		for i, l := range listeners {
+			if statsKey(l) != nil && l == r.router {
+				listeners[i] = winner
+				e.listeners.Store(r.pattern, listeners)
+				e.matches.Clear()
+				if key := statsKey(winner); key != nil && e.labels[statsKey(r.router)] != nil {
+					e.labels[key] = e.labels[statsKey(r.router)]
+				}
+				e._Forget([]Listener{r.router})
+				if !e._Registered(loser) {
+					e._Forget([]Listener{loser})
+				}
+				break
+			}
+		}
+	}
+	e.mutex.Unlock()
+	e.log.Debug("eventify rollout decided", "event_type_pattern", r.pattern, "name", status.Listener, "decided", decided)
+	payload, _ := json.Marshal(status)
+	e.Emit(NewEvent(eventType, payload))
+	return true
+}
+
+// rolloutRouter is the listener replacing the versions of a rollout until it is decided.
+type rolloutRouter struct {
+	rollout *Rollout
+	name    string
+}
+
+func (l *rolloutRouter) Name() string {
+	return l.name
+}
+
+func (l *rolloutRouter) Handle(event Event) error {
+	return l.rollout.Handle(event)
+}
+
+// Close closes the versions that are no longer registered, unless the rollout was decided,
+// which already forgot the loser.
+func (l *rolloutRouter) Close(ctx context.Context) error {
+	r := l.rollout
+	if r.Status().Decided != "" {
+		return nil
+	}
+	e := r.eventify
+	var errs []error
+	for _, version := range []Listener{r.blue, r.green} {
+		closable, ok := version.(Closable)
+		if !ok || statsKey(version) == nil {
+			continue
+		}
+		e.mutex.RLock()
+		registered := e._Registered(version)
+		e.mutex.RUnlock()
+		if registered {
+			continue
+		}
+		_ = e.inflight.Wait(ctx, version)
+		if err := closable.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		e.inits.Delete(statsKey(version))
+	}
+	return errors.Join(errs...)
+}
+
+type asyncRolloutRouter struct {
+	IAmAsync
+	rolloutRouter
+}
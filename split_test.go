@@ -0,0 +1,157 @@
+package eventify
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_SplitWeights(t *testing.T) {
+	tests := []struct {
+		name                    string
+		blueWeight, greenWeight int
+		wantBlue, wantGreen     int
+	}{
+		{name: "all blue", blueWeight: 100, greenWeight: 0, wantBlue: 50},
+		{name: "all green", blueWeight: 0, greenWeight: 100, wantGreen: 50},
+		{name: "no weights", wantBlue: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New()
+			var blue, green int
+			e.Register("order.created", NewNamedListener("handler", func(Event) error { blue++; return nil }))
+			e.Register("order.created", NewNamedListener("handler", func(Event) error { green++; return nil }))
+			_, err := e.Split("order.created", "handler", tt.blueWeight, tt.greenWeight, WithSplitSamples(0))
+			require.NoError(t, err)
+
+			for i := 0; i < 50; i++ {
+				e.EmitBy("order.created", nil)
+			}
+
+			assert.Equal(t, tt.wantBlue, blue)
+			assert.Equal(t, tt.wantGreen, green)
+		})
+	}
+}
+
+func TestEventify_SplitDecision(t *testing.T) {
+	failure := errors.New("failed")
+	tests := []struct {
+		name        string
+		greenErr    error
+		wantType    string
+		wantDecided string
+		wantWinner  string
+	}{
+		{name: "promoted", wantType: RolloutPromotedType, wantDecided: "promoted", wantWinner: "green"},
+		{name: "rolled back", greenErr: failure, wantType: RolloutRolledBackType, wantDecided: "rolled_back", wantWinner: "blue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New()
+			var handled []string
+			blue := NewNamedListener("handler", func(Event) error { handled = append(handled, "blue"); return nil })
+			green := NewNamedListener("handler", func(Event) error { handled = append(handled, "green"); return tt.greenErr })
+			e.Register("order.created", blue, WithLabels("team=orders"))
+			e.Register("order.created", NewNamedListener("audit", nil))
+			e.Register("order.created", green)
+			var decisions []Event
+			e.Register("eventify.rollout.*", NewListener(func(event Event) error {
+				decisions = append(decisions, event)
+				return nil
+			}))
+			rollout, err := e.Split("order.created", "handler", 50, 50, WithSplitSamples(5))
+			require.NoError(t, err)
+			assert.Equal(t, []Registration{
+				{Pattern: "eventify.rollout.*", Listener: "*eventify.listener"},
+				{Pattern: "order.created", Listener: "audit"},
+				{Pattern: "order.created", Listener: "handler", Labels: []string{"team=orders"}},
+			}, e.Registrations())
+
+			for i := 0; i < 1000 && rollout.Status().Decided == ""; i++ {
+				e.EmitBy("order.created", nil)
+			}
+
+			status := rollout.Status()
+			assert.Equal(t, tt.wantDecided, status.Decided)
+			require.Len(t, decisions, 1)
+			assert.Equal(t, tt.wantType, decisions[0].Type())
+			var payload RolloutStatus
+			require.NoError(t, json.Unmarshal(decisions[0].Payload(), &payload))
+			assert.Equal(t, status, payload)
+
+			winner := map[string]Listener{"blue": blue, "green": green}[tt.wantWinner]
+			assert.Equal(t, []Listener{winner, loadAllListeners(e)["order.created"][1]}, loadAllListeners(e)["order.created"])
+			assert.Equal(t, "audit", listenerLabel(loadAllListeners(e)["order.created"][1]))
+			assert.Equal(t, []string{"team=orders"}, e.Registrations()[2].Labels)
+
+			handled = nil
+			e.EmitBy("order.created", nil)
+			assert.Equal(t, []string{tt.wantWinner}, handled)
+			assert.False(t, rollout.Promote(), "decided rollouts stay decided")
+		})
+	}
+}
+
+func TestEventify_SplitManual(t *testing.T) {
+	e := New()
+	blue := NewNamedListener("handler", nil)
+	green := NewNamedListener("handler", nil)
+	e.Register("order.created", blue)
+	e.Register("order.created", green)
+	rollout, err := e.Split("order.created", "handler", 90, 10)
+	require.NoError(t, err)
+
+	assert.True(t, rollout.Rollback())
+	assert.False(t, rollout.Promote())
+	assert.Equal(t, []Listener{blue}, loadAllListeners(e)["order.created"])
+}
+
+func TestEventify_SplitListeners(t *testing.T) {
+	e := New()
+	e.Register("order.created", NewNamedListener("handler", nil))
+	e.Register("order.created", NewNamedListener("other", nil))
+
+	_, err := e.Split("order.created", "handler", 90, 10)
+	assert.ErrorIs(t, err, ErrSplitListeners)
+	_, err = e.Split("order.updated", "handler", 90, 10)
+	assert.ErrorIs(t, err, ErrSplitListeners)
+}
+
+type namedValidator struct {
+	IAmValidator
+	namedListener
+}
+
+func TestEventify_SplitUnsupported(t *testing.T) {
+	e := New()
+	e.Register("order.created", &namedValidator{namedListener: namedListener{name: "handler", handle: func(Event) error { return nil }}})
+	e.Register("order.created", NewNamedListener("handler", nil))
+	e.Register("order.paid", NewNamedListener("handler", nil))
+	e.Register("order.paid", NewNamedListener("handler", nil), WithShadow())
+
+	_, err := e.Split("order.created", "handler", 90, 10)
+	assert.ErrorIs(t, err, ErrSplitUnsupported)
+	_, err = e.Split("order.paid", "handler", 90, 10)
+	assert.ErrorIs(t, err, ErrSplitUnsupported)
+}
+
+func TestEventify_SplitClosesVersions(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	blue := &lifecycleListener{}
+	green := &lifecycleListener{}
+	e.Register("order.created", blue, WithLabels("orders"))
+	e.Register("order.created", green)
+	_, err := e.Split("order.created", "lifecycle", 50, 50)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, e.UnregisterByLabel("orders"))
+	sim.Run()
+	assert.Equal(t, 1, blue.closes)
+	assert.Equal(t, 1, green.closes)
+}
@@ -0,0 +1,132 @@
+package eventify
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples is the number of most recent latencies kept per listener to compute percentiles.
+const latencySamples = 256
+
+// ListenerStats are the invocation statistics of a listener.
+type ListenerStats struct {
+	// Listener is the name of the listener if it implements Namable, otherwise its type.
+	Listener string
+	// Labels are the labels attached to the listener with WithLabels.
+	Labels         []string
+	Invocations    uint64
+	Errors         uint64
+	MeanLatency    time.Duration
+	P95Latency     time.Duration
+	LastInvocation time.Time
+}
+
+// Stats returns the invocation statistics of every listener invoked since it was registered
+// or since the last ResetStats, sorted by listener.
+func (e *Eventify) Stats() []ListenerStats {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	stats := []ListenerStats{}
+	e.stats.Range(func(key, value any) bool {
+		snapshot := value.(*listenerStats).Snapshot()
+		snapshot.Labels = slices.Clone(e.labels[key])
+		stats = append(stats, snapshot)
+		return true
+	})
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].Listener < stats[j].Listener })
+	return stats
+}
+
+// ResetStats clears the invocation statistics of every listener.
+func (e *Eventify) ResetStats() {
+	e.stats.Clear()
+}
+
+// statsKey returns the key of the listener's statistics, or nil if the listener can't be used as a map key.
+func statsKey(listener Listener) any {
+	if listener == nil || !reflect.ValueOf(listener).Comparable() {
+		return nil
+	}
+	return listener
+}
+
+func listenerLabel(listener Listener) string {
+	if namable, ok := listener.(Namable); ok {
+		return namable.Name()
+	}
+	return fmt.Sprintf("%T", listener)
+}
+
+// _Record records an invocation of the listener.
+func (e *Eventify) _Record(listener Listener, start time.Time, latency time.Duration, err error) {
+	key := statsKey(listener)
+	if key == nil {
+		return
+	}
+	value, ok := e.stats.Load(key)
+	if !ok {
+		value, _ = e.stats.LoadOrStore(key, &listenerStats{label: listenerLabel(listener)})
+	}
+	value.(*listenerStats).Record(start, latency, err)
+}
+
+// _ForgetStats drops the statistics of the listeners.
+func (e *Eventify) _ForgetStats(listeners []Listener) {
+	for _, listener := range listeners {
+		if key := statsKey(listener); key != nil {
+			e.stats.Delete(key)
+		}
+	}
+}
+
+type listenerStats struct {
+	mutex          sync.Mutex
+	label          string
+	invocations    uint64
+	errors         uint64
+	total          time.Duration
+	latencies      []time.Duration
+	next           int
+	lastInvocation time.Time
+}
+
+func (s *listenerStats) Record(start time.Time, latency time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.invocations++
+	if err != nil {
+		s.errors++
+	}
+	s.total += latency
+	s.lastInvocation = start
+	if len(s.latencies) < latencySamples {
+		s.latencies = append(s.latencies, latency)
+	} else {
+		s.latencies[s.next] = latency
+		s.next = (s.next + 1) % latencySamples
+	}
+}
+
+func (s *listenerStats) Snapshot() ListenerStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stats := ListenerStats{
+		Listener:       s.label,
+		Invocations:    s.invocations,
+		Errors:         s.errors,
+		LastInvocation: s.lastInvocation,
+	}
+	if s.invocations > 0 {
+		stats.MeanLatency = s.total / time.Duration(s.invocations)
+	}
+	if len(s.latencies) > 0 {
+		sorted := slices.Clone(s.latencies)
+		slices.Sort(sorted)
+		stats.P95Latency = sorted[(len(sorted)*95+99)/100-1]
+	}
+	return stats
+}
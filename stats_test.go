@@ -0,0 +1,67 @@
+package eventify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_Stats(t *testing.T) {
+	e := New()
+	calls := 0
+	e.Register("job.run", NewNamedListener("flaky", func(event Event) error {
+		calls++
+		if calls%2 == 0 {
+			return assert.AnError
+		}
+		return nil
+	}))
+	e.Register("job.run", NewNamedListener("slow", func(event Event) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}))
+
+	for i := 0; i < 4; i++ {
+		e.EmitBy("job.run", nil)
+	}
+
+	stats := e.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "flaky", stats[0].Listener)
+	assert.Equal(t, uint64(4), stats[0].Invocations)
+	assert.Equal(t, uint64(2), stats[0].Errors)
+	assert.Equal(t, "slow", stats[1].Listener)
+	assert.GreaterOrEqual(t, stats[1].MeanLatency, time.Millisecond)
+	assert.GreaterOrEqual(t, stats[1].P95Latency, time.Millisecond)
+	assert.False(t, stats[1].LastInvocation.IsZero())
+
+	e.ResetStats()
+	assert.Empty(t, e.Stats())
+}
+
+func TestEventify_StatsForgottenOnUnregister(t *testing.T) {
+	e := New()
+	listener := NewNamedListener("temp", nil)
+	e.Register("temp.event", listener)
+	e.EmitBy("temp.event", nil)
+	require.Len(t, e.Stats(), 1)
+
+	e.Unregister("temp.event", listener)
+
+	assert.Empty(t, e.Stats())
+}
+
+func TestEventify_ProfilerLabels(t *testing.T) {
+	e := NewEventify(WithProfilerLabels())
+	e.Register("cpu.event", NewNamedListener("busy", func(event Event) error {
+		return assert.AnError
+	}))
+
+	e.EmitBy("cpu.event", nil)
+
+	stats := e.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(1), stats[0].Errors, "errors should propagate through the labeled call")
+}
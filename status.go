@@ -0,0 +1,99 @@
+package eventify
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// DeliveryStatus is where an event went, for a listener or for the whole emit when it was not delivered at all.
+// Logs, OnDelivery and DeliveryCounts use the same statuses, so operators can break down exactly where events go.
+type DeliveryStatus string
+
+// Delivery statuses.
+const (
+	// StatusDelivered is a listener that handled the event successfully.
+	StatusDelivered DeliveryStatus = "delivered"
+	// StatusFiltered is an emit rejected because of its type, such as with WithStrictTypes.
+	StatusFiltered DeliveryStatus = "filtered"
+	// StatusDroppedOverflow is an emit rejected to protect the bus, such as with WithMemoryLimit,
+	// WithMaxPayloadSize or a producer quota.
+	StatusDroppedOverflow DeliveryStatus = "dropped_overflow"
+	// StatusExpired is an event that expired before it could be delivered, reported by extensions with ReportDelivery.
+	StatusExpired DeliveryStatus = "expired"
+	// StatusVetoed is an emit rejected by a validator.
+	StatusVetoed DeliveryStatus = "vetoed"
+	// StatusFailed is a listener that returned an error, after the redeliveries if any.
+	StatusFailed DeliveryStatus = "failed"
+	// StatusDeadLettered is an event moved to a dead-letter queue, reported by extensions with ReportDelivery.
+	StatusDeadLettered DeliveryStatus = "dead_lettered"
+)
+
+// StatusOf returns the status of a delivery that ended with the error.
+func StatusOf(err error) DeliveryStatus {
+	switch {
+	case err == nil:
+		return StatusDelivered
+	case errors.Is(err, ErrVetoed):
+		return StatusVetoed
+	case errors.Is(err, ErrUnknownEventType):
+		return StatusFiltered
+	case errors.Is(err, ErrMemoryLimitExceeded), errors.Is(err, ErrPayloadTooLarge), errors.Is(err, ErrQuotaExceeded):
+		return StatusDroppedOverflow
+	default:
+		return StatusFailed
+	}
+}
+
+// DeliveryReport is the outcome of the delivery of an event.
+type DeliveryReport struct {
+	Event Event
+	// Listener is the listener the event was delivered to, or nil when the emit was rejected.
+	Listener Listener
+	Status   DeliveryStatus
+	// Err is the error of the listener or of the rejection, if any.
+	Err error
+}
+
+// deliveryCounts counts the delivery reports per status.
+type deliveryCounts struct {
+	counts sync.Map
+}
+
+func (c *deliveryCounts) Add(status DeliveryStatus) {
+	count, ok := c.counts.Load(status)
+	if !ok {
+		count, _ = c.counts.LoadOrStore(status, &atomic.Uint64{})
+	}
+	count.(*atomic.Uint64).Add(1)
+}
+
+// OnDelivery sets the hook invoked with the outcome of every delivery to a listener, once final,
+// and of every rejected emit. It is invoked synchronously and must be fast. A nil hook removes it.
+// This method is thread-safe.
+func (e *Eventify) OnDelivery(hook func(DeliveryReport)) {
+	if hook == nil {
+		e.deliveryHook.Store(nil)
+		return
+	}
+	e.deliveryHook.Store(&hook)
+}
+
+// DeliveryCounts returns the number of deliveries and rejected emits per status.
+func (e *Eventify) DeliveryCounts() map[DeliveryStatus]uint64 {
+	counts := map[DeliveryStatus]uint64{}
+	e.deliveryCounts.counts.Range(func(key, value any) bool {
+		counts[key.(DeliveryStatus)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return counts
+}
+
+// ReportDelivery reports the outcome of a delivery made outside the bus, such as by a transport
+// expiring or dead-lettering events, to OnDelivery and DeliveryCounts.
+func (e *Eventify) ReportDelivery(event Event, listener Listener, status DeliveryStatus, err error) {
+	e.deliveryCounts.Add(status)
+	if hook := e.deliveryHook.Load(); hook != nil {
+		(*hook)(DeliveryReport{Event: event, Listener: listener, Status: status, Err: err})
+	}
+}
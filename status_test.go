@@ -0,0 +1,81 @@
+package eventify
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want DeliveryStatus
+	}{
+		{nil, StatusDelivered},
+		{fmt.Errorf("%w: by listener", ErrVetoed), StatusVetoed},
+		{ErrUnknownEventType, StatusFiltered},
+		{ErrMemoryLimitExceeded, StatusDroppedOverflow},
+		{ErrPayloadTooLarge, StatusDroppedOverflow},
+		{ErrQuotaExceeded, StatusDroppedOverflow},
+		{assert.AnError, StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			assert.Equal(t, tt.want, StatusOf(tt.err))
+		})
+	}
+}
+
+func TestEventify_OnDelivery(t *testing.T) {
+	e := NewEventify(WithStrictTypes("order.created", "order.paid"), WithMaxPayloadSize(4))
+	var mutex sync.Mutex
+	var reports []string
+	e.OnDelivery(func(r DeliveryReport) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		listener := ""
+		if r.Listener != nil {
+			listener = r.Listener.(Namable).Name()
+		}
+		reports = append(reports, fmt.Sprintf("%s %s %s", r.Event.Type(), listener, r.Status))
+	})
+	e.Register("order.*", NewNamedListener("ok", func(Event) error { return nil }))
+	e.Register("order.paid", NewNamedListener("failing", func(Event) error { return assert.AnError }))
+	e.Register("order.*", NewValidator(func(event Event) error {
+		if string(event.Payload()) == "bad" {
+			return assert.AnError
+		}
+		return nil
+	}))
+
+	e.EmitBy("order.created", nil)
+	e.EmitBy("order.paid", nil)
+	e.EmitBy("order.created", "bad")
+	e.EmitBy("order.created", "too large")
+	e.EmitBy("order.unknown", nil)
+	e.ReportDelivery(NewEvent("order.created", nil), nil, StatusDeadLettered, nil)
+
+	assert.ElementsMatch(t, []string{
+		"order.created ok delivered",
+		"order.paid ok delivered",
+		"order.paid failing failed",
+		"order.created  vetoed",
+		"order.created  dropped_overflow",
+		"order.unknown  filtered",
+		"order.created  dead_lettered",
+	}, reports)
+	assert.Equal(t, map[DeliveryStatus]uint64{
+		StatusDelivered:       2,
+		StatusFailed:          1,
+		StatusVetoed:          1,
+		StatusDroppedOverflow: 1,
+		StatusFiltered:        1,
+		StatusDeadLettered:    1,
+	}, e.DeliveryCounts())
+
+	e.OnDelivery(nil)
+	e.EmitBy("order.created", nil)
+	assert.Len(t, reports, 7)
+}
@@ -0,0 +1,17 @@
+package eventify
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownEventType is reported when strict types are enabled and an event's type is not in the catalog.
+var ErrUnknownEventType = errors.New("eventify: unknown event type")
+
+// _CheckType returns ErrUnknownEventType if strict types are enabled and the event type is not in the catalog.
+func (e *Eventify) _CheckType(eventType string) error {
+	if e.catalog == nil || e.catalog[eventType] {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownEventType, eventType)
+}
@@ -0,0 +1,28 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_StrictTypes(t *testing.T) {
+	e := NewEventify(WithStrictTypes("user.created", "User.Deleted"), WithTypeNormalization(nil))
+	var received []string
+	e.Register("user.*", NewListener(func(event Event) error {
+		received = append(received, event.Type())
+		return nil
+	}))
+	unmatched := 0
+	e.OnUnmatched(func(Event) { unmatched++ })
+
+	e.EmitBy("user.created", nil)
+	e.EmitBy("user.deleted", nil)
+	e.EmitBy("user.craeted", nil)
+	errChan := make(chan error, 1)
+	e.Emit(&mockErrorEvent{errChan: errChan})
+
+	assert.Equal(t, []string{"user.created", "user.deleted"}, received)
+	assert.ErrorIs(t, <-errChan, ErrUnknownEventType)
+	assert.Zero(t, unmatched, "rejected events are not reported as unmatched")
+}
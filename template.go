@@ -0,0 +1,68 @@
+package eventify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// Template is an interface that represents a template, such as *text/template.Template or *html/template.Template.
+type Template interface {
+	Execute(w io.Writer, data any) error
+}
+
+// TemplateData is the value templates are executed with.
+type TemplateData struct {
+	// Type is the event type.
+	Type string
+	// ID and CorrelationID are set when the event implements Identifiable or Correlatable.
+	ID            string
+	CorrelationID string
+	// Payload is the raw event payload as a string.
+	Payload string
+	// JSON is the payload decoded as JSON, or nil if the payload is not valid JSON.
+	JSON any
+}
+
+// NewTemplateData creates the template data for the event.
+func NewTemplateData(event Event) TemplateData {
+	data := TemplateData{
+		Type:    event.Type(),
+		Payload: string(event.Payload()),
+	}
+	if identifiable, ok := event.(Identifiable); ok {
+		data.ID = identifiable.ID()
+	}
+	if correlatable, ok := event.(Correlatable); ok {
+		data.CorrelationID = correlatable.CorrelationID()
+	}
+	var decoded any
+	if err := json.Unmarshal(event.Payload(), &decoded); err == nil {
+		data.JSON = decoded
+	}
+	return data
+}
+
+// TemplateFuncs returns functions that are useful in event templates.
+// Add them to a template with Funcs before parsing:
+//   - json encodes a value as JSON, e.g. {{json .JSON.user}}
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"json": func(v any) (string, error) {
+			bz, err := json.Marshal(v)
+			return string(bz), err
+		},
+	}
+}
+
+// NewTemplateListener creates a listener that renders every event with the template
+// and passes the output to the sink.
+func NewTemplateListener(tmpl Template, sink func(event Event, output []byte) error) Listener {
+	return NewListener(func(event Event) error {
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, NewTemplateData(event)); err != nil {
+			return err
+		}
+		return sink(event, buf.Bytes())
+	})
+}
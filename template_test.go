@@ -0,0 +1,30 @@
+package eventify
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateListener(t *testing.T) {
+	tmpl := template.Must(template.New("out").Funcs(TemplateFuncs()).Parse(`{"type":"{{.Type}}","user":{{json .JSON.user}}}`))
+	var output string
+	listener := NewTemplateListener(tmpl, func(event Event, out []byte) error {
+		output = string(out)
+		return nil
+	})
+
+	require.NoError(t, listener.Handle(NewEvent("user.created", []byte(`{"user":{"name":"bob"}}`))))
+
+	assert.JSONEq(t, `{"type":"user.created","user":{"name":"bob"}}`, output)
+}
+
+func TestNewTemplateData(t *testing.T) {
+	data := NewTemplateData(NewEvent("plain.event", []byte("not json")))
+
+	assert.Equal(t, "plain.event", data.Type)
+	assert.Equal(t, "not json", data.Payload)
+	assert.Nil(t, data.JSON)
+}
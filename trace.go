@@ -0,0 +1,134 @@
+package eventify
+
+import (
+	"sync"
+	"time"
+)
+
+// Caused is an interface that can be implemented by events to expose the ID of the event that caused them,
+// so follow-up events can be linked to their cause in a Timeline.
+type Caused interface {
+	CausationID() string
+}
+
+// Span is the record of one listener invocation.
+type Span struct {
+	Listener string        `json:"listener,omitempty"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Timeline is the causal chain of an event: its listener invocations and the follow-up events it caused.
+type Timeline struct {
+	EventID   string     `json:"event_id"`
+	Type      string     `json:"type"`
+	EmittedAt time.Time  `json:"emitted_at"`
+	Spans     []Span     `json:"spans,omitempty"`
+	Children  []Timeline `json:"children,omitempty"`
+}
+
+// Trace returns the timeline of the event with the ID and reports whether it was found.
+// Tracing must be enabled with WithTracing; only events implementing Identifiable are traced,
+// and children are linked through Caused.
+func (e *Eventify) Trace(eventID string) (Timeline, bool) {
+	if e.tracer == nil {
+		return Timeline{}, false
+	}
+	return e.tracer.Timeline(eventID)
+}
+
+type traceRecord struct {
+	eventType string
+	emittedAt time.Time
+	spans     []Span
+	children  []string
+}
+
+// tracer keeps the records of the most recent traced events.
+type tracer struct {
+	mutex    sync.Mutex
+	capacity int
+	records  map[string]*traceRecord
+	order    []string
+}
+
+func newTracer(capacity int) *tracer {
+	return &tracer{capacity: capacity, records: map[string]*traceRecord{}}
+}
+
+func eventID(event Event) string {
+	if identifiable, ok := event.(Identifiable); ok {
+		return identifiable.ID()
+	}
+	return ""
+}
+
+// Emitted records the emit of the event and links it to its cause.
+func (t *tracer) Emitted(event Event) {
+	id := eventID(event)
+	if id == "" {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if _, ok := t.records[id]; !ok {
+		if len(t.order) >= t.capacity {
+			delete(t.records, t.order[0])
+			t.order = t.order[1:]
+		}
+		t.order = append(t.order, id)
+	}
+	t.records[id] = &traceRecord{eventType: event.Type(), emittedAt: time.Now()}
+	if caused, ok := event.(Caused); ok {
+		if parent, ok := t.records[caused.CausationID()]; ok {
+			parent.children = append(parent.children, id)
+		}
+	}
+}
+
+// Handled records a listener invocation for the event.
+func (t *tracer) Handled(event Event, listener Listener, start time.Time, err error) {
+	id := eventID(event)
+	if id == "" {
+		return
+	}
+	span := Span{Start: start, Duration: time.Since(start)}
+	if namable, ok := listener.(Namable); ok {
+		span.Listener = namable.Name()
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if record, ok := t.records[id]; ok {
+		record.spans = append(record.spans, span)
+	}
+}
+
+func (t *tracer) Timeline(id string) (Timeline, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.timeline(id, map[string]bool{})
+}
+
+func (t *tracer) timeline(id string, seen map[string]bool) (Timeline, bool) {
+	record, ok := t.records[id]
+	if !ok || seen[id] {
+		return Timeline{}, false
+	}
+	seen[id] = true
+	timeline := Timeline{
+		EventID:   id,
+		Type:      record.eventType,
+		EmittedAt: record.emittedAt,
+		Spans:     append([]Span{}, record.spans...),
+	}
+	for _, child := range record.children {
+		if childTimeline, ok := t.timeline(child, seen); ok {
+			timeline.Children = append(timeline.Children, childTimeline)
+		}
+	}
+	return timeline, true
+}
@@ -0,0 +1,58 @@
+package eventify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type causedEvent struct {
+	Event
+	id          string
+	causationID string
+}
+
+func (c *causedEvent) ID() string          { return c.id }
+func (c *causedEvent) CausationID() string { return c.causationID }
+
+func TestEventify_Trace(t *testing.T) {
+	e := NewEventify(WithTracing(10))
+	e.Register("order.created", NewNamedListener("reserve", func(event Event) error {
+		e.Emit(&causedEvent{Event: NewEvent("stock.reserved", nil), id: "2", causationID: "1"})
+		return nil
+	}))
+	e.Register("stock.reserved", NewNamedListener("notify", func(event Event) error {
+		return assert.AnError
+	}))
+
+	e.Emit(&causedEvent{Event: NewEvent("order.created", nil), id: "1"})
+
+	timeline, ok := e.Trace("1")
+	require.True(t, ok)
+	assert.Equal(t, "order.created", timeline.Type)
+	require.Len(t, timeline.Spans, 1)
+	assert.Equal(t, "reserve", timeline.Spans[0].Listener)
+	require.Len(t, timeline.Children, 1)
+	assert.Equal(t, "stock.reserved", timeline.Children[0].Type)
+	require.Len(t, timeline.Children[0].Spans, 1)
+	assert.Equal(t, assert.AnError.Error(), timeline.Children[0].Spans[0].Error)
+
+	_, err := json.Marshal(timeline)
+	assert.NoError(t, err)
+}
+
+func TestEventify_TraceCapacity(t *testing.T) {
+	e := NewEventify(WithTracing(1))
+
+	e.Emit(&causedEvent{Event: NewEvent("a", nil), id: "1"})
+	e.Emit(&causedEvent{Event: NewEvent("b", nil), id: "2"})
+
+	_, ok := e.Trace("1")
+	assert.False(t, ok, "oldest record should be evicted")
+	_, ok = e.Trace("2")
+	assert.True(t, ok)
+	_, ok = New().Trace("2")
+	assert.False(t, ok, "tracing is disabled by default")
+}
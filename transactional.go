@@ -0,0 +1,44 @@
+package eventify
+
+import (
+	"errors"
+	"sync"
+)
+
+// EmitTransactional emits the event and blocks until every listener is done, so the caller can
+// coordinate side effects with its subscribers: onAllOK is called if all of them succeeded,
+// onAnyFail with the joined errors to roll the side effects back if any failed or the event was rejected.
+// Listeners run synchronously, as with WithSync; with a delivery guarantee configured with WithDelivery
+// it also waits for the redeliveries. Either callback may be nil.
+// It returns the error passed to onAnyFail, or nil.
+func (e *Eventify) EmitTransactional(event Event, onAllOK func(), onAnyFail func(error)) error {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var errs []error
+	observe := func(listeners int) func(error) {
+		wg.Add(listeners)
+		return func(err error) {
+			defer wg.Done()
+			if err != nil {
+				mutex.Lock()
+				defer mutex.Unlock()
+				errs = append(errs, err)
+			}
+		}
+	}
+	err := e.TryEmit(event, WithSync(), func(c *emitConfig) { c.observe = observe })
+	if err == nil {
+		wg.Wait()
+		err = errors.Join(errs...)
+	}
+	if err != nil {
+		if onAnyFail != nil {
+			onAnyFail(err)
+		}
+		return err
+	}
+	if onAllOK != nil {
+		onAllOK()
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/payme50rmb/eventify/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_EmitTransactional(t *testing.T) {
+	ok := func(Event) error { return nil }
+	fail := func(Event) error { return assert.AnError }
+	tests := []struct {
+		name      string
+		opts      []OptionFunc
+		eventType string
+		handles   []func(Event) error
+		wantErr   error
+	}{
+		{name: "all ok", eventType: "order.created", handles: []func(Event) error{ok, ok}},
+		{name: "no listeners", eventType: "order.created"},
+		{name: "one fails", eventType: "order.created", handles: []func(Event) error{ok, fail}, wantErr: assert.AnError},
+		{name: "rejected", opts: []OptionFunc{WithStrictTypes("order.paid")}, eventType: "order.created", handles: []func(Event) error{ok}, wantErr: ErrUnknownEventType},
+		{
+			name: "redelivered",
+			opts: []OptionFunc{
+				WithDelivery("order.*", AtLeastOnce),
+				WithRetryBackoff(func() backoff.Backoff { return backoff.Constant(0) }),
+			},
+			eventType: "order.created",
+			handles:   []func(Event) error{fail},
+			wantErr:   assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEventify(tt.opts...)
+			for _, handle := range tt.handles {
+				e.Register(tt.eventType, &asyncListener{handle: handle})
+			}
+			committed := false
+			var rolledBack error
+
+			err := e.EmitTransactional(NewEvent(tt.eventType, nil), func() { committed = true }, func(err error) { rolledBack = err })
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.True(t, committed)
+				assert.NoError(t, rolledBack)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.False(t, committed)
+			assert.Equal(t, err, rolledBack)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Package transport micro-batches the events a bus sends to a message broker, so bridges to Kafka, SQS
+// and the like publish them in one request per batch rather than one per event.
+//
+// A Batcher is a listener buffering the events for a Publisher. The first event of a batch opens a window:
+// the batch is published when the window closes, after the flush interval, or as soon as it is full:
+//
+//	batcher := transport.New(transport.PublisherFunc(func(ctx context.Context, events []eventify.Event) error {
+//		return producer.SendBatch(ctx, events)
+//	}), transport.WithFlushInterval(5*time.Millisecond), transport.WithMaxBatch(500))
+//	bus.Register("order.*", batcher)
+//	bus.Mount(batcher)
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+)
+
+// Publisher publishes a batch of events to a broker, in one request where the broker allows it.
+type Publisher interface {
+	Publish(ctx context.Context, events []eventify.Event) error
+}
+
+// PublisherFunc is a function implementing Publisher.
+type PublisherFunc func(ctx context.Context, events []eventify.Event) error
+
+// Publish calls f.
+func (f PublisherFunc) Publish(ctx context.Context, events []eventify.Event) error {
+	return f(ctx, events)
+}
+
+// Batcher is a listener publishing the events in batches, and a module publishing the last batch on shutdown.
+type Batcher struct {
+	publisher     Publisher
+	log           eventify.Log
+	flushInterval time.Duration
+	maxBatch      int
+	errorHandler  func(events []eventify.Event, err error)
+
+	mutex   sync.Mutex
+	pending []eventify.Event
+	window  *time.Timer
+	// generation counts the batches cut, so a window firing after its batch was flushed leaves the next one alone.
+	generation uint64
+	flush      sync.Mutex
+}
+
+// OptionFunc is a function that configures a Batcher.
+type OptionFunc func(*Batcher)
+
+// WithLogger sets the logger for the Batcher.
+func WithLogger(log eventify.Log) OptionFunc {
+	return func(b *Batcher) {
+		b.log = log
+	}
+}
+
+// WithFlushInterval sets how long a batch waits for more events after its first one, 5ms by default.
+func WithFlushInterval(interval time.Duration) OptionFunc {
+	return func(b *Batcher) {
+		b.flushInterval = interval
+	}
+}
+
+// WithMaxBatch sets the number of events published per request, 500 by default.
+// A full batch is published without waiting for the end of its window.
+func WithMaxBatch(size int) OptionFunc {
+	return func(b *Batcher) {
+		b.maxBatch = size
+	}
+}
+
+// WithErrorHandler sets the function called with the batches whose publication failed,
+// e.g. to send them to a dead letter queue. They are logged and dropped by default.
+func WithErrorHandler(handler func(events []eventify.Event, err error)) OptionFunc {
+	return func(b *Batcher) {
+		b.errorHandler = handler
+	}
+}
+
+// New creates a new Batcher publishing to the publisher.
+func New(publisher Publisher, opts ...OptionFunc) *Batcher {
+	b := &Batcher{
+		publisher:     publisher,
+		log:           &eventify.NoLog{},
+		flushInterval: 5 * time.Millisecond,
+		maxBatch:      500,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.maxBatch <= 0 {
+		b.maxBatch = 1
+	}
+	if b.errorHandler == nil {
+		b.errorHandler = func(events []eventify.Event, err error) {
+			b.log.Debug("transport batch dropped", "events", len(events), "error", err)
+		}
+	}
+	return b
+}
+
+// Name implements eventify.Namable.
+func (b *Batcher) Name() string {
+	return "transport.batcher"
+}
+
+// Handle buffers the event, opening a window if it is the first of its batch, and publishes the batch once it is full.
+// It never fails: the failures of a publication concern the whole batch, not only the event that filled it,
+// so they only go to the error handler. Since the bus can't redeliver the batch, set WithErrorHandler to retry
+// or dead-letter the failed batches rather than relying on eventify.AtLeastOnce.
+// The event is kept until it is published, so its payload must not be reused.
+func (b *Batcher) Handle(event eventify.Event) error {
+	b.mutex.Lock()
+	b.pending = append(b.pending, event)
+	if len(b.pending) == 1 {
+		generation := b.generation
+		b.window = time.AfterFunc(b.flushInterval, func() {
+			b.flushWindow(generation)
+		})
+	}
+	full := len(b.pending) >= b.maxBatch
+	b.mutex.Unlock()
+	if full {
+		b.Flush(context.Background())
+	}
+	return nil
+}
+
+// Pending returns the number of buffered events.
+func (b *Batcher) Pending() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.pending)
+}
+
+// Close publishes the buffered events once the Batcher is no longer registered, see eventify.Closable.
+// The bus closes it asynchronously, so eventify.Eventify.UnregisterAndDrain returns before the last batch
+// is published: call Flush after it to wait for the publication.
+func (b *Batcher) Close(ctx context.Context) error {
+	return b.Flush(ctx)
+}
+
+// Run waits until the context is done, then publishes the buffered events.
+func (b *Batcher) Run(ctx context.Context) error {
+	<-ctx.Done()
+	b.Flush(context.WithoutCancel(ctx))
+	return nil
+}
+
+// Flush publishes the buffered events, in batches of the maximum size, in order.
+// The batches that fail are passed to the error handler and their errors returned joined.
+func (b *Batcher) Flush(ctx context.Context) error {
+	// The flush lock is held while cutting the batches so they are published in the order of the events.
+	b.flush.Lock()
+	defer b.flush.Unlock()
+	return b.publish(ctx, b.cut())
+}
+
+// flushWindow publishes the buffered events when the window of the batch of the generation closes,
+// unless that batch was flushed already.
+func (b *Batcher) flushWindow(generation uint64) {
+	b.flush.Lock()
+	defer b.flush.Unlock()
+	b.mutex.Lock()
+	stale := generation != b.generation
+	b.mutex.Unlock()
+	if stale {
+		return
+	}
+	b.publish(context.Background(), b.cut())
+}
+
+// cut takes the buffered events and closes their window.
+func (b *Batcher) cut() []eventify.Event {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	events := b.pending
+	b.pending = nil
+	b.generation++
+	if b.window != nil {
+		b.window.Stop()
+		b.window = nil
+	}
+	return events
+}
+
+func (b *Batcher) publish(ctx context.Context, events []eventify.Event) error {
+	var errs []error
+	for start := 0; start < len(events); start += b.maxBatch {
+		batch := events[start:min(start+b.maxBatch, len(events))]
+		if err := b.publisher.Publish(ctx, batch); err != nil {
+			b.errorHandler(batch, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,160 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/payme50rmb/eventify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a Publisher recording the types of the published batches.
+type recorder struct {
+	mutex   sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (r *recorder) Publish(_ context.Context, events []eventify.Event) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	batch := make([]string, len(events))
+	for i, event := range events {
+		batch[i] = event.Type()
+	}
+	r.batches = append(r.batches, batch)
+	return r.err
+}
+
+func (r *recorder) Batches() [][]string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.batches
+}
+
+func TestBatcher(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []OptionFunc
+		events int
+		want   [][]string
+	}{
+		{
+			name:   "window",
+			opts:   []OptionFunc{WithFlushInterval(20 * time.Millisecond)},
+			events: 3,
+			want:   [][]string{{"e0", "e1", "e2"}},
+		},
+		{
+			name:   "full batches",
+			opts:   []OptionFunc{WithFlushInterval(time.Hour), WithMaxBatch(2)},
+			events: 4,
+			want:   [][]string{{"e0", "e1"}, {"e2", "e3"}},
+		},
+		{
+			name:   "full batch and window",
+			opts:   []OptionFunc{WithFlushInterval(20 * time.Millisecond), WithMaxBatch(2)},
+			events: 3,
+			want:   [][]string{{"e0", "e1"}, {"e2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recorder{}
+			bus := eventify.New()
+			bus.Register("e*", New(publisher, tt.opts...))
+
+			for i := range tt.events {
+				bus.Emit(eventify.NewEvent("e"+string(rune('0'+i)), nil))
+			}
+
+			require.Eventually(t, func() bool { return len(publisher.Batches()) == len(tt.want) }, time.Second, time.Millisecond)
+			assert.Equal(t, tt.want, publisher.Batches())
+		})
+	}
+}
+
+func TestBatcher_StaleWindow(t *testing.T) {
+	publisher := &recorder{}
+	batcher := New(publisher, WithFlushInterval(time.Hour), WithMaxBatch(2))
+
+	require.NoError(t, batcher.Handle(eventify.NewEvent("e0", nil)))
+	require.NoError(t, batcher.Handle(eventify.NewEvent("e1", nil)))
+	require.NoError(t, batcher.Handle(eventify.NewEvent("e2", nil)))
+
+	// The window of the first batch firing after the batch was flushed as full.
+	batcher.flushWindow(0)
+
+	assert.Equal(t, [][]string{{"e0", "e1"}}, publisher.Batches())
+	assert.Equal(t, 1, batcher.Pending(), "the next batch should wait for its own window")
+}
+
+func TestBatcher_Run(t *testing.T) {
+	publisher := &recorder{}
+	batcher := New(publisher, WithFlushInterval(time.Hour))
+	require.NoError(t, batcher.Handle(eventify.NewEvent("a", nil)))
+	require.NoError(t, batcher.Handle(eventify.NewEvent("b", nil)))
+	assert.Equal(t, 2, batcher.Pending())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, batcher.Run(ctx))
+
+	assert.Equal(t, [][]string{{"a", "b"}}, publisher.Batches())
+	assert.Zero(t, batcher.Pending())
+}
+
+func TestBatcher_Errors(t *testing.T) {
+	errBroker := errors.New("broker unavailable")
+	publisher := &recorder{err: errBroker}
+	var mutex sync.Mutex
+	var failed [][]eventify.Event
+	batcher := New(publisher, WithMaxBatch(2), WithFlushInterval(10*time.Millisecond),
+		WithErrorHandler(func(events []eventify.Event, err error) {
+			assert.ErrorIs(t, err, errBroker)
+			mutex.Lock()
+			defer mutex.Unlock()
+			failed = append(failed, events)
+		}))
+
+	require.NoError(t, batcher.Handle(eventify.NewEvent("a", nil)))
+	require.NoError(t, batcher.Handle(eventify.NewEvent("b", nil)), "the error of a full batch only goes to the error handler")
+	require.NoError(t, batcher.Handle(eventify.NewEvent("c", nil)))
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(failed) == 2
+	}, time.Second, time.Millisecond, "the batch of the window is reported")
+	assert.Len(t, failed[0], 2)
+	assert.Len(t, failed[1], 1)
+}
+
+func TestBatcher_UnregisterAndDrain(t *testing.T) {
+	publisher := &recorder{}
+	batcher := New(publisher, WithFlushInterval(time.Hour))
+	bus := eventify.New()
+	bus.Register("order.*", batcher)
+	bus.EmitBy("order.created", nil)
+
+	require.NoError(t, bus.UnregisterAndDrain(context.Background(), "order.*", batcher))
+	require.NoError(t, batcher.Flush(context.Background()))
+
+	assert.Equal(t, [][]string{{"order.created"}}, publisher.Batches())
+}
+
+func TestBatcher_ClosedWhenUnregistered(t *testing.T) {
+	publisher := &recorder{}
+	batcher := New(publisher, WithFlushInterval(time.Hour))
+	bus := eventify.New()
+	bus.Register("order.*", batcher)
+	bus.EmitBy("order.created", nil)
+
+	bus.Unregister("order.*", batcher)
+
+	require.Eventually(t, func() bool { return len(publisher.Batches()) == 1 }, time.Second, time.Millisecond)
+}
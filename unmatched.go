@@ -0,0 +1,21 @@
+package eventify
+
+// OnUnmatched sets the handler invoked synchronously with every emitted event that matched no listener,
+// so dead emits such as typos or retired consumers can be detected. A nil handler removes it.
+// Rejected events are reported to their ErrorHandler instead.
+// This method is thread-safe.
+func (e *Eventify) OnUnmatched(handler func(Event)) {
+	if handler == nil {
+		e.unmatched.Store(nil)
+		return
+	}
+	e.unmatched.Store(&handler)
+}
+
+// _Unmatched invokes the unmatched handler, if any.
+func (e *Eventify) _Unmatched(event Event) {
+	withEventFields(e.log, event, nil).Debug("eventify unmatched", "event", event.Type())
+	if handler := e.unmatched.Load(); handler != nil {
+		(*handler)(event)
+	}
+}
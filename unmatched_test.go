@@ -0,0 +1,26 @@
+package eventify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_OnUnmatched(t *testing.T) {
+	e := New()
+	e.Register("user.*", NewListener(func(event Event) error { return nil }))
+	var unmatched []string
+	e.OnUnmatched(func(event Event) {
+		unmatched = append(unmatched, event.Type())
+	})
+
+	e.EmitBy("user.created", nil)
+	e.EmitBy("usr.created", nil)
+	e.EmitBy("order.created", nil)
+
+	assert.Equal(t, []string{"usr.created", "order.created"}, unmatched)
+
+	e.OnUnmatched(nil)
+	e.EmitBy("usr.deleted", nil)
+	assert.Len(t, unmatched, 2)
+}
@@ -0,0 +1,72 @@
+package eventify
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVetoed is returned when a validator rejects an event.
+var ErrVetoed = errors.New("eventify: event vetoed")
+
+// IsValidator is an interface that can be implemented by listeners to run as validators:
+// validators run synchronously before any other listener of an event, and if one of them returns an error
+// the event is rejected with ErrVetoed and no other listener runs. They suit invariant checks and policy gates.
+type IsValidator interface {
+	isValidator()
+}
+
+// IAmValidator is a struct that implements the IsValidator interface.
+type IAmValidator struct {
+}
+
+func (IAmValidator) isValidator() {}
+
+// NewValidator creates a validator with the specified validate function.
+func NewValidator(validate func(event Event) error) Listener {
+	return &validator{validate: validate}
+}
+
+type validator struct {
+	IAmValidator
+	validate func(event Event) error
+}
+
+func (v *validator) Handle(event Event) error {
+	return v.validate(event)
+}
+
+// _Validate runs the validators among the listeners and returns the other listeners,
+// or the error wrapping ErrVetoed of the first validator rejecting the event.
+// On rejection, the deliveries of the other listeners are released.
+func (e *Eventify) _Validate(event Event, listeners []Listener) ([]Listener, error) {
+	var validators, others []Listener
+	for _, listener := range listeners {
+		if _, ok := listener.(IsValidator); ok {
+			validators = append(validators, listener)
+		} else {
+			others = append(others, listener)
+		}
+	}
+	if len(validators) == 0 {
+		return listeners, nil
+	}
+	var veto error
+	for _, validator := range validators {
+		if veto == nil {
+			if err := e._Handle(event, validator); err != nil {
+				veto = err
+				if !errors.Is(err, ErrVetoed) {
+					veto = fmt.Errorf("%w by %s: %w", ErrVetoed, listenerLabel(validator), err)
+				}
+			}
+		}
+		e.inflight.Release(validator)
+	}
+	if veto != nil {
+		for _, listener := range others {
+			e.inflight.Release(listener)
+		}
+		return nil, veto
+	}
+	return others, nil
+}
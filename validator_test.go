@@ -0,0 +1,36 @@
+package eventify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventify_Validators(t *testing.T) {
+	e := New()
+	var received []string
+	e.Register("order.*", NewListener(func(event Event) error {
+		received = append(received, string(event.Payload()))
+		return nil
+	}))
+	e.Register("order.*", NewValidator(func(event Event) error {
+		if len(event.Payload()) == 0 {
+			return errors.New("payload is required")
+		}
+		return nil
+	}))
+
+	require.NoError(t, e.TryEmitBy("order.created", "42"))
+	err := e.TryEmitBy("order.created", nil)
+
+	assert.ErrorIs(t, err, ErrVetoed)
+	assert.ErrorContains(t, err, "payload is required")
+	assert.Equal(t, []string{"42"}, received)
+
+	errChan := make(chan error, 1)
+	e.Register("error.event", NewValidator(func(Event) error { return ErrVetoed }))
+	e.Emit(&mockErrorEvent{errChan: errChan})
+	assert.Equal(t, ErrVetoed, <-errChan)
+}
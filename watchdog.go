@@ -0,0 +1,114 @@
+package eventify
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// MissingEventType is the type of the events emitted by ExpectEvery.
+const MissingEventType = "eventify.missing_event"
+
+// MissingEvent is the payload of a MissingEventType event.
+type MissingEvent struct {
+	// Pattern is the pattern passed to ExpectEvery.
+	Pattern string `json:"pattern"`
+	// Key is the key of the silent events, see ExpectEvery.
+	Key string `json:"key"`
+	// LastSeen is when the last event with the key was emitted.
+	LastSeen time.Time `json:"last_seen"`
+	// Window is the window passed to ExpectEvery.
+	Window time.Duration `json:"window"`
+}
+
+// clock is implemented by schedulers with their own time, such as Simulation.
+type clock interface {
+	Now() time.Time
+}
+
+// _Now returns the time of the scheduler.
+func (e *Eventify) _Now() time.Time {
+	if c, ok := e.scheduler.(clock); ok {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// ExpectEvery watches the events whose type matches the pattern for liveness: once no event with a key
+// was emitted for the window, it emits a MissingEventType event once, until the key is seen again.
+// The key of an event is its Key if it implements Keyed, its type otherwise, so one pattern
+// such as "worker.heartbeat.*" watches every worker. Keys are watched from their first event.
+// The returned function stops watching.
+func (e *Eventify) ExpectEvery(pattern string, window time.Duration) (stop func()) {
+	w := &watchdog{eventify: e, pattern: pattern, window: window, keys: map[string]*watchedKey{}}
+	listener := NewNamedListener("eventify.watchdog", w.Seen)
+	e.Register(pattern, listener)
+	return func() {
+		e._Remove(e._Normalize(pattern), listener, "watchdog stopped")
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		w.stopped = true
+	}
+}
+
+// watchdog tracks the last time every key of a pattern was seen.
+type watchdog struct {
+	eventify *Eventify
+	pattern  string
+	window   time.Duration
+	mutex    sync.Mutex
+	keys     map[string]*watchedKey
+	stopped  bool
+}
+
+type watchedKey struct {
+	last time.Time
+	// scheduled reports whether a check of the key is scheduled.
+	scheduled bool
+}
+
+// Seen records the event and schedules the check of its key.
+func (w *watchdog) Seen(event Event) error {
+	if event.Type() == MissingEventType {
+		return nil
+	}
+	key := event.Type()
+	if keyed, ok := event.(Keyed); ok {
+		key = keyed.Key()
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	k, ok := w.keys[key]
+	if !ok {
+		k = &watchedKey{}
+		w.keys[key] = k
+	}
+	k.last = w.eventify._Now()
+	if !k.scheduled {
+		k.scheduled = true
+		w.eventify.scheduler.AfterFunc(w.window, func() { w._Check(key) })
+	}
+	return nil
+}
+
+// _Check emits the missing event of the key if it was silent for the window, or checks it again later.
+func (w *watchdog) _Check(key string) {
+	w.mutex.Lock()
+	if w.stopped {
+		w.mutex.Unlock()
+		return
+	}
+	k := w.keys[key]
+	silence := w.eventify._Now().Sub(k.last)
+	if silence < w.window {
+		w.eventify.scheduler.AfterFunc(w.window-silence, func() { w._Check(key) })
+		w.mutex.Unlock()
+		return
+	}
+	k.scheduled = false
+	missing := MissingEvent{Pattern: w.pattern, Key: key, LastSeen: k.last, Window: w.window}
+	w.mutex.Unlock()
+
+	payload, _ := json.Marshal(missing)
+	w.eventify.Emit(NewEvent(MissingEventType, payload))
+}
@@ -0,0 +1,56 @@
+package eventify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventify_ExpectEvery(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	start := sim.Now()
+	var missing []MissingEvent
+	e.Register(MissingEventType, NewListener(func(event Event) error {
+		var m MissingEvent
+		assert.NoError(t, json.Unmarshal(event.Payload(), &m))
+		m.LastSeen = m.LastSeen.UTC()
+		missing = append(missing, m)
+		return nil
+	}))
+	e.ExpectEvery("worker.heartbeat.*", 30*time.Second)
+
+	e.EmitBy("worker.heartbeat.a", nil)
+	e.EmitBy("worker.heartbeat.b", nil)
+	e.Emit(&keyedEvent{Event: NewEvent("worker.heartbeat.c", nil), key: "worker-c"})
+	sim.AfterFunc(20*time.Second, func() { e.EmitBy("worker.heartbeat.a", nil) })
+	sim.AfterFunc(45*time.Second, func() { e.EmitBy("worker.heartbeat.a", nil) })
+	sim.Run()
+
+	assert.Equal(t, []MissingEvent{
+		{Pattern: "worker.heartbeat.*", Key: "worker.heartbeat.b", LastSeen: start.UTC(), Window: 30 * time.Second},
+		{Pattern: "worker.heartbeat.*", Key: "worker-c", LastSeen: start.UTC(), Window: 30 * time.Second},
+		{Pattern: "worker.heartbeat.*", Key: "worker.heartbeat.a", LastSeen: start.Add(45 * time.Second).UTC(), Window: 30 * time.Second},
+	}, missing)
+	assert.Equal(t, start.Add(75*time.Second), sim.Now())
+}
+
+func TestEventify_ExpectEveryStop(t *testing.T) {
+	sim := NewSimulation(1)
+	e := NewEventify(WithScheduler(sim))
+	missing := 0
+	e.Register(MissingEventType, NewListener(func(Event) error {
+		missing++
+		return nil
+	}))
+	stop := e.ExpectEvery("worker.heartbeat", time.Second)
+
+	e.Emit(&keyedEvent{Event: NewEvent("worker.heartbeat", nil), key: "worker-1"})
+	stop()
+	e.EmitBy("worker.heartbeat", nil)
+	sim.Run()
+
+	assert.Equal(t, 0, missing)
+}